@@ -7,4 +7,5 @@ toolchain go1.24.2
 require (
 	github.com/google/uuid v1.6.0
 	github.com/mattn/go-sqlite3 v1.14.22
+	golang.org/x/crypto v0.41.0
 )
@@ -0,0 +1,73 @@
+package access
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+type contextKey string
+
+const profileKey contextKey = "profile"
+
+// Returns a copy of the context that carries the profile the request is made
+// on behalf of.
+func WithProfile(ctx context.Context, profile *types.Profile) context.Context {
+	return context.WithValue(ctx, profileKey, profile)
+}
+
+// Returns the profile the request is made on behalf of, or nil if the request
+// is not scoped to a profile.
+func Profile(r *http.Request) *types.Profile {
+	profile, _ := r.Context().Value(profileKey).(*types.Profile)
+	return profile
+}
+
+// Returns the id that viewing activity, such as playback progress, is kept
+// under: the selected profile, or the user when no profile is selected.
+func Viewer(r *http.Request) string {
+	if profile := Profile(r); profile != nil {
+		return profile.Id
+	}
+
+	if user := User(r); user != nil {
+		return user.Id
+	}
+
+	return ""
+}
+
+// Builds an SQL condition that only lets through content whose rating, found in
+// ratingColumn, the requesting profile is allowed to watch. Unrated content is
+// treated as unsuitable for profiles that have a limit. Returns the condition
+// along with its arguments.
+func RatingCondition(r *http.Request, ratingColumn string) (string, []any) {
+	var profile *types.Profile = Profile(r)
+
+	if profile == nil || profile.MaxContentRating == nil {
+		return "1 = 1", nil
+	}
+
+	return ratingColumn + " IN (SELECT id FROM content_ratings WHERE rank <= ?)", []any{profile.MaxContentRating.Rank}
+}
+
+// The rating column expression of an episode, which falls back to the rating of
+// its show when the episode itself is unrated. Expects the episodes table to not
+// be aliased.
+const EpisodeRating string = "COALESCE(episodes.content_rating_id, (SELECT shows.content_rating_id FROM shows WHERE shows.id = episodes.parent_id))"
+
+// Builds an SQL condition that only lets through episodes the requesting profile
+// is allowed to watch, which requires both the episode and its show to be within
+// the profile's limit. Expects the episodes table to not be aliased.
+func EpisodeCondition(r *http.Request) (string, []any) {
+	episodeCondition, episodeArguments := RatingCondition(r, EpisodeRating)
+	showCondition, showArguments := RatingCondition(r, "shows.content_rating_id")
+
+	if episodeArguments == nil && showArguments == nil {
+		return "1 = 1", nil
+	}
+
+	return "(" + episodeCondition + " AND episodes.parent_id IN (SELECT shows.id FROM shows WHERE " + showCondition + "))",
+		append(episodeArguments, showArguments...)
+}
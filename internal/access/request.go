@@ -0,0 +1,21 @@
+package access
+
+import (
+	"context"
+	"net/http"
+)
+
+const requestKey contextKey = "request"
+
+// Returns a copy of the context that carries the id the request is logged and
+// reported under.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestKey, id)
+}
+
+// Returns the id the request is logged and reported under, or an empty string
+// if it was not given one.
+func RequestId(r *http.Request) string {
+	id, _ := r.Context().Value(requestKey).(string)
+	return id
+}
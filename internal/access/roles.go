@@ -0,0 +1,127 @@
+package access
+
+import (
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// An action that a role may or may not be allowed to take.
+type Permission string
+
+const (
+	// Browsing the library and streaming from it.
+	Browse Permission = "browse"
+	// Keeping viewing profiles.
+	ManageProfiles Permission = "manage_profiles"
+	// Uploading content and editing everything that describes it, such as
+	// covers, tags, credits and content ratings.
+	EditLibrary Permission = "edit_library"
+	// Removing content from the library.
+	DeleteLibrary Permission = "delete_library"
+	// Creating, editing and removing users and their roles.
+	ManageUsers Permission = "manage_users"
+	// Changing server wide settings, such as the two-factor policy.
+	ManageServer Permission = "manage_server"
+	// Hiding and removing the reviews of other users.
+	ModerateReviews Permission = "moderate_reviews"
+)
+
+// Permissions granted to each role. Roles are ordered from most to least
+// privileged.
+var rolePermissions = []struct {
+	role        string
+	permissions []Permission
+}{
+	{"admin", []Permission{Browse, ManageProfiles, EditLibrary, DeleteLibrary, ManageUsers, ManageServer, ModerateReviews}},
+	{"uploader", []Permission{Browse, ManageProfiles, EditLibrary}},
+	{"viewer", []Permission{Browse, ManageProfiles}},
+	{"guest", []Permission{Browse}},
+}
+
+// Permissions that API tokens of each scope are limited to. A token never grants
+// more than the role of its user does.
+var scopePermissions = map[string][]Permission{
+	"read":   {Browse},
+	"upload": {Browse, EditLibrary},
+	"admin":  {Browse, ManageProfiles, EditLibrary, DeleteLibrary, ManageUsers, ManageServer, ModerateReviews},
+}
+
+// Reports whether the role exists.
+func IsRole(role string) bool {
+	for _, entry := range rolePermissions {
+		if entry.role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reports whether the API token scope exists.
+func IsScope(scope string) bool {
+	return scopePermissions[scope] != nil
+}
+
+// Returns every role along with the permissions granted to it, from most to
+// least privileged.
+func Roles() []types.Role {
+	var roles []types.Role = []types.Role{}
+
+	for _, entry := range rolePermissions {
+		var role types.Role = types.Role{Name: entry.role, Permissions: []string{}}
+
+		for _, permission := range entry.permissions {
+			role.Permissions = append(role.Permissions, string(permission))
+		}
+
+		roles = append(roles, role)
+	}
+
+	return roles
+}
+
+// Reports whether the user's role grants the permission. Nil users have no
+// permissions at all.
+func Can(user *types.User, permission Permission) bool {
+	if user == nil {
+		return false
+	}
+
+	for _, entry := range rolePermissions {
+		if entry.role != user.Role {
+			continue
+		}
+
+		for _, granted := range entry.permissions {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Reports whether the request may take an action requiring the permission. The
+// role of the authenticated user must grant it and, for requests authenticated
+// with an API token, so must the scope of the token.
+func Allowed(r *http.Request, permission Permission) bool {
+	var scope string = Scope(r)
+
+	if !Can(User(r), permission) {
+		return false
+	}
+
+	if scope == "" {
+		return true
+	}
+
+	for _, granted := range scopePermissions[scope] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package access
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+const userKey contextKey = "user"
+const scopeKey contextKey = "scope"
+
+// Name of the cookie that carries the session token.
+const SessionCookie string = "watchify_session"
+
+// Returns a copy of the context that carries the user the request was
+// authenticated as.
+func WithUser(ctx context.Context, user *types.User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// Returns the user the request was authenticated as, or nil if the request was
+// not authenticated.
+func User(r *http.Request) *types.User {
+	user, _ := r.Context().Value(userKey).(*types.User)
+	return user
+}
+
+// Returns a copy of the context that carries the scope of the API token the
+// request was authenticated with.
+func WithScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, scopeKey, scope)
+}
+
+// Returns the scope of the API token the request was authenticated with, or an
+// empty string if it was authenticated with a session.
+func Scope(r *http.Request) string {
+	scope, _ := r.Context().Value(scopeKey).(string)
+	return scope
+}
+
+// Returns the API token sent along with the request in the Authorization
+// header, or an empty string if there is none.
+func BearerToken(r *http.Request) string {
+	var header string = r.Header.Get("Authorization")
+
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+
+	return strings.TrimSpace(header[7:])
+}
+
+// Returns where the credential the request was authenticated with is stored: the
+// table, the column identifying its row and the digest found in that column.
+// API tokens take precedence over sessions, as they do during authentication.
+// Returns empty strings if the request carries no credential.
+func Credential(r *http.Request) (string, string, string) {
+	if bearer := BearerToken(r); bearer != "" {
+		return "api_tokens", "digest", secrets.Digest(bearer)
+	}
+
+	if session := SessionToken(r); session != "" {
+		return "sessions", "id", secrets.Digest(session)
+	}
+
+	return "", "", ""
+}
+
+// Returns the session token sent along with the request, or an empty string if
+// there is none.
+func SessionToken(r *http.Request) string {
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return ""
+	}
+
+	return cookie.Value
+}
+
+// Hands the session token to the client. The cookie is out of reach of scripts,
+// and is only sent over HTTPS when the request itself came in over HTTPS.
+func SetSessionCookie(w http.ResponseWriter, r *http.Request, token string, expiry time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiry,
+		HttpOnly: true,
+		Secure:   IsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Tells the client to forget its session token.
+func ClearSessionCookie(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   IsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Reports whether the request reached the server, or the proxy in front of it,
+// over HTTPS.
+func IsSecure(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// Reports whether the request may act on the account of its user, such as
+// changing the password or managing API tokens. That is reserved for sessions
+// and admin scoped API tokens, so that a leaked read or upload token can not be
+// used to take over the account.
+func FullAccess(r *http.Request) bool {
+	var scope string = Scope(r)
+	return scope == "" || scope == "admin"
+}
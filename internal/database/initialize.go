@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"os"
 	"path"
 
 	"github.com/andrewdotjs/watchify-server/internal/logger"
@@ -18,28 +19,49 @@ func Initialize(log *logger.Logger, appDirectory *string) *sql.DB {
 
 	log.Info(sequenceId, "Starting database initialization")
 
+	database, err := Open(log, databaseDirectory)
+	if err != nil {
+		log.Fatal(sequenceId, fmt.Sprintf("%v", err))
+		os.Exit(1)
+	}
+
+	return database
+}
+
+// Opens the SQLite database at dataSourceName, creating and migrating its
+// tables as needed. ":memory:" opens a new database that only lives in memory,
+// which every connection of the returned sql.DB shares.
+func Open(log *logger.Logger, dataSourceName string) (*sql.DB, error) {
+	var sequenceId string = uuid.NewString()
+
+	// Every connection to ":memory:" would get a database of its own, so a
+	// named one with a shared cache is opened instead.
+	if dataSourceName == ":memory:" {
+		dataSourceName = fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())
+	}
+
 	// Open database
 
 	log.Info(sequenceId, "Opening database")
 
-	database, err := sql.Open("sqlite3", databaseDirectory)
+	database, err := sql.Open("sqlite3", dataSourceName)
 	if err != nil {
-		log.Fatal(sequenceId, fmt.Sprintf("%v", err))
-	} else {
-	  log.Info(sequenceId, "Successfully opened the database")
+		return nil, err
 	}
 
+	log.Info(sequenceId, "Successfully opened the database")
+
 	// Verify connection with database.
 
 	log.Info(sequenceId, "Verifying the connection with the database")
 
 	if err := database.Ping(); err != nil {
-		defer database.Close()
-		log.Fatal(sequenceId, fmt.Sprintf("Verification failed. Reason: %v", err))
-	} else {
-    log.Info(sequenceId, "Connection verified")
+		database.Close()
+		return nil, fmt.Errorf("verification failed. Reason: %w", err)
 	}
 
+	log.Info(sequenceId, "Connection verified")
+
 	log.Info(sequenceId, "Verifying database integrity")
 
 	if _, err := database.Exec(`
@@ -83,13 +105,218 @@ func Initialize(log *logger.Logger, appDirectory *string) *sql.DB {
 			upload_date TEXT NOT NULL,
 		  last_modified TEXT NOT NULL
     );
+
+		CREATE TABLE IF NOT EXISTS tags (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			kind TEXT NOT NULL,
+			upload_date TEXT NOT NULL,
+			last_modified TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS taggings (
+			tag_id TEXT NOT NULL,
+			parent_id TEXT NOT NULL,
+			PRIMARY KEY (tag_id, parent_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS taggings_parent_id ON taggings (parent_id);
+
+		CREATE TABLE IF NOT EXISTS people (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			biography TEXT NOT NULL,
+			upload_date TEXT NOT NULL,
+			last_modified TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS credits (
+			id TEXT PRIMARY KEY,
+			person_id TEXT NOT NULL,
+			parent_id TEXT NOT NULL,
+			parent_type TEXT NOT NULL,
+			role TEXT NOT NULL,
+			character TEXT NOT NULL,
+			position INTEGER NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS credits_parent_id ON credits (parent_id);
+		CREATE INDEX IF NOT EXISTS credits_person_id ON credits (person_id);
+
+		CREATE TABLE IF NOT EXISTS content_ratings (
+			id TEXT PRIMARY KEY,
+			system TEXT NOT NULL,
+			code TEXT NOT NULL,
+			rank INTEGER NOT NULL,
+			description TEXT NOT NULL,
+			UNIQUE (system, code)
+		);
+
+		CREATE TABLE IF NOT EXISTS profiles (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			max_content_rating_id TEXT,
+			pin_hash TEXT NOT NULL,
+			upload_date TEXT NOT NULL,
+			last_modified TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS pin_failures (
+			profile_id TEXT PRIMARY KEY,
+			failures INTEGER NOT NULL,
+			locked_until TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS two_factor_failures (
+			user_id TEXT PRIMARY KEY,
+			failures INTEGER NOT NULL,
+			locked_until TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS watch_history (
+			profile_id TEXT NOT NULL,
+			media_id TEXT NOT NULL,
+			media_type TEXT NOT NULL,
+			play_count INTEGER NOT NULL,
+			last_watched_date TEXT NOT NULL,
+			PRIMARY KEY (profile_id, media_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS progress (
+			viewer_id TEXT NOT NULL,
+			media_id TEXT NOT NULL,
+			media_type TEXT NOT NULL,
+			position REAL NOT NULL,
+			duration REAL NOT NULL,
+			watched BOOLEAN NOT NULL,
+			last_watched_date TEXT NOT NULL,
+			PRIMARY KEY (viewer_id, media_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS list_items (
+			viewer_id TEXT NOT NULL,
+			list TEXT NOT NULL,
+			media_id TEXT NOT NULL,
+			media_type TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			added_date TEXT NOT NULL,
+			PRIMARY KEY (viewer_id, list, media_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS user_ratings (
+			id TEXT PRIMARY KEY,
+			viewer_id TEXT NOT NULL,
+			media_id TEXT NOT NULL,
+			media_type TEXT NOT NULL,
+			score INTEGER NOT NULL,
+			review TEXT NOT NULL,
+			hidden BOOLEAN NOT NULL,
+			created_date TEXT NOT NULL,
+			last_modified TEXT NOT NULL,
+			UNIQUE (viewer_id, media_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			username TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			password_hash TEXT NOT NULL,
+			role TEXT NOT NULL,
+			upload_date TEXT NOT NULL,
+			last_modified TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS sessions (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			expiry_date TEXT NOT NULL,
+			upload_date TEXT NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS sessions_user_id ON sessions (user_id);
+
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			digest TEXT NOT NULL UNIQUE,
+			scope TEXT NOT NULL,
+			last_used_date TEXT,
+			expiry_date TEXT,
+			upload_date TEXT NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS api_tokens_user_id ON api_tokens (user_id);
+
+		CREATE TABLE IF NOT EXISTS user_identities (
+			issuer TEXT NOT NULL,
+			subject TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			email TEXT NOT NULL,
+			upload_date TEXT NOT NULL,
+			PRIMARY KEY (issuer, subject)
+		);
+
+		CREATE TABLE IF NOT EXISTS recovery_codes (
+			user_id TEXT NOT NULL,
+			digest TEXT NOT NULL,
+			PRIMARY KEY (user_id, digest)
+		);
+
+		CREATE TABLE IF NOT EXISTS login_challenges (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			attempts INTEGER NOT NULL,
+			expiry_date TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS oidc_logins (
+			state TEXT PRIMARY KEY,
+			nonce TEXT NOT NULL,
+			verifier TEXT NOT NULL,
+			user_id TEXT,
+			expiry_date TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS persisted_queries (
+			hash TEXT PRIMARY KEY,
+			query TEXT NOT NULL,
+			upload_date TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			user_id TEXT NOT NULL,
+			key TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			status INTEGER NOT NULL,
+			content_type TEXT NOT NULL,
+			body BLOB NOT NULL,
+			expiry_date TEXT NOT NULL,
+			PRIMARY KEY (user_id, key)
+		);
   `); err != nil {
-		defer database.Close()
-		log.Fatal(sequenceId, fmt.Sprintf("Verification failed. Reason: %v", err))
-	} else {
-	  log.Info(sequenceId, "Database integrity verified")
+		database.Close()
+		return nil, fmt.Errorf("verification failed. Reason: %w", err)
+	}
+
+	log.Info(sequenceId, "Database integrity verified")
+
+	log.Info(sequenceId, "Migrating tables created by older versions")
+
+	if err := migrate(database); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("migration failed. Reason: %w", err)
+	}
+
+	if err := seed(database); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("seeding failed. Reason: %w", err)
 	}
 
 	log.Info(sequenceId, "Database is ready")
-	return database
+	return database, nil
 }
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestOpenMemory(t *testing.T) {
+	var log logger.Logger
+
+	first, err := Open(&log, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	second, err := Open(&log, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+
+	if _, err := first.Exec("INSERT INTO settings (key, value) VALUES ('test', 'first')"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Connections of the pool see the same database, even while another one
+	// is held.
+	held, err := first.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer held.Close()
+
+	var value string
+	if err := first.QueryRow("SELECT value FROM settings WHERE key = 'test'").Scan(&value); err != nil {
+		t.Fatalf("another connection does not see the setting: %v", err)
+	}
+
+	// Every database opened is a new one.
+	var count int
+	if err := second.QueryRow("SELECT COUNT(*) FROM settings WHERE key = 'test'").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 0 {
+		t.Error("two in-memory databases share their rows")
+	}
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Columns that were added to tables after their creation. Databases created by
+// older versions of the server get them added during initialization.
+var addedColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"movies", "content_rating_id", "TEXT"},
+	{"shows", "content_rating_id", "TEXT"},
+	{"episodes", "content_rating_id", "TEXT"},
+	{"users", "totp_secret", "TEXT NOT NULL DEFAULT ''"},
+	{"users", "totp_enabled", "BOOLEAN NOT NULL DEFAULT 0"},
+	{"users", "totp_counter", "INTEGER NOT NULL DEFAULT 0"},
+	{"profiles", "user_id", "TEXT"},
+	{"profiles", "audio_language", "TEXT NOT NULL DEFAULT ''"},
+	{"profiles", "subtitle_language", "TEXT NOT NULL DEFAULT ''"},
+	{"sessions", "profile_id", "TEXT"},
+	{"api_tokens", "profile_id", "TEXT"},
+	{"episodes", "season_number", "INTEGER NOT NULL DEFAULT 1"},
+	{"shows", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"movies", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"episodes", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"people", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"tags", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"content_ratings", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"profiles", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"users", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"credits", "version", "INTEGER NOT NULL DEFAULT 1"},
+	{"idempotency_keys", "replayable", "BOOLEAN NOT NULL DEFAULT 1"},
+	{"idempotency_keys", "header", "TEXT NOT NULL DEFAULT '{}'"},
+}
+
+// Tables whose rows carry a version, which the ETags of their representations
+// are derived from. Every update of a row increments it.
+var versionedTables = []string{
+	"shows",
+	"movies",
+	"episodes",
+	"people",
+	"tags",
+	"content_ratings",
+	"profiles",
+	"users",
+	"credits",
+}
+
+// Tables whose rows are part of the representation of the row their parent_id
+// points at, which is a row of one of parentTables. Any change to them
+// increments the version of that parent, so that its ETags change as well.
+var childTables = []string{"covers", "taggings", "credits"}
+
+// Versioned tables that rows of childTables can belong to. Profiles own the
+// covers that are their avatars.
+var parentTables = []string{"shows", "movies", "episodes", "people", "profiles"}
+
+// Adds every column in addedColumns that is not present in its table yet, and
+// the triggers incrementing the versions of versioned tables and of the parents
+// of rows of childTables.
+func migrate(database *sql.DB) error {
+	for _, added := range addedColumns {
+		var exists bool = false
+
+		rows, err := database.Query(fmt.Sprintf("PRAGMA table_info(%s)", added.table))
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var index, notNull, primaryKey int
+			var name, columnType string
+			var defaultValue sql.NullString
+
+			if err := rows.Scan(&index, &name, &columnType, &notNull, &defaultValue, &primaryKey); err != nil {
+				rows.Close()
+				return err
+			}
+
+			if name == added.column {
+				exists = true
+			}
+		}
+
+		rows.Close()
+
+		if exists {
+			continue
+		}
+
+		if _, err := database.Exec(
+			fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", added.table, added.column, added.definition),
+		); err != nil {
+			return err
+		}
+	}
+
+	// Updates that set the version themselves are left alone, which also keeps
+	// the trigger from firing for its own update.
+	for _, table := range versionedTables {
+		if _, err := database.Exec(fmt.Sprintf(`
+			CREATE TRIGGER IF NOT EXISTS %[1]s_version AFTER UPDATE ON %[1]s
+			WHEN NEW.version = OLD.version
+			BEGIN
+				UPDATE %[1]s SET version = OLD.version + 1 WHERE rowid = NEW.rowid;
+			END
+			`,
+			table,
+		)); err != nil {
+			return err
+		}
+	}
+
+	// Triggers of the parents are dropped first, so that databases created before
+	// a parent table was added pick it up.
+	for _, table := range childTables {
+		for _, event := range []struct {
+			name    string
+			parents string
+		}{
+			{"insert", "NEW.parent_id"},
+			{"update", "OLD.parent_id, NEW.parent_id"},
+			{"delete", "OLD.parent_id"},
+		} {
+			var statements []string
+
+			for _, parent := range parentTables {
+				statements = append(statements, fmt.Sprintf("UPDATE %s SET version = version + 1 WHERE id IN (%s);", parent, event.parents))
+			}
+
+			if _, err := database.Exec(fmt.Sprintf(`
+				DROP TRIGGER IF EXISTS %[1]s_%[2]s_parent_version;
+				CREATE TRIGGER %[1]s_%[2]s_parent_version AFTER %[2]s ON %[1]s
+				BEGIN
+					%[3]s
+				END
+				`,
+				table,
+				event.name,
+				strings.Join(statements, "\n"),
+			)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Content ratings the server starts out with. Ranks roughly follow the minimum
+// viewer age so that ratings from different systems can be compared against a
+// single profile limit. They can be reordered through the content ratings API.
+var defaultContentRatings = []types.ContentRating{
+	{System: "MPAA", Code: "G", Rank: 0, Description: "General audiences"},
+	{System: "MPAA", Code: "PG", Rank: 8, Description: "Parental guidance suggested"},
+	{System: "MPAA", Code: "PG-13", Rank: 13, Description: "Parents strongly cautioned"},
+	{System: "MPAA", Code: "R", Rank: 17, Description: "Restricted"},
+	{System: "MPAA", Code: "NC-17", Rank: 18, Description: "Adults only"},
+	{System: "TV", Code: "TV-Y", Rank: 0, Description: "All children"},
+	{System: "TV", Code: "TV-Y7", Rank: 7, Description: "Directed to older children"},
+	{System: "TV", Code: "TV-G", Rank: 0, Description: "General audience"},
+	{System: "TV", Code: "TV-PG", Rank: 8, Description: "Parental guidance suggested"},
+	{System: "TV", Code: "TV-14", Rank: 14, Description: "Parents strongly cautioned"},
+	{System: "TV", Code: "TV-MA", Rank: 17, Description: "Mature audience only"},
+	{System: "BBFC", Code: "U", Rank: 0, Description: "Universal"},
+	{System: "BBFC", Code: "PG", Rank: 8, Description: "Parental guidance"},
+	{System: "BBFC", Code: "12A", Rank: 12, Description: "Suitable for 12 years and over"},
+	{System: "BBFC", Code: "15", Rank: 15, Description: "Suitable only for 15 years and over"},
+	{System: "BBFC", Code: "18", Rank: 18, Description: "Suitable only for adults"},
+}
+
+// Settings the server starts out with. Settings added by newer versions are
+// filled in without touching the ones that were changed already.
+var defaultSettings = map[string]string{
+	"require_two_factor":      "false",
+	"watched_threshold":       "90",
+	"recommendation_interval": "60",
+}
+
+// Fills tables that the server needs a starting set of rows for, but only if
+// they are empty so that user changes are never overwritten.
+func seed(database *sql.DB) error {
+	var count int = 0
+
+	for key, value := range defaultSettings {
+		if _, err := database.Exec(`
+			INSERT OR IGNORE INTO
+				settings (key, value)
+			VALUES
+				(?, ?)
+			`,
+			key,
+			value,
+		); err != nil {
+			return err
+		}
+	}
+
+	if err := database.QueryRow("SELECT COUNT(*) FROM content_ratings").Scan(&count); err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	for _, rating := range defaultContentRatings {
+		if _, err := database.Exec(`
+			INSERT INTO
+				content_ratings (id, system, code, rank, description)
+			VALUES
+				(?, ?, ?, ?, ?)
+			`,
+			uuid.NewString(),
+			rating.System,
+			rating.Code,
+			rating.Rank,
+			rating.Description,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,5 +1,7 @@
 package functions
 
+import "strconv"
+
 // Returns the minimum of two integers. Needed this for
 // video streaming chunk calculations.
 func Minimum(num1 int, num2 int) int {
@@ -9,3 +11,15 @@ func Minimum(num1 int, num2 int) int {
 
 	return num2
 }
+
+// Parses a limit query value, such as the number of items to return. Falls back
+// to fallback when the value is missing or not a positive integer, and never
+// returns more than maximum.
+func Limit(value string, fallback int, maximum int) int {
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return fallback
+	}
+
+	return Minimum(limit, maximum)
+}
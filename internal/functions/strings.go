@@ -0,0 +1,33 @@
+package functions
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Trims the surrounding whitespace of user provided text and strips any
+// control characters, other than new lines and tabs, before it gets
+// stored in the database.
+func Sanitize(text string) string {
+	return strings.Map(func(character rune) rune {
+		if unicode.IsControl(character) && character != '\n' && character != '\t' {
+			return -1
+		}
+
+		return character
+	}, strings.TrimSpace(text))
+}
+
+// Splits a comma separated query or form value into its trimmed, non-empty
+// parts. Returns nil if the value contains no parts.
+func SplitList(value string) []string {
+	var parts []string
+
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,624 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// A request to execute a document, as clients send it.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// The result of a request. Data is left out if the request failed before it
+// could be executed.
+type Response struct {
+	Data   any      `json:"data,omitempty"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Parses, validates and executes a query. Errors while resolving a field leave
+// that field null and are reported along with the data of the other fields.
+func (schema *Schema) Execute(request Request) Response {
+	document, err := parse(request.Query)
+	if err != nil {
+		return Response{Errors: []*Error{err.(*Error)}}
+	}
+
+	operation, err := document.operation(request.OperationName)
+	if err != nil {
+		return Response{Errors: []*Error{err.(*Error)}}
+	}
+
+	variables, errors := coerceVariables(operation, request.Variables)
+	if len(errors) > 0 {
+		return Response{Errors: errors}
+	}
+
+	executor := executor{schema: schema, document: document, variables: variables, costs: map[spreadCost]int{}}
+
+	cost := executor.validate(schema.Query, operation.selections, 1, map[string]bool{})
+	if len(executor.errors) > 0 {
+		return Response{Errors: executor.errors}
+	}
+
+	if cost > schema.MaxComplexity {
+		return Response{Errors: []*Error{{
+			Message: fmt.Sprintf("The query is too complex, it selects about %d fields while at most %d are allowed.", cost, schema.MaxComplexity),
+		}}}
+	}
+
+	executor.merge(schema.Query, operation.selections)
+	if len(executor.errors) > 0 {
+		return Response{Errors: executor.errors}
+	}
+
+	data := executor.selectionSet(schema.Query, []any{nil}, operation.selections, nil)
+	return Response{Data: data[0], Errors: executor.errors}
+}
+
+// Returns the operation to execute: the one named name, or the only operation
+// of the document if name is empty. Only queries can be executed.
+func (document *document) operation(name string) (*operation, error) {
+	var selected *operation
+
+	for _, operation := range document.operations {
+		if operation.name == name || (name == "" && len(document.operations) == 1) {
+			selected = operation
+		}
+	}
+
+	switch {
+	case selected == nil && name == "":
+		return nil, &Error{Message: "The document contains several operations, so operationName is required."}
+	case selected == nil:
+		return nil, &Error{Message: fmt.Sprintf("The document contains no operation named %q.", name)}
+	case selected.kind != "query":
+		return nil, &Error{Message: fmt.Sprintf("Only queries are supported, not %ss.", selected.kind)}
+	}
+
+	return selected, nil
+}
+
+// Returns the values of the variables an operation declares, taken from the
+// request or from their defaults.
+func coerceVariables(operation *operation, provided map[string]any) (map[string]any, []*Error) {
+	var variables map[string]any = map[string]any{}
+	var errors []*Error
+
+	for _, definition := range operation.variables {
+		given, exists := provided[definition.name]
+
+		if !exists && definition.defaultValue != nil {
+			given, _ = definition.defaultValue.resolve(nil)
+		}
+
+		coerced, err := coerce(given, definition.typeName)
+		if err != nil {
+			errors = append(errors, locatedError(definition.location, fmt.Sprintf("Variable \"$%s\" %s.", definition.name, err.Error())))
+			continue
+		}
+
+		variables[definition.name] = coerced
+	}
+
+	return variables, errors
+}
+
+// Converts a value from JSON or from a document to the Go type of a GraphQL
+// type: string, int, float64, bool or a list of those.
+func coerce(given any, typeName string) (any, error) {
+	if given == nil {
+		if isRequired(typeName) {
+			return nil, fmt.Errorf("of type %s must not be null", typeName)
+		}
+
+		return nil, nil
+	}
+
+	if isList(typeName) {
+		return coerceList(given, typeName)
+	}
+
+	mismatch := fmt.Errorf("must be of type %s", typeName)
+
+	switch namedType(typeName) {
+	case "String", "ID":
+		if text, isText := given.(string); isText {
+			return text, nil
+		}
+
+		if number, isNumber := given.(int); isNumber && namedType(typeName) == "ID" {
+			return fmt.Sprint(number), nil
+		}
+
+	case "Int":
+		switch number := given.(type) {
+		case int:
+			return number, nil
+		case float64:
+			if number == math.Trunc(number) && math.Abs(number) <= math.MaxInt32 {
+				return int(number), nil
+			}
+		}
+
+	case "Float":
+		switch number := given.(type) {
+		case int:
+			return float64(number), nil
+		case float64:
+			return number, nil
+		}
+
+	case "Boolean":
+		if boolean, isBoolean := given.(bool); isBoolean {
+			return boolean, nil
+		}
+
+	default:
+		return given, nil
+	}
+
+	return nil, mismatch
+}
+
+// Coerces every item of a list value, or a single value as a list of one item.
+func coerceList(given any, typeName string) (any, error) {
+	var items []any
+	var coerced []any = []any{}
+
+	inner := strings.TrimSuffix(typeName, "!")
+	inner = inner[1 : len(inner)-1]
+
+	if list, isList := given.([]any); isList {
+		items = list
+	} else {
+		items = []any{given}
+	}
+
+	for _, item := range items {
+		value, err := coerce(item, inner)
+		if err != nil {
+			return nil, err
+		}
+
+		coerced = append(coerced, value)
+	}
+
+	return coerced, nil
+}
+
+// Returns the value of a literal or variable, taking variables from variables.
+func (value *value) resolve(variables map[string]any) (any, error) {
+	switch value.kind {
+	case variableValue:
+		resolved, exists := variables[value.variable]
+		if !exists {
+			return nil, fmt.Errorf("undefined variable \"$%s\"", value.variable)
+		}
+
+		return resolved, nil
+
+	case listValue:
+		var list []any = []any{}
+
+		for _, item := range value.list {
+			resolved, err := item.resolve(variables)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, resolved)
+		}
+
+		return list, nil
+
+	case objectValue:
+		var object map[string]any = map[string]any{}
+
+		for name, item := range value.object {
+			resolved, err := item.resolve(variables)
+			if err != nil {
+				return nil, err
+			}
+
+			object[name] = resolved
+		}
+
+		return object, nil
+	}
+
+	return value.literal, nil
+}
+
+// Runs a validated operation, collecting errors of fields that failed.
+type executor struct {
+	schema    *Schema
+	document  *document
+	variables map[string]any
+	errors    []*Error
+	costs     map[spreadCost]int
+}
+
+// A fragment spread within an object at some depth, whose cost is measured the
+// first time it is spread there and reused every time after.
+type spreadCost struct {
+	fragment string
+	object   string
+	depth    int
+}
+
+// Returns the arguments of a field coerced to the types the field declares.
+func (executor *executor) arguments(definition *Field, field *field) (map[string]any, error) {
+	var arguments map[string]any = map[string]any{}
+
+	for name := range field.arguments {
+		if _, exists := definition.Arguments[name]; !exists {
+			return nil, fmt.Errorf("Unknown argument %q on field %q.", name, field.name)
+		}
+	}
+
+	for name, typeName := range definition.Arguments {
+		var given any
+
+		if argument, exists := field.arguments[name]; exists {
+			resolved, err := argument.resolve(executor.variables)
+			if err != nil {
+				return nil, fmt.Errorf("Argument %q of field %q uses an %s.", name, field.name, err.Error())
+			}
+
+			given = resolved
+		}
+
+		coerced, err := coerce(given, typeName)
+		if err != nil {
+			return nil, fmt.Errorf("Argument %q of field %q %s.", name, field.name, err.Error())
+		}
+
+		arguments[name] = coerced
+	}
+
+	return arguments, nil
+}
+
+// Checks that every selected field exists and gets the arguments it needs, and
+// that the query stays within the depth limit, counting fragments as a level.
+// Returns the number of fields the query is expected to select, which stops
+// growing once it is past the complexity limit.
+func (executor *executor) validate(object *Object, selections []*selection, depth int, fragments map[string]bool) int {
+	var cost int = 0
+
+	if depth > executor.schema.MaxDepth {
+		executor.errors = append(executor.errors, locatedError(selections[0].location,
+			fmt.Sprintf("The query nests fields and fragments more than %d levels deep.", executor.schema.MaxDepth)))
+		return 0
+	}
+
+	for _, selection := range selections {
+		switch {
+		case selection.spread != "" || selection.inline != nil:
+			fragment := selection.inline
+			spread := spreadCost{fragment: selection.spread, object: object.Name, depth: depth}
+
+			if selection.spread != "" {
+				if fragments[selection.spread] {
+					executor.errors = append(executor.errors, locatedError(selection.location, fmt.Sprintf("Fragment %q spreads itself.", selection.spread)))
+					continue
+				}
+
+				if fragment = executor.document.fragments[selection.spread]; fragment == nil {
+					executor.errors = append(executor.errors, locatedError(selection.location, fmt.Sprintf("Unknown fragment %q.", selection.spread)))
+					continue
+				}
+			}
+
+			if fragment.typeCondition != "" && fragment.typeCondition != object.Name {
+				executor.errors = append(executor.errors, locatedError(selection.location,
+					fmt.Sprintf("Fragment on %q can't be spread within %q.", fragment.typeCondition, object.Name)))
+				continue
+			}
+
+			// Fragments spreading others several times would otherwise be walked as
+			// many times as they end up spread, which grows exponentially.
+			if known, measured := executor.costs[spread]; measured && selection.spread != "" {
+				cost = executor.bounded(cost + known)
+				continue
+			}
+
+			fragments[selection.spread] = true
+			fragmentCost := executor.validate(object, fragment.selections, depth+1, fragments)
+			delete(fragments, selection.spread)
+
+			if selection.spread != "" {
+				executor.costs[spread] = fragmentCost
+			}
+
+			cost = executor.bounded(cost + fragmentCost)
+
+		case selection.field.name == "__typename":
+			cost = executor.bounded(cost + 1)
+
+		default:
+			field := selection.field
+			definition, exists := object.Fields[field.name]
+
+			if !exists {
+				executor.errors = append(executor.errors, locatedError(field.location, fmt.Sprintf("Cannot query field %q on type %q.", field.name, object.Name)))
+				continue
+			}
+
+			arguments, err := executor.arguments(definition, field)
+			if err != nil {
+				executor.errors = append(executor.errors, locatedError(field.location, err.Error()))
+				continue
+			}
+
+			child, isObject := executor.schema.objects[namedType(definition.Type)]
+
+			switch {
+			case isObject && field.selections == nil:
+				executor.errors = append(executor.errors, locatedError(field.location,
+					fmt.Sprintf("Field %q of type %q must have a selection of subfields.", field.name, definition.Type)))
+
+			case !isObject && field.selections != nil:
+				executor.errors = append(executor.errors, locatedError(field.location,
+					fmt.Sprintf("Field %q of type %q can't have a selection of subfields.", field.name, definition.Type)))
+
+			case isObject:
+				size := 1
+				if isList(definition.Type) {
+					size = assumedListSize
+					if first, isInt := arguments["first"].(int); isInt && first > 0 {
+						size = first
+					}
+
+					size = min(size, executor.schema.MaxListSize)
+				}
+
+				cost = executor.bounded(cost + 1 + size*executor.validate(child, field.selections, depth+1, fragments))
+
+			default:
+				cost = executor.bounded(cost + 1)
+			}
+		}
+	}
+
+	return cost
+}
+
+// Returns cost, or just past the complexity limit if it is further past it, so
+// that costs can't overflow however far fragments and lists multiply them.
+func (executor *executor) bounded(cost int) int {
+	return min(cost, executor.schema.MaxComplexity+1)
+}
+
+// Checks that the fields selected under every response key, across fragments
+// and at every level, are the same field with the same arguments, since only
+// the first of them is resolved. Runs once the query is within its limits,
+// which bound how many fields expanding its fragments yields.
+func (executor *executor) merge(object *Object, selections []*selection) {
+	for _, group := range executor.collect(selections) {
+		var first *field = group.fields[0]
+		var definition *Field = object.Fields[first.name]
+		var childSelections []*selection
+		var arguments map[string]any
+		var conflicting bool = false
+
+		// __typename has no definition, nor any arguments.
+		if definition != nil {
+			arguments, _ = executor.arguments(definition, first)
+		}
+
+		for _, field := range group.fields {
+			var reason string
+
+			childSelections = append(childSelections, field.selections...)
+
+			switch {
+			case field.name != first.name:
+				reason = fmt.Sprintf("%q and %q are different fields", first.name, field.name)
+			case definition != nil:
+				if others, _ := executor.arguments(definition, field); !reflect.DeepEqual(arguments, others) {
+					reason = "they have differing arguments"
+				}
+			}
+
+			if reason == "" {
+				continue
+			}
+
+			executor.errors = append(executor.errors, &Error{
+				Message:   fmt.Sprintf("Fields %q conflict because %s.", group.key, reason),
+				Locations: []Location{first.location, field.location},
+			})
+
+			conflicting = true
+			break
+		}
+
+		if conflicting || definition == nil {
+			continue
+		}
+
+		if child, isObject := executor.schema.objects[namedType(definition.Type)]; isObject {
+			executor.merge(child, childSelections)
+		}
+	}
+}
+
+// The fields selected under one response key, merged across fragments.
+type fieldGroup struct {
+	key    string
+	fields []*field
+}
+
+// Gathers the fields of selections, expanding fragments, grouped by the key
+// their values are returned under and in the order they were first selected.
+func (executor *executor) collect(selections []*selection) []*fieldGroup {
+	var groups []*fieldGroup
+	var keyed map[string]*fieldGroup = map[string]*fieldGroup{}
+	var gather func(selections []*selection)
+
+	gather = func(selections []*selection) {
+		for _, selection := range selections {
+			switch {
+			case selection.field != nil:
+				group, exists := keyed[selection.field.key()]
+
+				if !exists {
+					group = &fieldGroup{key: selection.field.key()}
+					keyed[group.key] = group
+					groups = append(groups, group)
+				}
+
+				group.fields = append(group.fields, selection.field)
+
+			case selection.inline != nil:
+				gather(selection.inline.selections)
+
+			default:
+				gather(executor.document.fragments[selection.spread].selections)
+			}
+		}
+	}
+
+	gather(selections)
+	return groups
+}
+
+// Resolves selections on every parent, which are all of type object, returning
+// one response object for every parent. Every field is resolved once for all
+// parents, and so are the fields below it.
+func (executor *executor) selectionSet(object *Object, parents []any, selections []*selection, path []any) []*responseObject {
+	var results []*responseObject = make([]*responseObject, len(parents))
+
+	for index := range results {
+		results[index] = &responseObject{}
+	}
+
+	for _, group := range executor.collect(selections) {
+		var field *field = group.fields[0]
+		var fieldPath []any = append(append([]any{}, path...), group.key)
+
+		if field.name == "__typename" {
+			for _, result := range results {
+				result.set(group.key, object.Name)
+			}
+
+			continue
+		}
+
+		definition := object.Fields[field.name]
+		arguments, _ := executor.arguments(definition, field)
+
+		values, err := definition.Resolve(parents, arguments)
+		if err == nil && len(values) != len(parents) {
+			err = fmt.Errorf("resolved %d values for %d parents", len(values), len(parents))
+		}
+
+		if err != nil {
+			executor.errors = append(executor.errors, &Error{
+				Message:   err.Error(),
+				Locations: []Location{field.location},
+				Path:      fieldPath,
+			})
+
+			for _, result := range results {
+				result.set(group.key, nil)
+			}
+
+			continue
+		}
+
+		child, isObject := executor.schema.objects[namedType(definition.Type)]
+		if !isObject {
+			for index, result := range results {
+				result.set(group.key, values[index])
+			}
+
+			continue
+		}
+
+		// Resolve the objects of every parent together, then hand each parent back
+		// its own.
+		var children []any
+		var childSelections []*selection
+
+		for _, field := range group.fields {
+			childSelections = append(childSelections, field.selections...)
+		}
+
+		for _, value := range values {
+			if list, isList := value.([]any); isList {
+				children = append(children, list...)
+			} else if value != nil {
+				children = append(children, value)
+			}
+		}
+
+		resolved := executor.selectionSet(child, children, childSelections, fieldPath)
+
+		for index, value := range values {
+			if list, isList := value.([]any); isList {
+				var items []any = []any{}
+
+				for range list {
+					items = append(items, resolved[0])
+					resolved = resolved[1:]
+				}
+
+				results[index].set(group.key, items)
+			} else if value != nil {
+				results[index].set(group.key, resolved[0])
+				resolved = resolved[1:]
+			} else {
+				results[index].set(group.key, nil)
+			}
+		}
+	}
+
+	return results
+}
+
+// A JSON object that keeps its keys in the order they were selected in, as
+// GraphQL responses must.
+type responseObject struct {
+	keys   []string
+	values []any
+}
+
+func (object *responseObject) set(key string, value any) {
+	object.keys = append(object.keys, key)
+	object.values = append(object.values, value)
+}
+
+func (object *responseObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteByte('{')
+
+	for index, key := range object.keys {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+
+		encodedKey, _ := json.Marshal(key)
+		encodedValue, err := json.Marshal(object.values[index])
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(encodedKey)
+		buffer.WriteByte(':')
+		buffer.Write(encodedValue)
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,189 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/graphql"
+)
+
+// Returns a schema of items, named by their ids, whose lists hold as many
+// items as their first argument asks for, and two otherwise.
+func newSchema() *graphql.Schema {
+	list := func(parents []any, arguments map[string]any) ([]any, error) {
+		var values []any
+
+		for _, parent := range parents {
+			var items []any = []any{}
+			var size int = 2
+
+			if first, given := arguments["first"].(int); given {
+				size = first
+			}
+
+			for index := range size {
+				items = append(items, fmt.Sprintf("%v.%d", parent, index))
+			}
+
+			values = append(values, items)
+		}
+
+		return values, nil
+	}
+
+	item := &graphql.Object{Name: "Item", Fields: map[string]*graphql.Field{
+		"name":     {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent })},
+		"children": {Type: "[Item!]!", Arguments: map[string]string{"first": "Int"}, Resolve: list},
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+		"item": {Type: "Item", Arguments: map[string]string{"id": "ID!"}, Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			return []any{arguments["id"]}, nil
+		}},
+		"items": {Type: "[Item!]!", Arguments: map[string]string{"first": "Int"}, Resolve: list},
+	}}
+
+	return graphql.NewSchema(query, item)
+}
+
+// Executes query, returning its data as JSON along with the messages of its
+// errors.
+func execute(t *testing.T, schema *graphql.Schema, query string) (string, []string) {
+	var messages []string
+
+	response := schema.Execute(graphql.Request{Query: query})
+
+	data, err := json.Marshal(response.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, err := range response.Errors {
+		messages = append(messages, err.Message)
+	}
+
+	return string(data), messages
+}
+
+func TestExecute(t *testing.T) {
+	data, errors := execute(t, newSchema(), `{
+		item(id: "a") { name ...children }
+		first: item(id: "a") { name }
+	}
+	fragment children on Item { children(first: 1) { name } }`)
+
+	if len(errors) > 0 {
+		t.Fatal(errors)
+	}
+
+	if want := `{"item":{"name":"a","children":[{"name":"a.0"}]},"first":{"name":"a"}}`; data != want {
+		t.Errorf("returned %s, want %s", data, want)
+	}
+}
+
+// Fragments spreading each other twice over select exponentially many fields,
+// which must be measured without walking every one of them.
+func TestExecuteFanningFragments(t *testing.T) {
+	var query strings.Builder
+
+	query.WriteString("{ item(id: \"a\") { ...f0 } }\n")
+	for level := range 40 {
+		fmt.Fprintf(&query, "fragment f%d on Item { ...f%d ...f%d }\n", level, level+1, level+1)
+	}
+	query.WriteString("fragment f40 on Item { name }\n")
+
+	schema := newSchema()
+	schema.MaxDepth = 50
+
+	started := time.Now()
+	_, errors := execute(t, schema, query.String())
+
+	if len(errors) != 1 || !strings.HasPrefix(errors[0], "The query is too complex") {
+		t.Errorf("the query failed with %v", errors)
+	}
+
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("the query took %v to reject", elapsed)
+	}
+}
+
+func TestExecuteDepth(t *testing.T) {
+	schema := newSchema()
+	schema.MaxDepth = 3
+
+	// Each spread is a level, even though it selects no field of its own.
+	_, errors := execute(t, schema, `{ item(id: "a") { ...one } }
+	fragment one on Item { ...two }
+	fragment two on Item { name }`)
+
+	if len(errors) != 1 || errors[0] != "The query nests fields and fragments more than 3 levels deep." {
+		t.Errorf("the query failed with %v", errors)
+	}
+
+	_, errors = execute(t, schema, `{ item(id: "a") { ...one } }
+	fragment one on Item { name }`)
+
+	if len(errors) > 0 {
+		t.Errorf("the query failed with %v", errors)
+	}
+}
+
+func TestExecuteComplexity(t *testing.T) {
+	for _, test := range []struct {
+		query    string
+		rejected bool
+	}{
+		// Lists count as holding at most 100 items, whatever they are asked for.
+		{`{ items(first: 2147483647) { name } }`, false},
+		{`{ items(first: 40) { children(first: 40) { name } } }`, false},
+		{`{ items(first: 100) { children(first: 100) { name } } }`, true},
+		// Multiplying these without a bound would wrap around to a small cost.
+		{`{ items(first: 2147483647) { children(first: 2147483647) { children(first: 2147483647) { children(first: 2147483647) { name } } } } }`, true},
+	} {
+		schema := newSchema()
+		schema.MaxListSize = 100
+
+		// Resolving is beside the point, only measuring is.
+		schema.MaxComplexity = 2000
+		schema.Query.Fields["items"].Resolve = func(parents []any, arguments map[string]any) ([]any, error) {
+			return []any{[]any{}}, nil
+		}
+
+		_, errors := execute(t, schema, test.query)
+		rejected := len(errors) == 1 && strings.HasPrefix(errors[0], "The query is too complex")
+
+		if rejected != test.rejected || (!rejected && len(errors) > 0) {
+			t.Errorf("%s failed with %v", test.query, errors)
+		}
+	}
+}
+
+func TestExecuteConflicts(t *testing.T) {
+	for _, test := range []struct {
+		query string
+		error string
+	}{
+		{`{ item(id: "a") { name } item(id: "b") { name } }`, `Fields "item" conflict because they have differing arguments.`},
+		{`{ name: items { name } name: item(id: "a") { name } }`, `Fields "name" conflict because "items" and "item" are different fields.`},
+		{`{ item(id: "a") { name: __typename ...named } } fragment named on Item { name }`, `Fields "name" conflict because "__typename" and "name" are different fields.`},
+		{`{ item(id: "a") { ...one ...two } }
+		fragment one on Item { children(first: 1) { name } }
+		fragment two on Item { children(first: 2) { name } }`, `Fields "children" conflict because they have differing arguments.`},
+		{`{ items { children { name: name } } items { children { name: children { name } } } }`, `Fields "name" conflict because "name" and "children" are different fields.`},
+	} {
+		_, errors := execute(t, newSchema(), test.query)
+
+		if len(errors) != 1 || errors[0] != test.error {
+			t.Errorf("%s failed with %v, want %q", test.query, errors, test.error)
+		}
+	}
+
+	// The same field selected twice with the same arguments is merged.
+	data, errors := execute(t, newSchema(), `{ item(id: "a") { name } item(id: "a") { children(first: 1) { name } } }`)
+	if want := `{"item":{"name":"a","children":[{"name":"a.0"}]}}`; len(errors) > 0 || data != want {
+		t.Errorf("returned %s with %v, want %s", data, errors, want)
+	}
+}
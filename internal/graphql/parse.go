@@ -0,0 +1,606 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A parsed GraphQL document: its operations and the fragments they spread.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// A query, mutation or subscription of a document.
+type operation struct {
+	kind       string
+	name       string
+	variables  []*variableDefinition
+	selections []*selection
+}
+
+// A variable an operation declares, such as `$id: ID! = "..."`.
+type variableDefinition struct {
+	name         string
+	typeName     string
+	defaultValue *value
+	location     Location
+}
+
+// A named fragment, such as `fragment card on Show { ... }`.
+type fragment struct {
+	typeCondition string
+	selections    []*selection
+}
+
+// A field, fragment spread or inline fragment within a selection set. Exactly
+// one of field, spread or inline is set.
+type selection struct {
+	field  *field
+	spread string
+	inline *fragment
+
+	location Location
+}
+
+// A field along with its alias, arguments and selection set.
+type field struct {
+	alias      string
+	name       string
+	arguments  map[string]*value
+	selections []*selection
+	location   Location
+}
+
+// Returns the key the value of the field is returned under.
+func (field *field) key() string {
+	if field.alias != "" {
+		return field.alias
+	}
+
+	return field.name
+}
+
+// A literal or variable used as an argument. Lists and input objects hold their
+// items in list and object.
+type value struct {
+	variable string
+	literal  any
+	list     []*value
+	object   map[string]*value
+	kind     int
+}
+
+// Kinds of values.
+const (
+	literalValue = iota
+	variableValue
+	listValue
+	objectValue
+)
+
+// A punctuator, name, number or string of a document.
+type token struct {
+	kind     int
+	text     string
+	location Location
+}
+
+// Kinds of tokens.
+const (
+	endToken = iota
+	punctuatorToken
+	nameToken
+	intToken
+	floatToken
+	stringToken
+)
+
+// Splits a document into its tokens, skipping whitespace, commas and comments.
+func lex(source string) ([]token, error) {
+	var tokens []token
+	var line, lineStart int = 1, 0
+
+	for index := 0; index < len(source); {
+		character := source[index]
+		location := Location{Line: line, Column: index - lineStart + 1}
+
+		switch {
+		case character == '\n':
+			index++
+			line, lineStart = line+1, index
+
+		case character == ' ' || character == '\t' || character == '\r' || character == ',':
+			index++
+
+		case character == '#':
+			for index < len(source) && source[index] != '\n' {
+				index++
+			}
+
+		case strings.HasPrefix(source[index:], "..."):
+			tokens = append(tokens, token{kind: punctuatorToken, text: "...", location: location})
+			index += 3
+
+		case strings.IndexByte("!$():=@[]{}|", character) >= 0:
+			tokens = append(tokens, token{kind: punctuatorToken, text: string(character), location: location})
+			index++
+
+		case character == '_' || isLetter(character):
+			start := index
+			for index < len(source) && (source[index] == '_' || isLetter(source[index]) || isDigit(source[index])) {
+				index++
+			}
+
+			tokens = append(tokens, token{kind: nameToken, text: source[start:index], location: location})
+
+		case character == '-' || isDigit(character):
+			start, kind := index, intToken
+			index++
+
+			for index < len(source) && (isDigit(source[index]) || strings.IndexByte(".eE+-", source[index]) >= 0) {
+				if !isDigit(source[index]) {
+					kind = floatToken
+				}
+
+				index++
+			}
+
+			tokens = append(tokens, token{kind: kind, text: source[start:index], location: location})
+
+		case character == '"':
+			if strings.HasPrefix(source[index:], `"""`) {
+				end := strings.Index(source[index+3:], `"""`)
+				if end < 0 {
+					return nil, locatedError(location, "Unterminated block string.")
+				}
+
+				text := source[index+3 : index+3+end]
+				line += strings.Count(text, "\n")
+				tokens = append(tokens, token{kind: stringToken, text: strings.TrimSpace(text), location: location})
+				index += end + 6
+				continue
+			}
+
+			end := index + 1
+			for end < len(source) && source[end] != '"' && source[end] != '\n' {
+				if source[end] == '\\' {
+					end++
+				}
+
+				end++
+			}
+
+			if end >= len(source) || source[end] != '"' {
+				return nil, locatedError(location, "Unterminated string.")
+			}
+
+			text, err := strconv.Unquote(source[index : end+1])
+			if err != nil {
+				return nil, locatedError(location, "Invalid string.")
+			}
+
+			tokens = append(tokens, token{kind: stringToken, text: text, location: location})
+			index = end + 1
+
+		default:
+			return nil, locatedError(location, fmt.Sprintf("Unexpected character %q.", character))
+		}
+	}
+
+	return append(tokens, token{kind: endToken, location: Location{Line: line, Column: len(source) - lineStart + 1}}), nil
+}
+
+func isLetter(character byte) bool {
+	return (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+}
+
+func isDigit(character byte) bool {
+	return character >= '0' && character <= '9'
+}
+
+// A recursive descent parser over the tokens of a document.
+type parser struct {
+	tokens []token
+	index  int
+}
+
+// Parses a document. Directives are not supported.
+func parse(source string) (*document, error) {
+	var document document = document{fragments: map[string]*fragment{}}
+
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := parser{tokens: tokens}
+
+	for parser.peek().kind != endToken {
+		switch {
+		case parser.peek().text == "{":
+			selections, err := parser.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+
+			document.operations = append(document.operations, &operation{kind: "query", selections: selections})
+
+		case parser.peek().text == "fragment":
+			parser.index++
+
+			name, err := parser.name()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := parser.expect("on"); err != nil {
+				return nil, err
+			}
+
+			typeCondition, err := parser.name()
+			if err != nil {
+				return nil, err
+			}
+
+			selections, err := parser.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+
+			document.fragments[name] = &fragment{typeCondition: typeCondition, selections: selections}
+
+		case parser.peek().text == "query" || parser.peek().text == "mutation" || parser.peek().text == "subscription":
+			operation, err := parser.operation()
+			if err != nil {
+				return nil, err
+			}
+
+			document.operations = append(document.operations, operation)
+
+		default:
+			return nil, parser.unexpected()
+		}
+	}
+
+	if len(document.operations) == 0 {
+		return nil, locatedError(parser.peek().location, "The document contains no operation.")
+	}
+
+	return &document, nil
+}
+
+func (parser *parser) peek() token {
+	return parser.tokens[parser.index]
+}
+
+func (parser *parser) unexpected() error {
+	token := parser.peek()
+
+	if token.kind == endToken {
+		return locatedError(token.location, "Unexpected end of the document.")
+	}
+
+	return locatedError(token.location, fmt.Sprintf("Unexpected %q.", token.text))
+}
+
+// Moves past the current token if it is the given punctuator or keyword, and
+// fails otherwise.
+func (parser *parser) expect(text string) (token, error) {
+	token := parser.peek()
+
+	if token.text != text || token.kind == stringToken {
+		return token, locatedError(token.location, fmt.Sprintf("Expected %q, found %q.", text, token.text))
+	}
+
+	parser.index++
+	return token, nil
+}
+
+// Reports whether the current token is the given punctuator, and moves past it
+// if it is.
+func (parser *parser) accept(text string) bool {
+	if parser.peek().kind != punctuatorToken || parser.peek().text != text {
+		return false
+	}
+
+	parser.index++
+	return true
+}
+
+func (parser *parser) name() (string, error) {
+	token := parser.peek()
+
+	if token.kind != nameToken {
+		return "", parser.unexpected()
+	}
+
+	parser.index++
+	return token.text, nil
+}
+
+// operation = ("query" | "mutation" | "subscription") [name] [variables] selectionSet
+func (parser *parser) operation() (*operation, error) {
+	var operation operation = operation{kind: parser.peek().text}
+
+	parser.index++
+
+	if parser.peek().kind == nameToken {
+		operation.name = parser.peek().text
+		parser.index++
+	}
+
+	if parser.accept("(") {
+		for !parser.accept(")") {
+			var definition variableDefinition = variableDefinition{location: parser.peek().location}
+
+			if _, err := parser.expect("$"); err != nil {
+				return nil, err
+			}
+
+			name, err := parser.name()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := parser.expect(":"); err != nil {
+				return nil, err
+			}
+
+			typeName, err := parser.typeReference()
+			if err != nil {
+				return nil, err
+			}
+
+			definition.name, definition.typeName = name, typeName
+
+			if parser.accept("=") {
+				if definition.defaultValue, err = parser.value(true); err != nil {
+					return nil, err
+				}
+			}
+
+			operation.variables = append(operation.variables, &definition)
+		}
+	}
+
+	selections, err := parser.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	operation.selections = selections
+	return &operation, nil
+}
+
+// typeReference = name ["!"] | "[" typeReference "]" ["!"]
+func (parser *parser) typeReference() (string, error) {
+	var typeName string
+
+	if parser.accept("[") {
+		inner, err := parser.typeReference()
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := parser.expect("]"); err != nil {
+			return "", err
+		}
+
+		typeName = "[" + inner + "]"
+	} else {
+		name, err := parser.name()
+		if err != nil {
+			return "", err
+		}
+
+		typeName = name
+	}
+
+	if parser.accept("!") {
+		typeName += "!"
+	}
+
+	return typeName, nil
+}
+
+// selectionSet = "{" { field | "..." name | "..." ["on" name] selectionSet } "}"
+func (parser *parser) selectionSet() ([]*selection, error) {
+	var selections []*selection
+
+	if _, err := parser.expect("{"); err != nil {
+		return nil, err
+	}
+
+	for !parser.accept("}") {
+		location := parser.peek().location
+
+		if parser.accept("...") {
+			if parser.peek().kind == nameToken && parser.peek().text != "on" {
+				selections = append(selections, &selection{spread: parser.peek().text, location: location})
+				parser.index++
+				continue
+			}
+
+			var inline fragment
+
+			if parser.peek().text == "on" {
+				parser.index++
+
+				typeCondition, err := parser.name()
+				if err != nil {
+					return nil, err
+				}
+
+				inline.typeCondition = typeCondition
+			}
+
+			children, err := parser.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+
+			inline.selections = children
+			selections = append(selections, &selection{inline: &inline, location: location})
+			continue
+		}
+
+		field, err := parser.field()
+		if err != nil {
+			return nil, err
+		}
+
+		selections = append(selections, &selection{field: field, location: location})
+	}
+
+	if len(selections) == 0 {
+		return nil, parser.unexpected()
+	}
+
+	return selections, nil
+}
+
+// field = [alias ":"] name [arguments] [selectionSet]
+func (parser *parser) field() (*field, error) {
+	var field field = field{location: parser.peek().location}
+
+	name, err := parser.name()
+	if err != nil {
+		return nil, err
+	}
+
+	field.name = name
+
+	if parser.accept(":") {
+		if field.name, err = parser.name(); err != nil {
+			return nil, err
+		}
+
+		field.alias = name
+	}
+
+	if parser.peek().text == "@" {
+		return nil, locatedError(parser.peek().location, "Directives are not supported.")
+	}
+
+	if parser.accept("(") {
+		field.arguments = map[string]*value{}
+
+		for !parser.accept(")") {
+			name, err := parser.name()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := parser.expect(":"); err != nil {
+				return nil, err
+			}
+
+			if field.arguments[name], err = parser.value(false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if parser.peek().kind == punctuatorToken && parser.peek().text == "{" {
+		if field.selections, err = parser.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &field, nil
+}
+
+// value = "$" name | int | float | string | true | false | null | enum | list | object
+func (parser *parser) value(constant bool) (*value, error) {
+	token := parser.peek()
+
+	switch {
+	case token.kind == punctuatorToken && token.text == "$" && !constant:
+		parser.index++
+
+		name, err := parser.name()
+		if err != nil {
+			return nil, err
+		}
+
+		return &value{kind: variableValue, variable: name}, nil
+
+	case token.kind == punctuatorToken && token.text == "[":
+		var list value = value{kind: listValue}
+		parser.index++
+
+		for !parser.accept("]") {
+			item, err := parser.value(constant)
+			if err != nil {
+				return nil, err
+			}
+
+			list.list = append(list.list, item)
+		}
+
+		return &list, nil
+
+	case token.kind == punctuatorToken && token.text == "{":
+		var object value = value{kind: objectValue, object: map[string]*value{}}
+		parser.index++
+
+		for !parser.accept("}") {
+			name, err := parser.name()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := parser.expect(":"); err != nil {
+				return nil, err
+			}
+
+			if object.object[name], err = parser.value(constant); err != nil {
+				return nil, err
+			}
+		}
+
+		return &object, nil
+
+	case token.kind == intToken:
+		parser.index++
+
+		number, err := strconv.Atoi(token.text)
+		if err != nil {
+			return nil, locatedError(token.location, fmt.Sprintf("Invalid integer %q.", token.text))
+		}
+
+		return &value{literal: number}, nil
+
+	case token.kind == floatToken:
+		parser.index++
+
+		number, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, locatedError(token.location, fmt.Sprintf("Invalid number %q.", token.text))
+		}
+
+		return &value{literal: number}, nil
+
+	case token.kind == stringToken:
+		parser.index++
+		return &value{literal: token.text}, nil
+
+	case token.kind == nameToken:
+		parser.index++
+
+		switch token.text {
+		case "true":
+			return &value{literal: true}, nil
+		case "false":
+			return &value{literal: false}, nil
+		case "null":
+			return &value{literal: nil}, nil
+		default:
+			return &value{literal: token.text}, nil
+		}
+	}
+
+	return nil, parser.unexpected()
+}
@@ -0,0 +1,120 @@
+package graphql
+
+import (
+	"strings"
+)
+
+// Resolves a field for many parent objects at once, returning one value for
+// every parent in the same order. Resolving in batches lets a field query the
+// database once for a whole list instead of once for every item in it.
+//
+// Values of list fields must be []any. Values of object fields are handed to
+// the resolvers of the fields of that object as their parents.
+type Resolver func(parents []any, arguments map[string]any) ([]any, error)
+
+// A field of an object type. Type is written the way GraphQL writes it, such as
+// "String!" or "[Episode!]!", and so are the types of the arguments.
+type Field struct {
+	Type      string
+	Arguments map[string]string
+	Resolve   Resolver
+}
+
+// An object type along with its fields, keyed by their names.
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// The types a query can select from, starting at the query type, along with the
+// limits queries must stay within.
+type Schema struct {
+	Query *Object
+
+	// Most levels of fields and fragments a query may nest.
+	MaxDepth int
+
+	// Most fields a query may select, counting the fields of list items once for
+	// every item a list is expected to hold.
+	MaxComplexity int
+
+	// Most items a list is counted as holding, however many its first argument
+	// asks for. Resolvers should return no more than that.
+	MaxListSize int
+
+	objects map[string]*Object
+}
+
+// Number of items lists without a first argument are assumed to hold when the
+// complexity of a query is measured.
+const assumedListSize int = 10
+
+// Returns a schema starting at query, which can select from objects.
+func NewSchema(query *Object, objects ...*Object) *Schema {
+	var schema Schema = Schema{
+		Query:         query,
+		MaxDepth:      10,
+		MaxComplexity: 5000,
+		MaxListSize:   100,
+		objects:       map[string]*Object{query.Name: query},
+	}
+
+	for _, object := range objects {
+		schema.objects[object.Name] = object
+	}
+
+	return &schema
+}
+
+// Returns a resolver that resolves every parent on its own with get, for fields
+// whose value is already at hand, such as the columns of a row.
+func Each(get func(parent any) any) Resolver {
+	return func(parents []any, arguments map[string]any) ([]any, error) {
+		var values []any = make([]any, len(parents))
+
+		for index, parent := range parents {
+			values[index] = get(parent)
+		}
+
+		return values, nil
+	}
+}
+
+// A position within a document, counting lines and columns from 1.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// An error as GraphQL reports it, pointing at where in the document and the
+// response it happened.
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+func locatedError(location Location, message string) *Error {
+	return &Error{Message: message, Locations: []Location{location}}
+}
+
+// Returns the name of the type within a type reference, such as "Episode" for
+// "[Episode!]!".
+func namedType(typeName string) string {
+	return strings.Trim(typeName, "[]!")
+}
+
+// Reports whether a type reference is a list.
+func isList(typeName string) bool {
+	return strings.HasPrefix(typeName, "[")
+}
+
+// Reports whether a type reference may not be null.
+func isRequired(typeName string) bool {
+	return strings.HasSuffix(typeName, "!")
+}
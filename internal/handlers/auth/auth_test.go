@@ -0,0 +1,179 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+// Returns the session cookie a response sets, failing the test if it sets none.
+func sessionCookie(t *testing.T, response *servertest.Response) *http.Cookie {
+	t.Helper()
+
+	for _, line := range response.Header.Values("Set-Cookie") {
+		cookie, err := http.ParseSetCookie(line)
+		if err == nil && cookie.Name == access.SessionCookie {
+			return cookie
+		}
+	}
+
+	t.Fatalf("no session cookie was set: %v", response.Header.Values("Set-Cookie"))
+	return nil
+}
+
+func TestSetup(t *testing.T) {
+	server := servertest.New(t)
+	client := server.Client(t)
+
+	if required, _ := client.Send("GET", "/api/v1/setup").Expect(200).Data()["required"].(bool); !required {
+		t.Fatal("a server without users does not require setup")
+	}
+
+	client.Send("GET", "/api/v1/shows").Expect(401)
+
+	client.Form("POST", "/api/v1/setup", url.Values{
+		"username": {"admin"},
+		"password": {"short"},
+	}).Expect(400)
+
+	response := client.Form("POST", "/api/v1/setup", url.Values{
+		"username": {servertest.AdminUsername},
+		"password": {servertest.AdminPassword},
+	}).Expect(201)
+
+	if role := response.String("role"); role != "admin" {
+		t.Errorf("the first user is a %q rather than an admin", role)
+	}
+
+	if cookie := sessionCookie(t, response); !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("the session cookie can be read by scripts or sent cross-site: %v", cookie)
+	}
+
+	if strings.Contains(string(response.Body), "password") {
+		t.Errorf("the password hash was returned: %s", response.Body)
+	}
+
+	// The admin is logged in right away.
+	if username := client.Send("GET", "/api/v1/auth/me").Expect(200).String("username"); username != servertest.AdminUsername {
+		t.Errorf("logged in as %q rather than the admin", username)
+	}
+
+	// Only the first user can be created through setup.
+	stranger := server.Client(t)
+	if kind := stranger.Form("POST", "/api/v1/setup", url.Values{
+		"username": {"stranger"},
+		"password": {"password456"},
+	}).Expect(409).Problem(); kind != problems.Conflict.URI {
+		t.Errorf("a second setup failed with %s", kind)
+	}
+
+	if required, _ := stranger.Send("GET", "/api/v1/setup").Expect(200).Data()["required"].(bool); required {
+		t.Error("a server with an admin still requires setup")
+	}
+}
+
+func TestLogin(t *testing.T) {
+	server := servertest.New(t)
+	server.Admin(t)
+
+	client := server.Client(t)
+
+	for _, credentials := range []url.Values{
+		{"username": {servertest.AdminUsername}, "password": {"wrong password"}},
+		{"username": {"nobody"}, "password": {servertest.AdminPassword}},
+	} {
+		response := client.Form("POST", "/api/v1/auth/login", credentials).Expect(401)
+		if len(response.Header.Values("Set-Cookie")) > 0 {
+			t.Errorf("a failed login set a cookie: %v", response.Header.Values("Set-Cookie"))
+		}
+	}
+
+	client.Send("GET", "/api/v1/auth/me").Expect(401)
+
+	response := client.Form("POST", "/api/v1/auth/login", url.Values{
+		"username": {strings.ToUpper(servertest.AdminUsername)},
+		"password": {servertest.AdminPassword},
+	}).Expect(200)
+
+	cookie := sessionCookie(t, response)
+	if time.Until(cookie.Expires) < 24*time.Hour {
+		t.Errorf("the session expires at %v already", cookie.Expires)
+	}
+
+	client.Send("GET", "/api/v1/auth/me").Expect(200)
+
+	// Sessions are stored by a digest of their token only.
+	var stored int
+	if err := server.DB.QueryRow("SELECT COUNT(*) FROM sessions WHERE id = ?", cookie.Value).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored != 0 {
+		t.Error("the session token is stored as is")
+	}
+}
+
+func TestLogout(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	other := server.Client(t)
+
+	response := other.Form("POST", "/api/v1/auth/login", url.Values{
+		"username": {servertest.AdminUsername},
+		"password": {servertest.AdminPassword},
+	}).Expect(200)
+
+	token := sessionCookie(t, response).Value
+
+	response = other.Send("POST", "/api/v1/auth/logout").Expect(200)
+	if cookie := sessionCookie(t, response); cookie.MaxAge >= 0 {
+		t.Errorf("logging out did not clear the cookie: %v", cookie)
+	}
+
+	other.Send("GET", "/api/v1/auth/me").Expect(401)
+
+	// The session is ended on the server, so the token is useless even to
+	// clients that ignored the cleared cookie.
+	replayed := server.Client(t)
+	replayed.Send("GET", "/api/v1/auth/me", "Cookie", access.SessionCookie+"="+token).Expect(401)
+
+	// Other sessions of the user are left alone.
+	admin.Send("GET", "/api/v1/auth/me").Expect(200)
+}
+
+func TestSessions(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+
+	admin.Send("GET", "/api/v1/auth/me").Expect(200)
+
+	// Expired sessions are rejected and their cookie is cleared.
+	if _, err := server.DB.Exec(
+		"UPDATE sessions SET expiry_date = ?",
+		time.Now().Add(-time.Minute).Format("2006-01-02 15:04:05"),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	response := admin.Send("GET", "/api/v1/auth/me").Expect(401)
+	if cookie := sessionCookie(t, response); cookie.MaxAge >= 0 {
+		t.Errorf("the cookie of an expired session was not cleared: %v", cookie)
+	}
+
+	// Made up tokens are rejected as well.
+	server.Client(t).Send("GET", "/api/v1/shows", "Cookie", access.SessionCookie+"=made-up").Expect(401)
+
+	// Logging in again starts a new session.
+	admin.Form("POST", "/api/v1/auth/login", url.Values{
+		"username": {servertest.AdminUsername},
+		"password": {servertest.AdminPassword},
+	}).Expect(200)
+
+	admin.Send("GET", "/api/v1/shows").Expect(200)
+}
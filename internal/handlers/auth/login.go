@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Hash that unknown usernames are checked against, so that logging in takes as
+// long for unknown usernames as it does for wrong passwords.
+const placeholderHash string = "$2a$10$sSv80pDrLOhttKJhI0n7yeFqe4f6eibfdV6ko9w9mCPP8BbMBZFLi"
+
+// Logs a user in by checking their password and starting a new session. The
+// session token is handed to the client in an HttpOnly cookie. Users with
+// two-factor authentication get a challenge instead, which is completed through
+// /auth/login/2fa.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /auth/login
+//   - Auth?       : False
+//
+// # HTTP request multipart form:
+//   - username    : REQUIRED. Username of the user.
+//   - password    : REQUIRED. Password of the user.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The logged in user, or a challenge when the status is 202.
+func Login(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var passwordHash string = placeholderHash
+
+	user, err := queries.UserByUsername(database, r.FormValue("username"))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if user != nil {
+		passwordHash = user.PasswordHash
+	}
+
+	if !secrets.Verify(passwordHash, r.FormValue("password")) || user == nil {
+		log.Info(functionId, "Rejected login with a wrong username or password")
+		return problems.New(problems.Unauthorized, "The username or password is wrong.")
+	}
+
+	// Challenges are handed out even while the second factor of the user is
+	// locked, as refusing them would tell that the password was right. Codes
+	// are limited per user rather than per challenge, see checkSecondFactor.
+	if user.TwoFactorEnabled {
+		challenge, expiry, err := queries.CreateChallenge(database, user.Id)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to create login challenge. %v", err))
+			return err
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		responses.Status{
+			Status: 202,
+			Data: types.LoginChallenge{
+				TwoFactorRequired: true,
+				Challenge:         challenge,
+				ExpiryDate:        expiry.Format("2006-01-02 15:04:05"),
+			},
+		}.ToClient(w)
+		return nil
+	}
+
+	token, expiry, err := queries.CreateSession(database, user.Id)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to create session. %v", err))
+		return err
+	}
+
+	access.SetSessionCookie(w, r, token, expiry)
+
+	responses.Status{
+		Status: 200,
+		Data:   user,
+	}.ToClient(w)
+	return nil
+}
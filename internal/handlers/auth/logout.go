@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Logs the user out by ending the session the request was made with.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /auth/logout
+//   - Auth?       : True
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Logout(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+
+	if err := queries.DeleteSession(database, access.SessionToken(r)); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete session. %v", err))
+		return err
+	}
+
+	access.ClearSessionCookie(w, r)
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Returns the user the request was authenticated as.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /auth/me
+//   - Auth?       : True
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The logged in user.
+func Me(
+  w http.ResponseWriter,
+  r *http.Request,
+) error {
+	responses.Status{
+		Status: 200,
+		Data:   access.User(r),
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,440 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+	"github.com/andrewdotjs/watchify-server/internal/totp"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Name that authenticator apps show next to the codes of the server.
+const totpIssuer string = "Watchify"
+
+// Number of recovery codes handed out at once.
+const recoveryCodeCount int = 10
+
+// Completes a login that was answered with a challenge by checking the second
+// factor, either a TOTP code or an unused recovery code.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /auth/login/2fa
+//   - Auth?       : False
+//
+// # HTTP request multipart form:
+//   - challenge   : REQUIRED. Challenge returned by /auth/login.
+//   - code        : REQUIRED. TOTP code or recovery code.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The logged in user.
+func LoginTwoFactor(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var challenge string = r.FormValue("challenge")
+
+	user, err := queries.ChallengeUser(database, challenge)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return problems.New(problems.Unauthorized, "The challenge has expired or ran out of attempts. Log in again.")
+		}
+
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if err := checkSecondFactor(database, user, w, r); err != nil {
+		log.Info(functionId, "Rejected login with a wrong second factor")
+		return err
+	}
+
+	if err := queries.DeleteChallenge(database, challenge); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete login challenge. %v", err))
+	}
+
+	token, expiry, err := queries.CreateSession(database, user.Id)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to create session. %v", err))
+		return err
+	}
+
+	access.SetSessionCookie(w, r, token, expiry)
+
+	responses.Status{
+		Status: 200,
+		Data:   user,
+	}.ToClient(w)
+	return nil
+}
+
+// Starts enrolling the logged in user in two-factor authentication by creating
+// a new TOTP secret. Two-factor authentication is only enabled once a code of
+// the secret is confirmed through /auth/2fa/confirm.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /auth/2fa
+//   - Auth?       : True
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The secret and its otpauth provisioning URI.
+func EnrollTwoFactor(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var user *types.User = access.User(r)
+
+	if err := requireAccount(r); err != nil {
+		return err
+	}
+
+	if user.TwoFactorEnabled {
+		return problems.New(problems.Conflict, "Two-factor authentication is already enabled. Disable it first to enroll a new device.")
+	}
+
+	secret, err := totp.Secret()
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to generate TOTP secret. %v", err))
+		return err
+	}
+
+	if _, err := database.Exec(`
+		UPDATE
+			users
+		SET
+			totp_secret = ?, totp_counter = 0
+		WHERE
+			id = ?
+		`,
+		secret,
+		user.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to store TOTP secret. %v", err))
+		return err
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	responses.Status{
+		Status: 200,
+		Data: types.TwoFactorEnrollment{
+			Secret:          secret,
+			ProvisioningUri: totp.ProvisioningUri(totpIssuer, user.Username, secret),
+		},
+	}.ToClient(w)
+	return nil
+}
+
+// Enables two-factor authentication for the logged in user once they enter a
+// code of the secret created by /auth/2fa. Returns recovery codes, which can
+// each be used once in place of a code when the device is lost.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /auth/2fa/confirm
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - code        : REQUIRED. TOTP code of the new secret.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The recovery codes. They are not shown again.
+func ConfirmTwoFactor(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var user *types.User = access.User(r)
+
+	if err := requireAccount(r); err != nil {
+		return err
+	}
+
+	if user.TwoFactorEnabled || user.TotpSecret == "" {
+		return problems.New(problems.Conflict, "There is no pending enrollment. Start one through /auth/2fa.")
+	}
+
+	counter, valid := totp.Verify(user.TotpSecret, r.FormValue("code"), time.Now(), user.TotpCounter)
+	if !valid {
+		return problems.New(problems.InvalidRequest, "The code does not match. Check the clock of the device.")
+	}
+
+	if _, err := database.Exec(`
+		UPDATE
+			users
+		SET
+			totp_enabled = 1, totp_counter = ?, last_modified = ?
+		WHERE
+			id = ?
+		`,
+		counter,
+		time.Now().Format("2006-01-02 15:04:05"),
+		user.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to enable two-factor authentication. %v", err))
+		return err
+	}
+
+	codes, err := replaceRecoveryCodes(database, user.Id)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to create recovery codes. %v", err))
+		return err
+	}
+
+	log.Info(functionId, fmt.Sprintf("User %s enabled two-factor authentication", user.Username))
+
+	w.Header().Set("Cache-Control", "no-store")
+	responses.Status{
+		Status: 200,
+		Data:   codes,
+	}.ToClient(w)
+	return nil
+}
+
+// Replaces the recovery codes of the logged in user with new ones.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /auth/2fa/recovery-codes
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - code        : REQUIRED. TOTP code or recovery code.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The new recovery codes. They are not shown again.
+func RegenerateRecoveryCodes(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var user *types.User = access.User(r)
+
+	if err := requireEnabled(r); err != nil {
+		return err
+	}
+
+	if err := checkSecondFactor(database, user, w, r); err != nil {
+		return err
+	}
+
+	codes, err := replaceRecoveryCodes(database, user.Id)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to create recovery codes. %v", err))
+		return err
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	responses.Status{
+		Status: 200,
+		Data:   codes,
+	}.ToClient(w)
+	return nil
+}
+
+// Disables two-factor authentication for the logged in user. Not allowed when
+// the require_two_factor setting applies to their role.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /auth/2fa
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - code        : REQUIRED. TOTP code or recovery code.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func DisableTwoFactor(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var user *types.User = access.User(r)
+
+	if err := requireEnabled(r); err != nil {
+		return err
+	}
+
+	required, err := queries.TwoFactorRequired(database, user.Role)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if required {
+		return problems.New(problems.Conflict, "Two-factor authentication is required for your role and can not be disabled.")
+	}
+
+	if err := checkSecondFactor(database, user, w, r); err != nil {
+		return err
+	}
+
+	if err := queries.ResetTwoFactor(database, user.Id); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to disable two-factor authentication. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks the code form value against the TOTP secret and the recovery codes of
+// the user. Used recovery codes are removed, and TOTP codes can not be used
+// twice. Wrong codes are counted against the user rather than the login
+// challenge, and once too many were entered no code is checked for a while, so
+// that codes can't be guessed by logging in again and again. Returns the
+// problem to send to the client if the code is wrong or can't be entered yet.
+func checkSecondFactor(database *sql.DB, user *types.User, w http.ResponseWriter, r *http.Request) error {
+	var code string = strings.TrimSpace(r.FormValue("code"))
+	var statement string
+	var arguments []any
+
+	lockedUntil, err := queries.TwoFactorLockedUntil(database, user.Id)
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to check the code.")
+	}
+
+	if wait := time.Until(lockedUntil); wait > 0 {
+		return twoFactorLocked(w, wait)
+	}
+
+	if counter, valid := totp.Verify(user.TotpSecret, code, time.Now(), user.TotpCounter); valid {
+		// Only moving the counter forward keeps concurrent requests from both
+		// using the same code.
+		statement = "UPDATE users SET totp_counter = ? WHERE id = ? AND totp_counter < ?"
+		arguments = []any{counter, user.Id, counter}
+	} else {
+		statement = "DELETE FROM recovery_codes WHERE user_id = ? AND digest = ?"
+		arguments = []any{user.Id, secrets.Digest(normalizeRecoveryCode(code))}
+	}
+
+	result, err := database.Exec(statement, arguments...)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 1 {
+		if err := queries.ResetTwoFactorFailures(database, user.Id); err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to check the code.")
+		}
+
+		return nil
+	}
+
+	lockedUntil, err = queries.FailTwoFactor(database, user.Id)
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to check the code.")
+	}
+
+	if wait := time.Until(lockedUntil); wait > 0 {
+		return twoFactorLocked(w, wait)
+	}
+
+	return problems.New(problems.Unauthorized, "The code is wrong or was already used.")
+}
+
+// Returns the problem telling the client that no code can be entered for wait,
+// which the Retry-After header carries in seconds.
+func twoFactorLocked(w http.ResponseWriter, wait time.Duration) error {
+	var seconds int = int(math.Ceil(wait.Seconds()))
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	return problems.New(problems.TooManyAttempts, fmt.Sprintf("Too many wrong codes were entered. Try again in %d seconds.", seconds))
+}
+
+// Removes every recovery code of the user and creates new ones. Only the digests
+// of the codes are stored.
+func replaceRecoveryCodes(database *sql.DB, userId string) ([]string, error) {
+	var codes []string = []string{}
+
+	if _, err := database.Exec("DELETE FROM recovery_codes WHERE user_id = ?", userId); err != nil {
+		return nil, err
+	}
+
+	for range recoveryCodeCount {
+		secret, err := totp.Secret()
+		if err != nil {
+			return nil, err
+		}
+
+		code := strings.ToLower(secret[:5] + "-" + secret[5:10])
+
+		if _, err := database.Exec(`
+			INSERT INTO
+				recovery_codes (user_id, digest)
+			VALUES
+				(?, ?)
+			`,
+			userId,
+			secrets.Digest(normalizeRecoveryCode(code)),
+		); err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// Strips the formatting of a recovery code so that it matches however it was
+// typed.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// Checks that the request may change the two-factor settings of its user.
+// Returns the problem to send to the client if it may not.
+func requireAccount(r *http.Request) error {
+	if access.FullAccess(r) {
+		return nil
+	}
+
+	return problems.New(problems.Forbidden, "Two-factor authentication can only be managed with a session or an admin scoped API token.")
+}
+
+// Checks that the request may change the two-factor settings of its user and
+// that two-factor authentication is enabled. Returns the problem to send to the
+// client otherwise.
+func requireEnabled(r *http.Request) error {
+	if err := requireAccount(r); err != nil {
+		return err
+	}
+
+	if access.User(r).TwoFactorEnabled {
+		return nil
+	}
+
+	return problems.New(problems.Conflict, "Two-factor authentication is not enabled.")
+}
@@ -0,0 +1,89 @@
+package auth_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+	"github.com/andrewdotjs/watchify-server/internal/totp"
+)
+
+// Enables two-factor authentication for the client with the code of the
+// current time step. Returns the secret.
+func enroll(t *testing.T, client *servertest.Client) string {
+	t.Helper()
+
+	secret := client.Send("POST", "/api/v1/auth/2fa").Expect(200).String("secret")
+
+	code, err := totp.Code(secret, totp.Counter(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.Form("POST", "/api/v1/auth/2fa/confirm", url.Values{"code": {code}}).Expect(200)
+	return secret
+}
+
+// Logs the admin in with their password, and returns the challenge.
+func challenge(server *servertest.Server, t *testing.T) string {
+	t.Helper()
+
+	return server.Client(t).Form("POST", "/api/v1/auth/login", url.Values{
+		"username": {servertest.AdminUsername},
+		"password": {servertest.AdminPassword},
+	}).Expect(202).String("challenge")
+}
+
+func TestTwoFactorLockout(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	secret := enroll(t, admin)
+	client := server.Client(t)
+
+	// Every challenge allows a few attempts, but wrong codes add up across
+	// challenges, so that logging in again does not allow guessing on.
+	for attempt := 1; attempt < queries.TwoFactorAttempts; attempt++ {
+		client.Form("POST", "/api/v1/auth/login/2fa", url.Values{
+			"challenge": {challenge(server, t)},
+			"code":      {"000000"},
+		}).Expect(401)
+	}
+
+	locked := client.Form("POST", "/api/v1/auth/login/2fa", url.Values{
+		"challenge": {challenge(server, t)},
+		"code":      {"000000"},
+	}).Expect(429)
+
+	if kind := locked.Problem(); kind != problems.TooManyAttempts.URI || locked.Header.Get("Retry-After") == "" {
+		t.Errorf("the lockout was answered with %s, Retry-After %q", kind, locked.Header.Get("Retry-After"))
+	}
+
+	// While locked, not even the right code is checked, nor does it count as
+	// having been used.
+	code, err := totp.Code(secret, totp.Counter(time.Now())+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.Form("POST", "/api/v1/auth/login/2fa", url.Values{"challenge": {challenge(server, t)}, "code": {code}}).Expect(429)
+	admin.Form("DELETE", "/api/v1/auth/2fa", url.Values{"code": {code}}).Expect(429)
+
+	if _, err := server.DB.Exec("UPDATE two_factor_failures SET locked_until = '2000-01-01 00:00:00'"); err != nil {
+		t.Fatal(err)
+	}
+
+	client.Form("POST", "/api/v1/auth/login/2fa", url.Values{"challenge": {challenge(server, t)}, "code": {code}}).Expect(200)
+
+	// The right code forgets the wrong ones.
+	var failures int
+	if err := server.DB.QueryRow("SELECT COUNT(*) FROM two_factor_failures").Scan(&failures); err != nil {
+		t.Fatal(err)
+	}
+
+	if failures != 0 {
+		t.Error("the wrong codes are still counted after the right one")
+	}
+}
@@ -0,0 +1,199 @@
+package batch
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/middleware"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Largest number of operations in a batch.
+const maximumOperations int = 1000
+
+// Largest request body read.
+const maximumBody int64 = 1 << 20
+
+type request struct {
+	Atomic     *bool                  `json:"atomic"`
+	Operations []types.BatchOperation `json:"operations"`
+}
+
+// Executes a batch of operations on the library in a single transaction, and
+// returns the outcome of each of them.
+//
+// Atomic batches are all-or-nothing: the first failing operation rolls back
+// every other one, which are then reported as failed dependencies. Otherwise,
+// operations that fail are rolled back on their own and the rest is kept.
+// Files of deleted shows and movies are only removed once the batch is
+// committed. The shows, movies and episodes a batch changes are locked until
+// then, just as their own routes lock them while checking If-Match.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /batch
+//   - Auth?       : True
+//
+// # HTTP request JSON contents:
+//   - atomic      : OPTIONAL. false to keep the operations that succeed, true by default.
+//   - operations  : REQUIRED. At most 1000 operations, each made of op and id, where op is
+//     "hide", "unhide", "tag" along with tag_id, "delete", or "move" along with show_id to
+//     move the episode with the id to that series. Each must hold an if_match ETag, or *,
+//     which the show, movie or episode must still match, as with the If-Match of its own
+//     routes.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : atomic, committed, and the results of the operations in order, each
+//     returning op, id, status, and the type and detail of the problem if it failed.
+func Execute(
+  w http.ResponseWriter,
+  r *http.Request,
+  db *sql.DB,
+  appDirectory *string,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var body request
+	var batch types.Batch = types.Batch{Results: []types.BatchResult{}}
+	var files []string // removed once the batch is committed
+	var failed int = -1
+
+	if err := json.NewDecoder(io.LimitReader(r.Body, maximumBody)).Decode(&body); err != nil {
+		return problems.New(problems.InvalidRequest, fmt.Sprintf("The request body is not a valid batch. %v", err))
+	}
+
+	if len(body.Operations) == 0 {
+		detail := "The operations value is required."
+		return problems.New(problems.InvalidRequest, detail, problems.Param("operations", detail))
+	}
+
+	if len(body.Operations) > maximumOperations {
+		detail := fmt.Sprintf("A batch may hold at most %d operations.", maximumOperations)
+		return problems.New(problems.InvalidRequest, detail, problems.Param("operations", detail))
+	}
+
+	batch.Atomic = body.Atomic == nil || *body.Atomic
+
+	var resources []middleware.Resource
+	for _, operation := range body.Operations {
+		if found, exists := resource(db, operation); exists {
+			resources = append(resources, found)
+		}
+	}
+
+	// Held until the files are removed as well, as the routes deleting a show or
+	// movie do.
+	defer middleware.LockResources(resources)()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to start the transaction.")
+	}
+
+	// Rolling back after committing does nothing.
+	defer tx.Rollback()
+
+	for index, operation := range body.Operations {
+		var result types.BatchResult = types.BatchResult{Op: operation.Op, Id: operation.Id, Status: 200}
+
+		if failed >= 0 {
+			result.Status = problems.FailedDependency.Status
+			result.Type = problems.FailedDependency.URI
+			result.Detail = fmt.Sprintf("Not attempted, as operation %d failed.", failed)
+			batch.Results = append(batch.Results, result)
+			continue
+		}
+
+		// Operations of batches that are not atomic are undone on their own.
+		if !batch.Atomic {
+			if _, err := tx.Exec("SAVEPOINT operation"); err != nil {
+				return problems.Wrap(problems.Internal, err, "Failed to start the operation.")
+			}
+		}
+
+		removed, err := apply(tx, r, operation, appDirectory)
+		if err != nil {
+			problem := problems.From(err)
+			result.Status = problem.Type.Status
+			result.Type = problem.Type.URI
+			result.Detail = problem.Detail
+
+			if problem.Err != nil {
+				log.Error(functionId, fmt.Sprintf("Operation %d failed. %s %v", index, problem.Detail, problem.Err))
+			}
+		}
+
+		if err != nil && batch.Atomic {
+			failed = index
+		}
+
+		// Rolling back to a savepoint keeps it, so it is released either way.
+		if err != nil && !batch.Atomic {
+			if _, err := tx.Exec("ROLLBACK TO operation"); err != nil {
+				return problems.Wrap(problems.Internal, err, "Failed to undo the operation.")
+			}
+		}
+
+		if !batch.Atomic {
+			if _, err := tx.Exec("RELEASE operation"); err != nil {
+				return problems.Wrap(problems.Internal, err, "Failed to end the operation.")
+			}
+		}
+
+		if err == nil {
+			files = append(files, removed...)
+		}
+
+		batch.Results = append(batch.Results, result)
+	}
+
+	if failed >= 0 {
+		for index := 0; index < failed; index++ {
+			batch.Results[index].Status = problems.FailedDependency.Status
+			batch.Results[index].Type = problems.FailedDependency.URI
+			batch.Results[index].Detail = fmt.Sprintf("Rolled back, as operation %d failed.", failed)
+		}
+
+		if err := tx.Rollback(); err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to roll back the batch.")
+		}
+
+		log.Info(functionId, fmt.Sprintf("Rolled back a batch of %d operations, as operation %d failed", len(body.Operations), failed))
+		responses.Status{
+			Status: 200,
+			Data:   batch,
+		}.ToClient(w)
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to commit the batch.")
+	}
+
+	batch.Committed = true
+
+	// The files are gone from the database already, so failing to remove them
+	// only leaves them behind.
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Error(functionId, fmt.Sprintf("Failed to remove %s. %v", file, err))
+		}
+	}
+
+	log.Info(functionId, fmt.Sprintf("Committed a batch of %d operations", len(body.Operations)))
+	responses.Status{
+		Status: 200,
+		Data:   batch,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,148 @@
+package batch_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/batch"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns the statuses of the operations of a batch, in order.
+func statuses(response *servertest.Response) []float64 {
+	var statuses []float64
+
+	for _, result := range response.Data()["results"].([]any) {
+		statuses = append(statuses, result.(map[string]any)["status"].(float64))
+	}
+
+	return statuses
+}
+
+func TestExecuteDelete(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	movie := admin.CreateMovie("Arrival")
+
+	var video, cover string
+	if err := server.DB.QueryRow("SELECT file_name FROM movies WHERE id = ?", movie).Scan(&video); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.DB.QueryRow("SELECT file_name FROM covers WHERE parent_id = ?", movie).Scan(&cover); err != nil {
+		t.Fatal(err)
+	}
+
+	admin.JSON("POST", "/api/v1/batch", map[string]any{
+		"operations": []map[string]any{{"op": "delete", "id": movie, "if_match": "*"}},
+	}).Expect(200)
+
+	for _, file := range []string{path.Join("videos", video), path.Join("covers", cover)} {
+		if _, err := os.Stat(path.Join(server.AppDirectory, "storage", file)); !os.IsNotExist(err) {
+			t.Errorf("%s is still stored: %v", file, err)
+		}
+	}
+
+	admin.Send("GET", "/api/v1/movies/"+movie).Expect(404)
+}
+
+func TestExecuteIfMatch(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	movie := admin.CreateMovie("Arrival")
+	show := admin.CreateShow("Andor")
+
+	stale := admin.Send("GET", "/api/v1/movies/"+movie).Expect(200).Header.Get("ETag")
+	admin.JSON("POST", "/api/v1/batch", map[string]any{
+		"operations": []map[string]any{{"op": "hide", "id": movie, "if_match": stale}},
+	}).Expect(200)
+
+	// Hiding the movie changed it, so deleting it with the ETag from before
+	// fails, and rolls back the rest of the batch along with it.
+	response := admin.JSON("POST", "/api/v1/batch", map[string]any{
+		"operations": []map[string]any{
+			{"op": "delete", "id": show, "if_match": "*"},
+			{"op": "delete", "id": movie, "if_match": stale},
+		},
+	}).Expect(200)
+
+	if got := statuses(response); got[0] != 424 || got[1] != 412 || response.Data()["committed"] != false {
+		t.Fatalf("the batch was answered with %v", response.Data())
+	}
+
+	admin.Send("GET", "/api/v1/shows/"+show).Expect(200)
+
+	current := admin.Send("GET", "/api/v1/movies/"+movie).Expect(200).Header.Get("ETag")
+	response = admin.JSON("POST", "/api/v1/batch", map[string]any{
+		"operations": []map[string]any{{"op": "delete", "id": movie, "if_match": current}},
+	}).Expect(200)
+
+	if got := statuses(response); got[0] != 200 {
+		t.Fatalf("the batch was answered with %v", response.Data())
+	}
+}
+
+// Every operation changes a show, movie or episode, which their own routes
+// only do with If-Match. The OpenAPI document requires if_match as well, so the
+// batch is run without validating it against the document.
+func TestExecuteIfMatchRequired(t *testing.T) {
+	var log logger.Logger
+
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	movie := admin.CreateMovie("Arrival")
+	show := admin.CreateShow("Andor")
+	tag := admin.Form("POST", "/api/v1/tags", url.Values{"name": {"Drama"}}).Expect(201).String("id")
+
+	var episode string
+	if err := server.DB.QueryRow("SELECT id FROM episodes WHERE parent_id = ?", show).Scan(&episode); err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(map[string]any{
+		"atomic": false,
+		"operations": []map[string]any{
+			{"op": "hide", "id": movie},
+			{"op": "unhide", "id": movie},
+			{"op": "tag", "id": movie, "tag_id": tag},
+			{"op": "move", "id": episode, "show_id": show},
+			{"op": "delete", "id": movie},
+		},
+	})
+
+	request := httptest.NewRequest("POST", "/api/v1/batch", bytes.NewReader(body))
+	request = request.WithContext(access.WithUser(request.Context(), &types.User{Id: "admin", Role: "admin"}))
+	recorder := httptest.NewRecorder()
+
+	if err := batch.Execute(recorder, request, server.DB, &server.AppDirectory, &log); err != nil {
+		t.Fatal(err)
+	}
+
+	var response struct {
+		Data types.Batch `json:"data"`
+	}
+
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Data.Results) != 5 {
+		t.Fatalf("the batch was answered with %s", recorder.Body)
+	}
+
+	for index, result := range response.Data.Results {
+		if result.Status != 428 {
+			t.Errorf("operation %d was answered with %d, want 428", index, result.Status)
+		}
+	}
+
+	admin.Send("GET", "/api/v1/movies/"+movie).Expect(200)
+}
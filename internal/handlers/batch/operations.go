@@ -0,0 +1,216 @@
+package batch
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/middleware"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// A statement of an operation, run in order.
+type statement struct {
+	query     string
+	arguments []any
+}
+
+// Operations a batch can hold.
+var operations = []string{"hide", "unhide", "tag", "delete", "move"}
+
+// Applies an operation within the transaction. Returns the files to remove
+// once the transaction is committed, or the problem the operation ran into.
+func apply(tx *sql.Tx, r *http.Request, operation types.BatchOperation, appDirectory *string) ([]string, error) {
+	if !slices.Contains(operations, operation.Op) {
+		detail := fmt.Sprintf("%q is not one of hide, unhide, tag, delete or move.", operation.Op)
+		return nil, problems.New(problems.InvalidRequest, detail, problems.Param("op", detail))
+	}
+
+	if operation.Op == "delete" && !access.Allowed(r, access.DeleteLibrary) {
+		return nil, problems.New(problems.Forbidden, fmt.Sprintf("Deleting requires the %s permission.", access.DeleteLibrary))
+	}
+
+	if err := precondition(tx, operation); err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "hide", "unhide":
+		table, err := mediaTable(tx, operation.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.Exec(
+			fmt.Sprintf("UPDATE %s SET hidden = ?, last_modified = ? WHERE id = ?", table),
+			operation.Op == "hide",
+			time.Now().Format("01-02-2006 15:04:05"),
+			operation.Id,
+		); err != nil {
+			return nil, problems.Wrap(problems.Internal, err, "Failed to change the visibility.")
+		}
+
+		return nil, nil
+
+	case "tag":
+		return nil, tag(tx, operation)
+
+	case "delete":
+		table, err := mediaTable(tx, operation.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		return remove(tx, table, operation.Id, appDirectory)
+
+	default:
+		return nil, move(tx, operation)
+	}
+}
+
+// Returns the table of the show or movie with the given id.
+func mediaTable(executor queries.Executor, id string) (string, error) {
+	for _, table := range []string{"movies", "shows"} {
+		var count int
+
+		if err := executor.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id = ?", table), id).Scan(&count); err != nil {
+			return "", problems.Wrap(problems.Internal, err, "Failed to find the show or movie.")
+		}
+
+		if count > 0 {
+			return table, nil
+		}
+	}
+
+	return "", problems.New(problems.NotFound, "No show or movie could be found with the given id.")
+}
+
+// Attaches a tag to a show or movie.
+func tag(tx *sql.Tx, operation types.BatchOperation) error {
+	var tagCount int
+
+	if _, err := mediaTable(tx, operation.Id); err != nil {
+		return err
+	}
+
+	if err := tx.QueryRow("SELECT COUNT(*) FROM tags WHERE id = ?", operation.TagId).Scan(&tagCount); err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to find the tag.")
+	}
+
+	if tagCount == 0 {
+		detail := "No tag could be found with the given tag_id."
+		return problems.New(problems.NotFound, detail, problems.Param("tag_id", detail))
+	}
+
+	if _, err := tx.Exec("INSERT OR IGNORE INTO taggings VALUES (?, ?)", operation.TagId, operation.Id); err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to attach the tag.")
+	}
+
+	return nil
+}
+
+// Moves an episode to another series, counting the episodes of both again.
+func move(tx *sql.Tx, operation types.BatchOperation) error {
+	var parentId string
+	var showCount int
+
+	if err := tx.QueryRow("SELECT COALESCE(parent_id, '') FROM episodes WHERE id = ?", operation.Id).Scan(&parentId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return problems.New(problems.NotFound, "No episode could be found with the given id.")
+		}
+
+		return problems.Wrap(problems.Internal, err, "Failed to find the episode.")
+	}
+
+	if err := tx.QueryRow("SELECT COUNT(*) FROM shows WHERE id = ?", operation.ShowId).Scan(&showCount); err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to find the series.")
+	}
+
+	if showCount == 0 {
+		detail := "No series could be found with the given show_id."
+		return problems.New(problems.NotFound, detail, problems.Param("show_id", detail))
+	}
+
+	for _, statement := range []statement{
+		{"UPDATE episodes SET parent_id = ?, last_modified = ? WHERE id = ?", []any{operation.ShowId, time.Now().Format("01-02-2006 15:04:05"), operation.Id}},
+		{"UPDATE shows SET episode_count = (SELECT COUNT(*) FROM episodes WHERE parent_id = shows.id) WHERE id IN (?, ?)", []any{parentId, operation.ShowId}},
+	} {
+		if _, err := tx.Exec(statement.query, statement.arguments...); err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to move the episode.")
+		}
+	}
+
+	return nil
+}
+
+// Deletes a show along with its episodes, or a movie, and everything attached
+// to them, the way their DELETE routes do. Returns the video and cover files
+// to remove.
+func remove(tx *sql.Tx, table string, id string, appDirectory *string) ([]string, error) {
+	files, err := queries.DeleteMedia(tx, table, id)
+	if err != nil {
+		return nil, problems.Wrap(problems.Internal, err, "Failed to delete the show or movie.")
+	}
+
+	for index, file := range files {
+		files[index] = path.Join(*appDirectory, "storage", file)
+	}
+
+	return files, nil
+}
+
+// Returns the versioned row an operation changes, whose lock is held while the
+// batch runs, or false if there is no such show, movie or episode.
+func resource(executor queries.Executor, operation types.BatchOperation) (middleware.Resource, bool) {
+	if operation.Op == "move" {
+		return middleware.Resource{Table: "episodes", Id: operation.Id}, true
+	}
+
+	table, err := mediaTable(executor, operation.Id)
+	return middleware.Resource{Table: table, Id: operation.Id}, err == nil
+}
+
+// Checks the ETag an operation sent in if_match against the version of the
+// show, movie or episode it runs on, the way If-Match is checked by the routes
+// of a single one of them, which require it as well.
+func precondition(tx *sql.Tx, operation types.BatchOperation) error {
+	var table string = "episodes"
+	var version int64
+
+	if operation.IfMatch == "" {
+		detail := "Send the ETag of the show, movie or episode in if_match to change it."
+		return problems.New(problems.PreconditionRequired, detail, problems.Param("if_match", detail))
+	}
+
+	if operation.Op != "move" {
+		found, err := mediaTable(tx, operation.Id)
+		if err != nil {
+			return err
+		}
+
+		table = found
+	}
+
+	err := tx.QueryRow(fmt.Sprintf("SELECT version FROM %s WHERE id = ?", table), operation.Id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // the operation tells the client it does not exist
+	}
+
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to retrieve the version of the resource.")
+	}
+
+	if !middleware.VersionMatches(operation.IfMatch, version) {
+		detail := "The resource changed since it was retrieved. Retrieve it again and reapply the changes."
+		return problems.New(problems.PreconditionFailed, detail, problems.Param("if_match", detail))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package contentratings
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Sets or clears the content rating of a show, movie or episode. Unrated episodes
+// inherit the content rating of their show.
+//
+// # Specifications:
+//   - Method            : PUT
+//   - Endpoint          : /shows/{id}/content-rating, /movies/{id}/content-rating, /videos/{id}/content-rating
+//   - Auth?             : True
+//
+// # HTTP request multipart form:
+//   - content_rating_id : OPTIONAL. UUID of the content rating, empty to clear it.
+//
+// # HTTP response JSON contents:
+//   - status_code       : HTTP status code.
+func Assign(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  table string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var ratingId string = r.FormValue("content_rating_id")
+	var functionId string = access.RequestId(r)
+	var value any = nil
+
+	if ratingId != "" {
+		ratings, err := queries.ContentRatings(database)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		if ratings[ratingId] == nil {
+			return problems.New(problems.InvalidRequest, "No content rating could be found with the given content_rating_id.")
+		}
+
+		value = ratingId
+	}
+
+	result, err := database.Exec(
+		fmt.Sprintf(`
+			UPDATE
+				%s
+			SET
+				content_rating_id = ?
+			WHERE
+				id = ?
+			`,
+			table,
+		),
+		value,
+		id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to assign content rating. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No content could be found with the given id.")
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,124 @@
+package contentratings
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Adds a content rating to a new or existing rating system. The rank places the
+// rating on the scale shared by every system, which profile limits compare against.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /content-ratings
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - system      : REQUIRED. Rating system, such as "MPAA", "TV" or "BBFC".
+//   - code        : REQUIRED. Rating within the system, unique per system.
+//   - rank        : REQUIRED. Position on the shared scale, higher is more mature.
+//   - description : OPTIONAL. Short explanation of the rating.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The created content rating.
+func Create(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var existing int = 0
+	var rating types.ContentRating = types.ContentRating{
+		Id:          uuid.NewString(),
+		System:      functions.Sanitize(r.FormValue("system")),
+		Code:        functions.Sanitize(r.FormValue("code")),
+		Description: functions.Sanitize(r.FormValue("description")),
+	}
+
+	rank, err := strconv.Atoi(r.FormValue("rank"))
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The rank value must be an integer.")
+	}
+
+	rating.Rank = rank
+
+	if err := validate(&rating); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			content_ratings
+		WHERE
+			system = ? AND code = ?
+		`,
+		rating.System,
+		rating.Code,
+	).Scan(&existing); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if existing > 0 {
+		return problems.New(problems.Conflict, "The rating system already contains a rating with the given code.")
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			content_ratings (id, system, code, rank, description)
+		VALUES
+			(?, ?, ?, ?, ?)
+		`,
+		rating.Id,
+		rating.System,
+		rating.Code,
+		rating.Rank,
+		rating.Description,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to insert content rating. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 201,
+		Data:   rating,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks that the values of a content rating are acceptable. Returns the
+// problem to send to the client if they are not.
+func validate(rating *types.ContentRating) error {
+	var name string = ""
+	var detail string = ""
+
+	switch {
+	case rating.System == "" || rating.Code == "":
+		name, detail = "code", "Both system and code are required."
+	case len(rating.System) > 20 || len(rating.Code) > 20:
+		name, detail = "code", "The system and code values must not be larger than 20 bytes."
+	case len(rating.Description) > 200:
+		name, detail = "description", "The description value was larger than 200 bytes. In UTF-8 encoding, English characters are 1 byte each."
+	case rating.Rank < 0:
+		name, detail = "rank", "The rank value must not be negative."
+	default:
+		return nil
+	}
+
+	return problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+}
@@ -0,0 +1,96 @@
+package contentratings
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Deletes a content rating. Content carrying the rating becomes unrated. Ratings
+// that are used as the limit of a profile can not be deleted, as that would
+// silently lift the limit.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /content-ratings/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the content rating.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Delete(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+	var profileCount int = 0
+
+	if err := database.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			profiles
+		WHERE
+			max_content_rating_id = ?
+		`,
+		id,
+	).Scan(&profileCount); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if profileCount > 0 {
+		return problems.New(problems.Conflict, fmt.Sprintf("The content rating is the limit of %d profiles. Change their limits first.", profileCount))
+	}
+
+	result, err := database.Exec(`
+		DELETE FROM
+			content_ratings
+		WHERE
+			id = ?
+		`,
+		id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete content rating. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No content rating could be found with the given id.")
+	}
+
+	for _, table := range []string{"movies", "shows", "episodes"} {
+		if _, err := database.Exec(
+			fmt.Sprintf(`
+				UPDATE
+					%s
+				SET
+					content_rating_id = NULL
+				WHERE
+					content_rating_id = ?
+				`,
+				table,
+			),
+			id,
+		); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to unrate %s. %v", table, err))
+			return err
+		}
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,122 @@
+package contentratings
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets and returns either a single content rating or every content rating,
+// grouped by system and ordered from least to most mature.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /content-ratings/{id}, /content-ratings
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : OPTIONAL. UUID of the content rating.
+//
+// # HTTP request query parameters:
+//   - system      : OPTIONAL. Only return ratings of this system.
+//   - limit       : OPTIONAL. Number of ratings per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Content ratings, each returning id, system, code, rank, description.
+//   - total       : Number of content ratings across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var system string = r.URL.Query().Get("system")
+	var functionId string = access.RequestId(r)
+	var list []types.ContentRating = []types.ContentRating{}
+
+	ratings, err := queries.ContentRatings(database)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if id != "" {
+		if ratings[id] == nil {
+			return problems.New(problems.NotFound, "No content rating could be found with the given id.")
+		}
+
+		responses.Status{
+			Status: 200,
+			Data:   ratings[id],
+		}.ToClient(w)
+		return nil
+	}
+
+	page, err := queries.NewPage(r, 50, []queries.SortKey{{Expression: "system"}, {Expression: "rank"}, {Expression: "code"}, {Expression: "id"}})
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+	}
+
+	total, err := queries.Count(database, "content_ratings", "(? = '' OR system = ?)", []any{system, system})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	seek, seekArguments := page.Condition()
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				id %s
+			FROM
+				content_ratings
+			WHERE
+				(? = '' OR system = ?) AND %s
+			%s
+			`,
+			page.Columns(),
+			seek,
+			page.Order(),
+		),
+		append([]any{system, system}, seekArguments...)...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var ratingId string
+
+		if err := rows.Scan(append([]any{&ratingId}, page.Scan()...)...); err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		list = append(list, *ratings[ratingId])
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	list = queries.Trim(page, list)
+
+	responses.Paged(r, list, total, page.Next(), page.Prev()).ToClient(w)
+	return nil
+}
@@ -0,0 +1,46 @@
+package contentratings_test
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestRead(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+
+	var codes []string = []string{"A", "B", "C", "D", "E"}
+
+	// Created out of order, to be read by rank.
+	for _, rank := range []int{3, 1, 4, 0, 2} {
+		admin.Form("POST", "/api/v1/content-ratings", url.Values{
+			"system": {"Test"},
+			"code":   {codes[rank]},
+			"rank":   {strconv.Itoa(rank)},
+		}).Expect(201)
+	}
+
+	var read []string
+	for target := "/api/v1/content-ratings?system=Test&limit=2"; target != ""; {
+		page := admin.Send("GET", target).Expect(200).JSON()
+		target, _ = page["next"].(string)
+
+		if total, _ := page["total"].(float64); total != float64(len(codes)) {
+			t.Errorf("a page counts %v ratings, want %d", page["total"], len(codes))
+		}
+
+		for _, value := range page["data"].([]any) {
+			read = append(read, value.(map[string]any)["code"].(string))
+		}
+	}
+
+	if !slices.Equal(read, codes) {
+		t.Errorf("read %v, want %v", read, codes)
+	}
+
+	admin.Send("GET", "/api/v1/content-ratings?after=nonsense").Expect(400)
+}
@@ -0,0 +1,120 @@
+package contentratings
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Changes the code, rank or description of a content rating. Changing the rank
+// reorders the rating on the shared scale, which immediately affects what
+// every limited profile is allowed to watch.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /content-ratings/{id}
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - code        : OPTIONAL. Rating within the system.
+//   - rank        : OPTIONAL. Position on the shared scale.
+//   - description : OPTIONAL. Short explanation of the rating.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The updated content rating.
+func Update(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+	var existing int = 0
+
+	ratings, err := queries.ContentRatings(database)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if ratings[id] == nil {
+		return problems.New(problems.NotFound, "No content rating could be found with the given id.")
+	}
+
+	rating := *ratings[id]
+
+	if code := functions.Sanitize(r.FormValue("code")); code != "" {
+		rating.Code = code
+	}
+
+	if description := functions.Sanitize(r.FormValue("description")); description != "" {
+		rating.Description = description
+	}
+
+	if rank := r.FormValue("rank"); rank != "" {
+		number, err := strconv.Atoi(rank)
+		if err != nil {
+			return problems.New(problems.InvalidRequest, "The rank value must be an integer.")
+		}
+
+		rating.Rank = number
+	}
+
+	if err := validate(&rating); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			content_ratings
+		WHERE
+			system = ? AND code = ? AND id != ?
+		`,
+		rating.System,
+		rating.Code,
+		rating.Id,
+	).Scan(&existing); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if existing > 0 {
+		return problems.New(problems.Conflict, "The rating system already contains a rating with the given code.")
+	}
+
+	if _, err := database.Exec(`
+		UPDATE
+			content_ratings
+		SET
+			code = ?, rank = ?, description = ?
+		WHERE
+			id = ?
+		`,
+		rating.Code,
+		rating.Rank,
+		rating.Description,
+		rating.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to update content rating. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   rating,
+	}.ToClient(w)
+	return nil
+}
@@ -2,16 +2,93 @@ package covers
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"path"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
 )
 
-// use this route to delete a cover
+// Deletes the cover of a show or movie, or the headshot of a person, from the
+// database and file system. Reading it afterwards returns the placeholder cover.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /shows/{id}/cover, /movies/{id}/cover, /people/{id}/headshot
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the show, movie or person.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
 func Delete(
   w http.ResponseWriter,
   r *http.Request,
   db *sql.DB,
   appDirectory *string,
   log *logger.Logger,
-) {}
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	if err := Remove(db, appDirectory, id); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to remove cover. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Removes the cover belonging to the given parent id from both the database
+// and the covers storage folder. Having no cover is not an error.
+func Remove(
+  database *sql.DB,
+  appDirectory *string,
+  parentId string,
+) error {
+	var fileName string = ""
+
+	if err := database.QueryRow(`
+		SELECT
+			file_name
+		FROM
+			covers
+		WHERE
+			parent_id = ?
+		`,
+		parentId,
+	).Scan(&fileName); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return err
+	}
+
+	if _, err := database.Exec(`
+		DELETE FROM
+			covers
+		WHERE
+			parent_id = ?
+		`,
+		parentId,
+	); err != nil {
+		return err
+	}
+
+	if err := os.Remove(
+		path.Join(*appDirectory, "storage", "covers", fileName),
+	); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -8,11 +8,11 @@ import (
 	"os"
 	"path"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
 	"github.com/andrewdotjs/watchify-server/internal/placeholders"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
 	"github.com/andrewdotjs/watchify-server/internal/types"
-	"github.com/google/uuid"
 )
 
 // Returns the covers stored in the database and filesystem. If none are present,
@@ -20,21 +20,21 @@ import (
 //
 // # Specifications:
 //   - Method   : GET
-//   - Endpoint : /shows/{id}/cover
-//   - Auth?    : False
+//   - Endpoint : /shows/{id}/cover, /movies/{id}/cover, /people/{id}/headshot
+//   - Auth?    : True
 //
 // # HTTP request path parameters:
-//   - id       : REQUIRED. UUID of the show.
+//   - id       : REQUIRED. UUID of the show, movie or person.
 func Read(
     w http.ResponseWriter,
     r *http.Request,
     database *sql.DB,
     appDirectory *string,
     log *logger.Logger,
-) {
+) error {
 	var id string = r.PathValue("id")
 	var uploadDirectory string = path.Join(*appDirectory, "storage", "covers")
-	var functionId string = uuid.NewString()
+	var functionId string = access.RequestId(r)
 	var cover types.Cover = types.Cover{}
 
 	if id == "" {
@@ -44,19 +44,30 @@ func Read(
 			StatusCode: 200,
 			FileBuffer: placeholders.Cover(),
 		}.ToClient(w)
-		return
+		return nil
 	}
 
+	movieCondition, movieArguments := access.RatingCondition(r, "movies.content_rating_id")
+	showCondition, showArguments := access.RatingCondition(r, "shows.content_rating_id")
+
+	// Covers of shows and movies the profile may not watch are treated as missing.
 	if err := database.QueryRow(
-		`
-		SELECT
-			id, file_extension, file_name, upload_date
-		FROM
-			movies
-		WHERE
-			id = ?
-		`,
-		id,
+		fmt.Sprintf(`
+			SELECT
+				id, file_extension, file_name, upload_date
+			FROM
+				covers
+			WHERE
+				parent_id = ?
+			AND
+				parent_id NOT IN (SELECT movies.id FROM movies WHERE NOT COALESCE(%s, 0))
+			AND
+				parent_id NOT IN (SELECT shows.id FROM shows WHERE NOT COALESCE(%s, 0))
+			`,
+			movieCondition,
+			showCondition,
+		),
+		append(append([]any{id}, movieArguments...), showArguments...)...,
 	).Scan(
 		&cover.Id,
 		&cover.FileExtension,
@@ -65,26 +76,17 @@ func Read(
 	); err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-		  log.Info(functionId, "No movie found with provided ID")
-			responses.Error{
-				Type:     "null",
-				Title:    "Data not found",
-				Status:   400,
-				Detail:   "No movie could be found with the given id.",
-				Instance: r.URL.Path,
+		  log.Info(functionId, "No cover found with provided ID, sending placeholder.")
+			responses.File{
+				StatusCode: 200,
+				FileBuffer: placeholders.Cover(),
 			}.ToClient(w)
 		default:
 		  log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
-			responses.Error{
-				Type:     "null",
-				Title:    "Unknown Error",
-				Status:   500,
-				Detail:   fmt.Sprintf("%v", err),
-				Instance: r.URL.Path,
-			}.ToClient(w)
+			return err
 		}
 
-		return
+		return nil
 	}
 
 	if buffer, err := os.ReadFile(path.Join(uploadDirectory, cover.FileName)); err != nil {
@@ -100,4 +102,5 @@ func Read(
 			FileBuffer: buffer,
 		}.ToClient(w)
 	}
+	return nil
 }
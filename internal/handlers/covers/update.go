@@ -2,35 +2,78 @@ package covers
 
 import (
 	"database/sql"
+	"fmt"
 	"net/http"
+	"path"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
-	"github.com/google/uuid"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/andrewdotjs/watchify-server/internal/upload"
 )
 
-// use this route to update a cover for a series
+// Replaces the cover of a show or movie, or the headshot of a person, with the
+// uploaded image.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /shows/{id}/cover, /movies/{id}/cover, /people/{id}/headshot
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - cover       : REQUIRED. Uploaded image, should be a 400x600 jpg.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
 func Update(
   w http.ResponseWriter,
   r *http.Request,
   db *sql.DB,
   appDirectory *string,
   log *logger.Logger,
-) {
+) error {
 	var id string = r.PathValue("id")
-	var functionId string = uuid.NewString()
+	var functionId string = access.RequestId(r)
+	var uploadDirectory string = path.Join(*appDirectory, "storage", "covers")
 
 	if id == "" {
 	  log.Error(functionId, "Cover ID was not provided by the request")
 
-		responses.Error{
-			Type:     "null",
-			Title:    "Incomplete request",
-			Status:   400,
-			Detail:   "Did not receive id from url.",
-			Instance: r.URL.Path,
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "Did not receive id from url.")
 	}
 
+	if err := r.ParseMultipartForm(10 << 20); err != nil { // Error handling if form data exceeds 10MB
+		log.Error(functionId, fmt.Sprintf("%v", err))
+		return problems.New(problems.InvalidRequest, "The upload form exceeded 10MB.")
+	}
+
+	uploadedCover := r.MultipartForm.File["cover"]
+	if len(uploadedCover) == 0 {
+		log.Error(functionId, "Received no cover in request")
+		return problems.New(problems.InvalidRequest, "No uploaded cover present in form.")
+	}
+
+	if err := Remove(db, appDirectory, id); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to remove the previous cover. %v", err))
+		return err
+	}
+
+	cover := types.Cover{ParentId: id}
+	if err := upload.Cover(
+		uploadedCover[0],
+		&cover,
+		db,
+		&uploadDirectory,
+		log,
+		&functionId,
+	); err != nil {
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
 }
@@ -0,0 +1,147 @@
+package credits
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Maps the parent types of credits to the table their content is stored in.
+var parentTables map[string]string = map[string]string{
+	"movie":   "movies",
+	"show":    "shows",
+	"episode": "episodes",
+}
+
+// Credits a person on a movie, show or episode.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /credits
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - person_id   : REQUIRED. UUID of the credited person.
+//   - parent_id   : REQUIRED. UUID of the movie, show or episode.
+//   - parent_type : REQUIRED. Either "movie", "show" or "episode".
+//   - role        : REQUIRED. Either "actor", "director" or "writer".
+//   - character   : OPTIONAL. Name of the played character, actors only.
+//   - position    : OPTIONAL. Billing order within the content, defaults to 0.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The created credit.
+func Create(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var credit types.Credit = types.Credit{
+		Id:         uuid.NewString(),
+		PersonId:   r.FormValue("person_id"),
+		ParentId:   r.FormValue("parent_id"),
+		ParentType: r.FormValue("parent_type"),
+		Role:       r.FormValue("role"),
+		Character:  functions.Sanitize(r.FormValue("character")),
+	}
+
+	if position := r.FormValue("position"); position != "" {
+		number, err := strconv.Atoi(position)
+		if err != nil {
+			return problems.New(problems.InvalidRequest, "The position value must be an integer.")
+		}
+
+		credit.Position = number
+	}
+
+	if err := validate(&credit); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	for table, id := range map[string]string{"people": credit.PersonId, parentTables[credit.ParentType]: credit.ParentId} {
+		var count int = 0
+
+		if err := database.QueryRow(
+			fmt.Sprintf(`
+				SELECT
+					COUNT(*)
+				FROM
+					%s
+				WHERE
+					id = ?
+				`,
+				table,
+			),
+			id,
+		).Scan(&count); err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		if count == 0 {
+			return problems.New(problems.NotFound, fmt.Sprintf("No entry in %s could be found with the given id.", table))
+		}
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			credits (id, person_id, parent_id, parent_type, role, character, position)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?)
+		`,
+		credit.Id,
+		credit.PersonId,
+		credit.ParentId,
+		credit.ParentType,
+		credit.Role,
+		credit.Character,
+		credit.Position,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to insert credit. %v", err))
+		return err
+	}
+
+	credit.Version = 1
+
+	responses.Status{
+		Status: 201,
+		Data:   credit,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks that the type, role and character of a credit are acceptable. Returns
+// the problem to send to the client if they are not.
+func validate(credit *types.Credit) error {
+	var name string = ""
+	var detail string = ""
+
+	switch {
+	case credit.PersonId == "" || credit.ParentId == "":
+		name, detail = "person_id", "Both person_id and parent_id are required."
+	case parentTables[credit.ParentType] == "":
+		name, detail = "parent_type", "The parent_type value must be either movie, show or episode."
+	case credit.Role != "actor" && credit.Role != "director" && credit.Role != "writer":
+		name, detail = "role", "The role value must be either actor, director or writer."
+	case credit.Role != "actor" && credit.Character != "":
+		name, detail = "character", "Only actors can play a character."
+	case len(credit.Character) > 100:
+		name, detail = "character", "The character value was larger than 100 bytes. In UTF-8 encoding, English characters are 1 byte each."
+	default:
+		return nil
+	}
+
+	return problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+}
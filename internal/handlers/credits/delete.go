@@ -0,0 +1,56 @@
+package credits
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Removes a credit. The credited person and content are left untouched.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /credits/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the credit.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Delete(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	result, err := database.Exec(`
+		DELETE FROM
+			credits
+		WHERE
+			id = ?
+		`,
+		id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete credit. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No credit could be found with the given id.")
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
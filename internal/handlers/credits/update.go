@@ -0,0 +1,120 @@
+package credits
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Changes the role, character or billing position of a credit. Values that are
+// not present in the form are left untouched.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /credits/{id}
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - role        : OPTIONAL. Either "actor", "director" or "writer".
+//   - character   : OPTIONAL. Name of the played character, actors only.
+//   - position    : OPTIONAL. Billing order within the content.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The updated credit.
+func Update(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+	var credit types.Credit = types.Credit{}
+
+	if err := database.QueryRow(`
+		SELECT
+			id, person_id, parent_id, parent_type, role, character, position, version
+		FROM
+			credits
+		WHERE
+			id = ?
+		`,
+		id,
+	).Scan(
+		&credit.Id,
+		&credit.PersonId,
+		&credit.ParentId,
+		&credit.ParentType,
+		&credit.Role,
+		&credit.Character,
+		&credit.Position,
+		&credit.Version,
+	); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return problems.New(problems.NotFound, "No credit could be found with the given id.")
+		default:
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+	}
+
+	if role := r.FormValue("role"); role != "" {
+		credit.Role = role
+	}
+
+	if character := functions.Sanitize(r.FormValue("character")); character != "" {
+		credit.Character = character
+	}
+
+	if position := r.FormValue("position"); position != "" {
+		number, err := strconv.Atoi(position)
+		if err != nil {
+			return problems.New(problems.InvalidRequest, "The position value must be an integer.")
+		}
+
+		credit.Position = number
+	}
+
+	if err := validate(&credit); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	// The version is set here rather than by the trigger, whose changes RETURNING
+	// would not report.
+	if err := database.QueryRow(`
+		UPDATE
+			credits
+		SET
+			role = ?, character = ?, position = ?, version = version + 1
+		WHERE
+			id = ?
+		RETURNING
+			version
+		`,
+		credit.Role,
+		credit.Character,
+		credit.Position,
+		credit.Id,
+	).Scan(&credit.Version); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to update credit. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   credit,
+	}.ToClient(w)
+	return nil
+}
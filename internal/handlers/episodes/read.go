@@ -2,111 +2,116 @@ package episodes
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
-	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
 )
 
 // Gets and returns an array of episodes of a series stored in the database.
 //
 // # Specifications:
 //   - Method      : GET
-//   - Endpoint    : /series/{id}/episodes
-//   - Auth?       : False
+//   - Endpoint    : /shows/{id}/episodes
+//   - Auth?       : True
 //
 // # HTTP request path parameters:
 //   - id          : REQUIRED. UUID of the series.
 //
+// # HTTP request query parameters:
+//   - filter      : OPTIONAL. Filter expression, such as `season=2 and title~"finale"`.
+//   - sort        : OPTIONAL. Comma separated fields to sort by, descending if prefixed
+//     with a minus. By season and episode otherwise.
+//   - limit       : OPTIONAL. Number of episodes per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
 // # HTTP response JSON contents:
 //   - status_code : HTTP status code.
-//   - message     : If error, Message detailing the error.
-//   - data        : Series episodes, each returning id, episode.
+//   - data        : Series episodes ordered by season and episode, each returning
+//     id, season_number, episode_number and the progress of the viewer.
+//   - total       : Number of episodes across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
 func Read(
   w http.ResponseWriter,
   r *http.Request,
   db *sql.DB,
   log *logger.Logger,
-) {
-	var videos []types.Episode
+) error {
 	id := r.PathValue("id")
 
 	if id == "" {
-		responses.Error{
-			Type:     "null",
-			Title:    "Invalid Request",
-			Status:   400,
-			Detail:   "Id not passed in as a path parameter.",
-			Instance: r.URL.Path,
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "Id not passed in as a path parameter.")
 	}
 
-	rows, err := db.Query(
-		`
-   	SELECT
-			id, episode_number, file_name, last_modified, upload_date
-   	FROM
-			episodes
-   	WHERE
-			parent_id=?
-    `,
-		id,
-	)
+	var order []queries.SortKey = []queries.SortKey{
+		{Expression: "season_number"},
+		{Expression: "episode_number"},
+		{Expression: "id"},
+	}
 
-	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			responses.Status{
-				Status: 200,
-				Data:   nil,
-			}.ToClient(w)
-		default:
-			responses.Error{
-				Type:     "null",
-				Title:    "Unknown Error",
-				Status:   500,
-				Detail:   fmt.Sprintf("%v", err),
-				Instance: r.URL.Path,
-			}.ToClient(w)
+	if sort := r.URL.Query().Get("sort"); sort != "" {
+		keys, err := queries.Sort(sort, queries.EpisodeFields)
+		if err != nil {
+			return queries.Malformed(err)
 		}
 
-		return
+		order = keys
 	}
 
-	defer rows.Close()
-	for rows.Next() {
-		var video types.Episode
-
-		if err := rows.Scan(
-			&video.Id,
-			&video.EpisodeNumber,
-			&video.FileName,
-			&video.LastModified,
-			&video.UploadDate,
-		); err != nil {
-			switch {
-			default:
-				responses.Error{
-					Type:     "null",
-					Title:    "Unknown Error",
-					Status:   500,
-					Detail:   fmt.Sprintf("%v", err),
-					Instance: r.URL.Path,
-				}.ToClient(w)
-			}
-
-			return
-		} else {
-			videos = append(videos, video)
-		}
+	filter, filterArguments, err := queries.Filter(r.URL.Query().Get("filter"), queries.EpisodeFields)
+	if err != nil {
+		return queries.Malformed(err)
+	}
+
+	page, err := queries.NewPage(r, 50, order)
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+	}
+
+	condition, conditionArguments := access.EpisodeCondition(r)
+	condition += " AND " + filter
+	arguments := append(append([]any{id}, conditionArguments...), filterArguments...)
+
+	total, err := queries.Count(db, "episodes", "parent_id=? AND "+condition, arguments)
+	if err != nil {
+		return err
+	}
+
+	ratings, err := queries.ContentRatings(db)
+	if err != nil {
+		return err
+	}
+
+	videos, err := queries.EpisodePage(db, "parent_id=? AND "+condition, arguments, page)
+	if err != nil {
+		return err
+	}
+
+	for index := range videos {
+		videos[index].ContentRating = ratings[videos[index].ContentRatingId]
+	}
+
+	var ids []string
+	for _, video := range videos {
+		ids = append(ids, video.Id)
+	}
+
+	progress, err := queries.ProgressFor(db, access.Viewer(r), ids)
+	if err != nil {
+		log.Error(uuid.NewString(), fmt.Sprintf("Failed to retrieve progress. %v", err))
+		return err
+	}
+
+	for index := range videos {
+		videos[index].Progress = progress[videos[index].Id]
 	}
 
-	responses.Status{
-		Status: 200,
-		Data:   videos,
-	}.ToClient(w)
+	responses.Paged(r, videos, total, page.Next(), page.Prev()).ToClient(w)
+	return nil
 }
@@ -0,0 +1,131 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/graphql"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+)
+
+// Largest request body accepted, in bytes.
+const maximumBodySize int64 = 1 << 20
+
+// A GraphQL request along with the persisted query extension, which lets clients
+// send the SHA-256 hash of a query in place of its text once it was persisted.
+type request struct {
+	graphql.Request
+	Extensions struct {
+		PersistedQuery *struct {
+			Version    int    `json:"version"`
+			Sha256Hash string `json:"sha256Hash"`
+		} `json:"persistedQuery"`
+	} `json:"extensions"`
+}
+
+// Executes a GraphQL query over shows, seasons, episodes and movies along with
+// their covers, tags, credits and the progress, lists and ratings of the viewer,
+// so that clients can fetch everything a screen needs in one request.
+//
+// Fields are resolved for whole lists at once, so a query costs a fixed number
+// of database queries regardless of how many items it returns. Queries that
+// nest too deep or select too many fields are rejected before running.
+//
+// # Specifications:
+//   - Method      : GET, POST
+//   - Endpoint    : /api/graphql
+//   - Auth?       : True
+//
+// # HTTP request JSON contents (POST), or query parameters (GET):
+//   - query         : REQUIRED unless persisted. GraphQL query document.
+//   - operationName : OPTIONAL. Operation to run if the document holds several.
+//   - variables     : OPTIONAL. Values of the variables of the operation, JSON encoded for GET.
+//   - extensions    : OPTIONAL. `{"persistedQuery": {"version": 1, "sha256Hash": "..."}}`
+//     to run a persisted query by its hash, or to persist the query sent along with it.
+//     JSON encoded for GET.
+//
+// # HTTP response JSON contents:
+//   - data        : Selected fields, left out if the query could not run.
+//   - errors      : OPTIONAL. Errors along with the location and path they occurred at.
+func Execute(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var body request
+
+	if r.Method == http.MethodGet {
+		body.Query = r.URL.Query().Get("query")
+		body.OperationName = r.URL.Query().Get("operationName")
+
+		for name, destination := range map[string]any{"variables": &body.Variables, "extensions": &body.Extensions} {
+			if value := r.URL.Query().Get(name); value != "" {
+				if err := json.Unmarshal([]byte(value), destination); err != nil {
+					respond(w, http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: fmt.Sprintf("The %s are not valid JSON.", name)}}})
+					return nil
+				}
+			}
+		}
+	} else if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maximumBodySize)).Decode(&body); err != nil {
+		respond(w, http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: "The request body is not a valid GraphQL request."}}})
+		return nil
+	}
+
+	persisted := body.Extensions.PersistedQuery
+	if persisted != nil {
+		if body.Query == "" {
+			query, err := queries.PersistedQuery(database, persisted.Sha256Hash)
+			if err != nil {
+				log.Error(functionId, fmt.Sprintf("Failed to retrieve persisted query. %v", err))
+				respond(w, http.StatusInternalServerError, graphql.Response{Errors: []*graphql.Error{{Message: "The persisted query could not be retrieved."}}})
+				return nil
+			}
+
+			if query == "" {
+				respond(w, http.StatusOK, graphql.Response{Errors: []*graphql.Error{{
+					Message:    "PersistedQueryNotFound",
+					Extensions: map[string]any{"code": "PERSISTED_QUERY_NOT_FOUND"},
+				}}})
+				return nil
+			}
+
+			body.Query = query
+		} else if hash := sha256.Sum256([]byte(body.Query)); hex.EncodeToString(hash[:]) != persisted.Sha256Hash {
+			respond(w, http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: "The sha256Hash does not match the query."}}})
+			return nil
+		}
+	}
+
+	if body.Query == "" {
+		respond(w, http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: "No query was given."}}})
+		return nil
+	}
+
+	response := newSchema(database, r).Execute(body.Request)
+
+	// Only keep queries that could run, so clients can't fill the table with junk.
+	if persisted != nil && response.Data != nil {
+		if err := queries.PersistQuery(database, persisted.Sha256Hash, body.Query); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to persist query. %v", err))
+		}
+	}
+
+	log.Info(functionId, fmt.Sprintf("Executed GraphQL query with %d errors", len(response.Errors)))
+	respond(w, http.StatusOK, response)
+	return nil
+}
+
+// Sends a GraphQL response to the client.
+func respond(w http.ResponseWriter, status int, response graphql.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,484 @@
+package graph
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/graphql"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Number of shows or movies a list query returns without a first argument, and
+// the most it returns with one.
+const (
+	defaultFirst int = 20
+	maximumFirst int = 100
+)
+
+// A season of a show along with its episodes, in order.
+type season struct {
+	number   int
+	episodes []types.Episode
+}
+
+// Builds the schema for a request. Resolvers only let through content the
+// requesting profile may watch, and return the progress, lists and ratings of
+// its viewer, just like the REST handlers.
+func newSchema(database *sql.DB, r *http.Request) *graphql.Schema {
+	var viewer string = access.Viewer(r)
+
+	showId := func(parent any) string { return parent.(types.Show).Id }
+	movieId := func(parent any) string { return parent.(types.Movie).Id }
+	episodeId := func(parent any) string { return parent.(types.Episode).Id }
+
+	// Fields every show, movie and episode has, resolved for a whole list at once.
+	contentRating := func(ratingId func(parent any) string) *graphql.Field {
+		return &graphql.Field{Type: "ContentRating", Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			ratings, err := queries.ContentRatings(database)
+			if err != nil {
+				return nil, err
+			}
+
+			return lookup(parents, ratingId, ratings), nil
+		}}
+	}
+
+	tags := func(id func(parent any) string) *graphql.Field {
+		return &graphql.Field{Type: "[Tag!]!", Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			found, err := queries.TagsFor(database, ids(parents, id))
+			if err != nil {
+				return nil, err
+			}
+
+			return lists(parents, id, found), nil
+		}}
+	}
+
+	credits := func(id func(parent any) string) *graphql.Field {
+		return &graphql.Field{Type: "[Credit!]!", Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			found, err := queries.CreditsFor(database, ids(parents, id))
+			if err != nil {
+				return nil, err
+			}
+
+			return lists(parents, id, found), nil
+		}}
+	}
+
+	progress := func(id func(parent any) string) *graphql.Field {
+		return &graphql.Field{Type: "Progress", Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			found, err := queries.ProgressFor(database, viewer, ids(parents, id))
+			if err != nil {
+				return nil, err
+			}
+
+			return lookup(parents, id, found), nil
+		}}
+	}
+
+	rating := func(id func(parent any) string) *graphql.Field {
+		return &graphql.Field{Type: "Rating", Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			found, err := queries.RatingsFor(database, viewer, ids(parents, id))
+			if err != nil {
+				return nil, err
+			}
+
+			return lookup(parents, id, found), nil
+		}}
+	}
+
+	membership := func(list string, id func(parent any) string) *graphql.Field {
+		return &graphql.Field{Type: "Boolean!", Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			found, err := queries.ListMembership(database, viewer, list, ids(parents, id))
+			if err != nil {
+				return nil, err
+			}
+
+			return graphql.Each(func(parent any) any { return found[id(parent)] })(parents, arguments)
+		}}
+	}
+
+	// Loads the episodes of every show a list of parents belongs to, in order.
+	episodesOf := func(parents []any) (map[string][]types.Episode, error) {
+		var found map[string][]types.Episode = map[string][]types.Episode{}
+		var showIds []string = ids(parents, showId)
+
+		if len(showIds) == 0 {
+			return found, nil
+		}
+
+		condition, arguments := access.EpisodeCondition(r)
+		episodes, err := queries.Episodes(
+			database,
+			fmt.Sprintf("parent_id IN (%s) AND %s", queries.Placeholders(len(showIds)), condition),
+			append(anys(showIds), arguments...),
+			"ORDER BY season_number, episode_number",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, episode := range episodes {
+			found[episode.ParentId] = append(found[episode.ParentId], episode)
+		}
+
+		return found, nil
+	}
+
+	cover := &graphql.Object{Name: "Cover", Fields: map[string]*graphql.Field{
+		"exists": {Type: "Boolean!", Resolve: graphql.Each(func(parent any) any { return parent.(map[string]any)["exists"] })},
+		"url":    {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(map[string]any)["url"] })},
+	}}
+
+	tag := &graphql.Object{Name: "Tag", Fields: map[string]*graphql.Field{
+		"id":   {Type: "ID!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Tag).Id })},
+		"name": {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Tag).Name })},
+		"kind": {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Tag).Kind })},
+	}}
+
+	credit := &graphql.Object{Name: "Credit", Fields: map[string]*graphql.Field{
+		"id":        {Type: "ID!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Credit).Id })},
+		"personId":  {Type: "ID!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Credit).PersonId })},
+		"name":      {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Credit).Name })},
+		"role":      {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Credit).Role })},
+		"character": {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Credit).Character })},
+		"position":  {Type: "Int!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Credit).Position })},
+	}}
+
+	contentRatingObject := &graphql.Object{Name: "ContentRating", Fields: map[string]*graphql.Field{
+		"id":          {Type: "ID!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.ContentRating).Id })},
+		"system":      {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.ContentRating).System })},
+		"code":        {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.ContentRating).Code })},
+		"rank":        {Type: "Int!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.ContentRating).Rank })},
+		"description": {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.ContentRating).Description })},
+	}}
+
+	progressObject := &graphql.Object{Name: "Progress", Fields: map[string]*graphql.Field{
+		"position":        {Type: "Float!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.Progress).Position })},
+		"duration":        {Type: "Float!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.Progress).Duration })},
+		"watched":         {Type: "Boolean!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.Progress).Watched })},
+		"lastWatchedDate": {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.Progress).LastWatchedDate })},
+	}}
+
+	ratingObject := &graphql.Object{Name: "Rating", Fields: map[string]*graphql.Field{
+		"average": {Type: "Float!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.RatingSummary).Average })},
+		"count":   {Type: "Int!", Resolve: graphql.Each(func(parent any) any { return parent.(*types.RatingSummary).Count })},
+		"score":   {Type: "Int", Resolve: graphql.Each(func(parent any) any { return nullable(parent.(*types.RatingSummary).Score) })},
+	}}
+
+	show := &graphql.Object{Name: "Show", Fields: map[string]*graphql.Field{
+		"id":            {Type: "ID!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Show).Id })},
+		"title":         {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Show).Title })},
+		"description":   {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Show).Description })},
+		"episodeCount":  {Type: "Int!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Show).EpisodeCount })},
+		"uploadDate":    {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Show).UploadDate })},
+		"lastModified":  {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Show).LastModified })},
+		"cover":         {Type: "Cover!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Show).Cover })},
+		"contentRating": contentRating(func(parent any) string { return parent.(types.Show).ContentRatingId }),
+		"tags":          tags(showId),
+		"credits":       credits(showId),
+		"rating":        rating(showId),
+		"inWatchlist":   membership(queries.Watchlist, showId),
+		"favorite":      membership(queries.Favorites, showId),
+		"seasons": {Type: "[Season!]!", Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			found, err := episodesOf(parents)
+			if err != nil {
+				return nil, err
+			}
+
+			return graphql.Each(func(parent any) any {
+				var seasons []any = []any{}
+
+				for _, episode := range found[showId(parent)] {
+					if len(seasons) == 0 || seasons[len(seasons)-1].(*season).number != episode.SeasonNumber {
+						seasons = append(seasons, &season{number: episode.SeasonNumber})
+					}
+
+					last := seasons[len(seasons)-1].(*season)
+					last.episodes = append(last.episodes, episode)
+				}
+
+				return seasons
+			})(parents, arguments)
+		}},
+		"episodes": {Type: "[Episode!]!", Arguments: map[string]string{"season": "Int"}, Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			found, err := episodesOf(parents)
+			if err != nil {
+				return nil, err
+			}
+
+			return graphql.Each(func(parent any) any {
+				var episodes []any = []any{}
+
+				for _, episode := range found[showId(parent)] {
+					if number, filtered := arguments["season"].(int); !filtered || episode.SeasonNumber == number {
+						episodes = append(episodes, episode)
+					}
+				}
+
+				return episodes
+			})(parents, arguments)
+		}},
+	}}
+
+	seasonObject := &graphql.Object{Name: "Season", Fields: map[string]*graphql.Field{
+		"number": {Type: "Int!", Resolve: graphql.Each(func(parent any) any { return parent.(*season).number })},
+		"episodes": {Type: "[Episode!]!", Resolve: graphql.Each(func(parent any) any {
+			var episodes []any = []any{}
+
+			for _, episode := range parent.(*season).episodes {
+				episodes = append(episodes, episode)
+			}
+
+			return episodes
+		})},
+	}}
+
+	episode := &graphql.Object{Name: "Episode", Fields: map[string]*graphql.Field{
+		"id":            {Type: "ID!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Episode).Id })},
+		"title":         {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Episode).Title })},
+		"description":   {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Episode).Description })},
+		"seasonNumber":  {Type: "Int!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Episode).SeasonNumber })},
+		"episodeNumber": {Type: "Int!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Episode).EpisodeNumber })},
+		"uploadDate":    {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Episode).UploadDate })},
+		"lastModified":  {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Episode).LastModified })},
+		"streamUrl":     {Type: "String!", Resolve: graphql.Each(func(parent any) any { return "/api/v1/stream/" + episodeId(parent) })},
+		"contentRating": contentRating(func(parent any) string { return parent.(types.Episode).ContentRatingId }),
+		"credits":       credits(episodeId),
+		"progress":      progress(episodeId),
+		"rating":        rating(episodeId),
+		"show": {Type: "Show", Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			parentId := func(parent any) string { return parent.(types.Episode).ParentId }
+			found, err := showsById(database, r, ids(parents, parentId))
+			if err != nil {
+				return nil, err
+			}
+
+			return lookup(parents, parentId, found), nil
+		}},
+	}}
+
+	movie := &graphql.Object{Name: "Movie", Fields: map[string]*graphql.Field{
+		"id":            {Type: "ID!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Movie).Id })},
+		"title":         {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Movie).Title })},
+		"description":   {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Movie).Description })},
+		"uploadDate":    {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Movie).UploadDate })},
+		"lastModified":  {Type: "String!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Movie).LastModified })},
+		"streamUrl":     {Type: "String!", Resolve: graphql.Each(func(parent any) any { return "/api/v1/stream/" + movieId(parent) })},
+		"cover":         {Type: "Cover!", Resolve: graphql.Each(func(parent any) any { return parent.(types.Movie).Cover })},
+		"contentRating": contentRating(func(parent any) string { return parent.(types.Movie).ContentRatingId }),
+		"tags":          tags(movieId),
+		"credits":       credits(movieId),
+		"progress":      progress(movieId),
+		"rating":        rating(movieId),
+		"inWatchlist":   membership(queries.Watchlist, movieId),
+		"favorite":      membership(queries.Favorites, movieId),
+	}}
+
+	listArguments := map[string]string{"filter": "String", "sort": "String", "first": "Int"}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+		"show": {Type: "Show", Arguments: map[string]string{"id": "ID!"}, Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			found, err := showsById(database, r, []string{arguments["id"].(string)})
+			if err != nil {
+				return nil, err
+			}
+
+			if show, exists := found[arguments["id"].(string)]; exists {
+				return []any{show}, nil
+			}
+
+			return []any{nil}, nil
+		}},
+		"shows": {Type: "[Show!]!", Arguments: listArguments, Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			condition, order, conditionArguments, err := listQuery(r, arguments, queries.ShowFields)
+			if err != nil {
+				return nil, err
+			}
+
+			shows, err := queries.Shows(database, condition, conditionArguments, order)
+			if err != nil {
+				return nil, err
+			}
+
+			return []any{items(shows)}, nil
+		}},
+		"movie": {Type: "Movie", Arguments: map[string]string{"id": "ID!"}, Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			condition, conditionArguments := access.RatingCondition(r, "content_rating_id")
+			movies, err := queries.Movies(database, "id = ? AND "+condition, append([]any{arguments["id"]}, conditionArguments...), "")
+			if err != nil || len(movies) == 0 {
+				return []any{nil}, err
+			}
+
+			return []any{movies[0]}, nil
+		}},
+		"movies": {Type: "[Movie!]!", Arguments: listArguments, Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			condition, order, conditionArguments, err := listQuery(r, arguments, queries.MovieFields)
+			if err != nil {
+				return nil, err
+			}
+
+			movies, err := queries.Movies(database, "hidden = 0 AND "+condition, conditionArguments, order)
+			if err != nil {
+				return nil, err
+			}
+
+			return []any{items(movies)}, nil
+		}},
+		"episode": {Type: "Episode", Arguments: map[string]string{"id": "ID!"}, Resolve: func(parents []any, arguments map[string]any) ([]any, error) {
+			condition, conditionArguments := access.EpisodeCondition(r)
+			episodes, err := queries.Episodes(database, "id = ? AND "+condition, append([]any{arguments["id"]}, conditionArguments...), "")
+			if err != nil || len(episodes) == 0 {
+				return []any{nil}, err
+			}
+
+			return []any{episodes[0]}, nil
+		}},
+	}}
+
+	schema := graphql.NewSchema(query, cover, tag, credit, contentRatingObject, progressObject, ratingObject, show, seasonObject, episode, movie)
+	schema.MaxListSize = maximumFirst
+	return schema
+}
+
+// Returns the shows with the given ids that the request may watch, keyed by id.
+func showsById(database *sql.DB, r *http.Request, showIds []string) (map[string]types.Show, error) {
+	var found map[string]types.Show = map[string]types.Show{}
+
+	if len(showIds) == 0 {
+		return found, nil
+	}
+
+	condition, arguments := access.RatingCondition(r, "content_rating_id")
+	shows, err := queries.Shows(
+		database,
+		fmt.Sprintf("id IN (%s) AND %s", queries.Placeholders(len(showIds)), condition),
+		append(anys(showIds), arguments...),
+		"",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, show := range shows {
+		found[show.Id] = show
+	}
+
+	return found, nil
+}
+
+// Turns the filter, sort and first arguments of a list field into the condition
+// and ORDER BY clause of a query, including the content rating limit of the
+// request, the same way the REST list endpoints read them.
+func listQuery(r *http.Request, arguments map[string]any, fields queries.Fields) (string, string, []any, error) {
+	var keys []queries.SortKey = []queries.SortKey{{Expression: "title"}, {Expression: "id"}}
+	var parts []string
+	var first int = defaultFirst
+
+	condition, conditionArguments := access.RatingCondition(r, "content_rating_id")
+
+	filterText, _ := arguments["filter"].(string)
+	filter, filterArguments, err := queries.Filter(filterText, fields)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	if sort, _ := arguments["sort"].(string); sort != "" {
+		if keys, err = queries.Sort(sort, fields); err != nil {
+			return "", "", nil, err
+		}
+	}
+
+	if limit, given := arguments["first"].(int); given {
+		first = min(max(limit, 0), maximumFirst)
+	}
+
+	for _, key := range keys {
+		direction := "ASC"
+		if key.Descending {
+			direction = "DESC"
+		}
+
+		parts = append(parts, key.Expression+" "+direction)
+	}
+
+	return condition + " AND " + filter,
+		fmt.Sprintf("ORDER BY %s LIMIT %d", strings.Join(parts, ", "), first),
+		append(conditionArguments, filterArguments...),
+		nil
+}
+
+// Returns the ids of parents, leaving out duplicates.
+func ids(parents []any, id func(parent any) string) []string {
+	var list []string
+	var seen map[string]bool = map[string]bool{}
+
+	for _, parent := range parents {
+		if value := id(parent); value != "" && !seen[value] {
+			seen[value] = true
+			list = append(list, value)
+		}
+	}
+
+	return list
+}
+
+// Returns the value found for the id of every parent, or null where nothing was
+// found.
+func lookup[T any](parents []any, id func(parent any) string, found map[string]T) []any {
+	var values []any = make([]any, len(parents))
+
+	for index, parent := range parents {
+		if value, exists := found[id(parent)]; exists {
+			values[index] = value
+		}
+	}
+
+	return values
+}
+
+// Returns the list found for the id of every parent, or an empty list where
+// nothing was found.
+func lists[T any](parents []any, id func(parent any) string, found map[string][]T) []any {
+	var values []any = make([]any, len(parents))
+
+	for index, parent := range parents {
+		values[index] = items(found[id(parent)])
+	}
+
+	return values
+}
+
+// Converts a typed slice to the []any list fields resolve to.
+func items[T any](list []T) []any {
+	var values []any = []any{}
+
+	for _, item := range list {
+		values = append(values, item)
+	}
+
+	return values
+}
+
+func anys(list []string) []any {
+	var values []any
+
+	for _, item := range list {
+		values = append(values, item)
+	}
+
+	return values
+}
+
+// Returns null for scores the viewer never gave.
+func nullable(score int) any {
+	if score == 0 {
+		return nil
+	}
+
+	return score
+}
@@ -4,13 +4,38 @@ import (
 	"database/sql"
 	"net/http"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/auth"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/batch"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/contentratings"
 	"github.com/andrewdotjs/watchify-server/internal/handlers/covers"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/credits"
 	"github.com/andrewdotjs/watchify-server/internal/handlers/episodes"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/graph"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/home"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/lists"
 	"github.com/andrewdotjs/watchify-server/internal/handlers/movies"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/people"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/profiles"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/progress"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/recommendations"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/reviews"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/settings"
 	"github.com/andrewdotjs/watchify-server/internal/handlers/shows"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/spec"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/sso"
 	"github.com/andrewdotjs/watchify-server/internal/handlers/stream"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/suggestions"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/tags"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/tokens"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/users"
 	"github.com/andrewdotjs/watchify-server/internal/handlers/videos"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/middleware"
+	"github.com/andrewdotjs/watchify-server/internal/oidc"
+	"github.com/andrewdotjs/watchify-server/internal/openapi"
+	"github.com/andrewdotjs/watchify-server/internal/recommend"
+	"github.com/andrewdotjs/watchify-server/internal/suggest"
 )
 
 // Stream
@@ -21,9 +46,9 @@ func Stream(
   appDirectory *string,
   log *logger.Logger,
 ) {
- 	mux.Handle("GET /api/v1/stream/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		stream.Read(w, r, db, appDirectory)
-	}))
+ 	mux.Handle("GET /api/v1/stream/{id}", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return stream.Read(w, r, db, appDirectory, log)
+	})))
 }
 
 // Videos
@@ -34,21 +59,25 @@ func Videos(
   appDirectory *string,
   log *logger.Logger,
 ) {
- 	mux.Handle("GET /api/v1/videos/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		videos.Read(w, r, db)
-	}))
+ 	mux.Handle("GET /api/v1/videos/{id}", middleware.Authorize(access.Browse, middleware.Versioned("episodes", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return videos.Read(w, r, db)
+	}))))
 
-	mux.Handle("DELETE /api/v1/videos/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		videos.Delete(w, r, db, appDirectory, log)
-	}))
+	mux.Handle("PUT /api/v1/videos/{id}/content-rating", middleware.Authorize(access.EditLibrary, middleware.Versioned("episodes", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return contentratings.Assign(w, r, db, "episodes", log)
+	}))))
 
-	mux.Handle("POST /api/v1/videos", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		videos.Create(w, r, db, appDirectory, log)
-	}))
+	mux.Handle("DELETE /api/v1/videos/{id}", middleware.Authorize(access.DeleteLibrary, middleware.Versioned("episodes", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return videos.Delete(w, r, db, appDirectory, log)
+	}))))
 
-	mux.Handle("GET /api/v1/videos", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		videos.Read(w, r, db)
-	}))
+	mux.Handle("POST /api/v1/videos", middleware.Authorize(access.EditLibrary, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return videos.Create(w, r, db, appDirectory, log)
+	})))
+
+	mux.Handle("GET /api/v1/videos", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return videos.Read(w, r, db)
+	})))
 }
 
 // Shows
@@ -57,84 +86,718 @@ func Shows(
   mux *http.ServeMux,
   db *sql.DB,
   appDirectory *string,
+  index *suggest.Index,
   log *logger.Logger,
 ) {
- 	mux.Handle("GET /api/v1/shows/{id}/episodes", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		episodes.Read(w, r, db, log)
-	}))
+ 	mux.Handle("GET /api/v1/shows/{id}/episodes", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return episodes.Read(w, r, db, log)
+	})))
+
+  mux.Handle("GET /api/v1/shows/{id}/cover", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return covers.Read(w, r, db, appDirectory, log)
+  })))
+
+  mux.Handle("PUT /api/v1/shows/{id}/cover", middleware.Authorize(access.EditLibrary, middleware.Versioned("shows", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return covers.Update(w, r, db, appDirectory, log)
+  }))))
+
+  mux.Handle("DELETE /api/v1/shows/{id}/cover", middleware.Authorize(access.EditLibrary, middleware.Versioned("shows", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return covers.Delete(w, r, db, appDirectory, log)
+  }))))
+
+  mux.Handle("PUT /api/v1/shows/{id}/content-rating", middleware.Authorize(access.EditLibrary, middleware.Versioned("shows", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    err := contentratings.Assign(w, r, db, "shows", log)
+    if err == nil {
+      index.Refresh(log)
+    }
+    return err
+  }))))
+
+  mux.Handle("GET /api/v1/shows/{id}/tags", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return tags.ReadParent(w, r, db, "shows", log)
+  })))
+
+  mux.Handle("PUT /api/v1/shows/{id}/tags/{tagId}", middleware.Authorize(access.EditLibrary, middleware.Versioned("shows", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return tags.Attach(w, r, db, "shows", log)
+  }))))
+
+  mux.Handle("DELETE /api/v1/shows/{id}/tags/{tagId}", middleware.Authorize(access.EditLibrary, middleware.Versioned("shows", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return tags.Detach(w, r, db, "shows", log)
+  }))))
+
+	mux.Handle("GET /api/v1/shows/{id}", middleware.Authorize(access.Browse, middleware.Versioned("shows", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return shows.Read(w, r, db, log)
+	}))))
+
+	mux.Handle("PUT /api/v1/shows/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("shows", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := shows.Update(w, r, db, appDirectory, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
+
+	mux.Handle("PATCH /api/v1/shows/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("shows", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := shows.Patch(w, r, db, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
+
+	mux.Handle("DELETE /api/v1/shows/{id}", middleware.Authorize(access.DeleteLibrary, middleware.Versioned("shows", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := shows.Delete(w, r, db, appDirectory, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
+
+	mux.Handle("POST /api/v1/shows", middleware.Authorize(access.EditLibrary, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := shows.Create(w, r, db, appDirectory, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	})))
+
+	mux.Handle("GET /api/v1/shows", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return shows.Read(w, r, db, log)
+	})))
+}
+
+// Movies
+
+func Movies(
+  mux *http.ServeMux,
+  db *sql.DB,
+  appDirectory *string,
+  index *suggest.Index,
+  log *logger.Logger,
+) {
+  mux.Handle("GET /api/v1/movies/{id}/cover", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return covers.Read(w, r, db, appDirectory, log)
+  })))
+
+  mux.Handle("PUT /api/v1/movies/{id}/cover", middleware.Authorize(access.EditLibrary, middleware.Versioned("movies", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return covers.Update(w, r, db, appDirectory, log)
+  }))))
+
+  mux.Handle("DELETE /api/v1/movies/{id}/cover", middleware.Authorize(access.EditLibrary, middleware.Versioned("movies", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return covers.Delete(w, r, db, appDirectory, log)
+  }))))
+
+  mux.Handle("PUT /api/v1/movies/{id}/content-rating", middleware.Authorize(access.EditLibrary, middleware.Versioned("movies", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    err := contentratings.Assign(w, r, db, "movies", log)
+    if err == nil {
+      index.Refresh(log)
+    }
+    return err
+  }))))
+
+  mux.Handle("GET /api/v1/movies/{id}/tags", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return tags.ReadParent(w, r, db, "movies", log)
+  })))
+
+  mux.Handle("PUT /api/v1/movies/{id}/tags/{tagId}", middleware.Authorize(access.EditLibrary, middleware.Versioned("movies", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return tags.Attach(w, r, db, "movies", log)
+  }))))
+
+  mux.Handle("DELETE /api/v1/movies/{id}/tags/{tagId}", middleware.Authorize(access.EditLibrary, middleware.Versioned("movies", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return tags.Detach(w, r, db, "movies", log)
+  }))))
+
+ 	mux.Handle("GET /api/v1/movies/{id}", middleware.Authorize(access.Browse, middleware.Versioned("movies", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return movies.Read(w, r, db, log)
+	}))))
 
-  mux.Handle("GET /api/v1/shows/{id}/cover", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-    covers.Read(w, r, db, appDirectory, log)
-  }))
+	mux.Handle("PUT /api/v1/movies/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("movies", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := movies.Update(w, r, db, appDirectory, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
 
-  mux.Handle("PUT /api/v1/shows/{id}/cover", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-    covers.Update(w, r, db, appDirectory, log)
-  }))
+	mux.Handle("PATCH /api/v1/movies/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("movies", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := movies.Patch(w, r, db, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
 
-  mux.Handle("DELETE /api/v1/shows/{id}/cover", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-    covers.Delete(w, r, db, appDirectory, log)
-  }))
+	mux.Handle("DELETE /api/v1/movies/{id}", middleware.Authorize(access.DeleteLibrary, middleware.Versioned("movies", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := movies.Delete(w, r, db, appDirectory, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
 
-	mux.Handle("GET /api/v1/shows/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		shows.Read(w, r, db, log)
+	mux.Handle("POST /api/v1/movies", middleware.Authorize(access.EditLibrary, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := movies.Create(w, r, db, appDirectory, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	})))
+
+	mux.Handle("GET /api/v1/movies", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return movies.Read(w, r, db, log)
+	})))
+}
+
+// Tags
+
+func Tags(
+  mux *http.ServeMux,
+  db *sql.DB,
+  index *suggest.Index,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/tags/facets", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return tags.Facets(w, r, db, log)
+	})))
+
+	mux.Handle("GET /api/v1/tags/{id}", middleware.Authorize(access.Browse, middleware.Versioned("tags", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return tags.Read(w, r, db, log)
+	}))))
+
+	mux.Handle("PUT /api/v1/tags/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("tags", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := tags.Update(w, r, db, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
+
+	mux.Handle("DELETE /api/v1/tags/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("tags", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := tags.Delete(w, r, db, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
+
+	mux.Handle("POST /api/v1/tags", middleware.Authorize(access.EditLibrary, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := tags.Create(w, r, db, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	})))
+
+	mux.Handle("GET /api/v1/tags", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return tags.Read(w, r, db, log)
+	})))
+}
+
+// People
+
+func People(
+  mux *http.ServeMux,
+  db *sql.DB,
+  appDirectory *string,
+  index *suggest.Index,
+  log *logger.Logger,
+) {
+  mux.Handle("GET /api/v1/people/{id}/headshot", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return covers.Read(w, r, db, appDirectory, log)
+  })))
+
+  mux.Handle("PUT /api/v1/people/{id}/headshot", middleware.Authorize(access.EditLibrary, middleware.Versioned("people", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return covers.Update(w, r, db, appDirectory, log)
+  }))))
+
+  mux.Handle("DELETE /api/v1/people/{id}/headshot", middleware.Authorize(access.EditLibrary, middleware.Versioned("people", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+    return covers.Delete(w, r, db, appDirectory, log)
+  }))))
+
+	mux.Handle("GET /api/v1/people/{id}", middleware.Authorize(access.Browse, middleware.Versioned("people", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return people.Read(w, r, db, log)
+	}))))
+
+	mux.Handle("PUT /api/v1/people/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("people", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := people.Update(w, r, db, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
+
+	mux.Handle("DELETE /api/v1/people/{id}", middleware.Authorize(access.DeleteLibrary, middleware.Versioned("people", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := people.Delete(w, r, db, appDirectory, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
+
+	mux.Handle("POST /api/v1/people", middleware.Authorize(access.EditLibrary, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := people.Create(w, r, db, appDirectory, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	})))
+
+	mux.Handle("GET /api/v1/people", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return people.Read(w, r, db, log)
+	})))
+
+	mux.Handle("PUT /api/v1/credits/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("credits", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return credits.Update(w, r, db, log)
+	}))))
+
+	mux.Handle("DELETE /api/v1/credits/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("credits", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return credits.Delete(w, r, db, log)
+	}))))
+
+	mux.Handle("POST /api/v1/credits", middleware.Authorize(access.EditLibrary, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return credits.Create(w, r, db, log)
+	})))
+}
+
+// Content ratings
+
+func ContentRatings(
+  mux *http.ServeMux,
+  db *sql.DB,
+  index *suggest.Index,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/content-ratings/{id}", middleware.Authorize(access.Browse, middleware.Versioned("content_ratings", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return contentratings.Read(w, r, db, log)
+	}))))
+
+	mux.Handle("PUT /api/v1/content-ratings/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("content_ratings", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := contentratings.Update(w, r, db, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
+
+	mux.Handle("DELETE /api/v1/content-ratings/{id}", middleware.Authorize(access.EditLibrary, middleware.Versioned("content_ratings", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := contentratings.Delete(w, r, db, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	}))))
+
+	mux.Handle("POST /api/v1/content-ratings", middleware.Authorize(access.EditLibrary, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return contentratings.Create(w, r, db, log)
+	})))
+
+	mux.Handle("GET /api/v1/content-ratings", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return contentratings.Read(w, r, db, log)
+	})))
+}
+
+// Profiles
+
+func Profiles(
+  mux *http.ServeMux,
+  db *sql.DB,
+  appDirectory *string,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/profiles/selected", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.ReadSelected(w, r)
+	})))
+
+	mux.Handle("PUT /api/v1/profiles/selected", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.Select(w, r, db, log)
+	})))
+
+	mux.Handle("DELETE /api/v1/profiles/selected", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.Deselect(w, r, db, log)
+	})))
+
+	mux.Handle("GET /api/v1/profiles/{id}/avatar", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.ReadAvatar(w, r, db, appDirectory, log)
+	})))
+
+	mux.Handle("PUT /api/v1/profiles/{id}/avatar", middleware.Authorize(access.ManageProfiles, middleware.Versioned("profiles", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.UpdateAvatar(w, r, db, appDirectory, log)
+	}))))
+
+	mux.Handle("DELETE /api/v1/profiles/{id}/avatar", middleware.Authorize(access.ManageProfiles, middleware.Versioned("profiles", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.DeleteAvatar(w, r, db, appDirectory, log)
+	}))))
+
+	mux.Handle("GET /api/v1/profiles/{id}/history", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.ReadHistory(w, r, db, log)
+	})))
+
+	mux.Handle("DELETE /api/v1/profiles/{id}/history", middleware.Authorize(access.ManageProfiles, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.DeleteHistory(w, r, db, log)
+	})))
+
+	mux.Handle("GET /api/v1/profiles/{id}", middleware.Authorize(access.Browse, middleware.Versioned("profiles", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.Read(w, r, db, log)
+	}))))
+
+	mux.Handle("PUT /api/v1/profiles/{id}", middleware.Authorize(access.ManageProfiles, middleware.Versioned("profiles", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.Update(w, r, db, log)
+	}))))
+
+	mux.Handle("DELETE /api/v1/profiles/{id}", middleware.Authorize(access.ManageProfiles, middleware.Versioned("profiles", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.Delete(w, r, db, appDirectory, log)
+	}))))
+
+	mux.Handle("POST /api/v1/profiles", middleware.Authorize(access.ManageProfiles, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.Create(w, r, db, log)
+	})))
+
+	mux.Handle("GET /api/v1/profiles", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return profiles.Read(w, r, db, log)
+	})))
+}
+
+// Auth
+
+func Auth(
+  mux *http.ServeMux,
+  db *sql.DB,
+  log *logger.Logger,
+) {
+	mux.Handle("POST /api/v1/auth/login", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return auth.Login(w, r, db, log)
+	}))
+
+	mux.Handle("POST /api/v1/auth/logout", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return auth.Logout(w, r, db, log)
 	}))
 
-	mux.Handle("PUT /api/v1/shows/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		shows.Update(w, r, db, appDirectory, log)
+	mux.Handle("GET /api/v1/auth/me", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return auth.Me(w, r)
 	}))
 
-	mux.Handle("DELETE /api/v1/shows/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		shows.Delete(w, r, db, appDirectory, log)
+	mux.Handle("POST /api/v1/auth/login/2fa", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return auth.LoginTwoFactor(w, r, db, log)
 	}))
 
-	mux.Handle("POST /api/v1/shows", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		shows.Create(w, r, db, appDirectory, log)
+	mux.Handle("POST /api/v1/auth/2fa", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return auth.EnrollTwoFactor(w, r, db, log)
 	}))
 
-	mux.Handle("GET /api/v1/shows", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		shows.Read(w, r, db, log)
+	mux.Handle("POST /api/v1/auth/2fa/confirm", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return auth.ConfirmTwoFactor(w, r, db, log)
+	}))
+
+	mux.Handle("POST /api/v1/auth/2fa/recovery-codes", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return auth.RegenerateRecoveryCodes(w, r, db, log)
+	}))
+
+	mux.Handle("DELETE /api/v1/auth/2fa", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return auth.DisableTwoFactor(w, r, db, log)
 	}))
 }
 
-// Movies
+// Users
 
-func Movies(
+func Users(
   mux *http.ServeMux,
   db *sql.DB,
   appDirectory *string,
   log *logger.Logger,
 ) {
-  mux.Handle("GET /api/v1/movies/{id}/cover", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-    covers.Read(w, r, db, appDirectory, log)
-  }))
+	mux.Handle("GET /api/v1/setup", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.SetupRequired(w, r, db, log)
+	}))
+
+	mux.Handle("POST /api/v1/setup", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.Setup(w, r, db, log)
+	}))
+
+	mux.Handle("GET /api/v1/roles", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.Roles(w, r)
+	}))
+
+	mux.Handle("PUT /api/v1/users/{id}/role", middleware.Authorize(access.ManageUsers, middleware.Versioned("users", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.UpdateRole(w, r, db, log)
+	}))))
+
+	mux.Handle("DELETE /api/v1/users/{id}/2fa", middleware.Authorize(access.ManageUsers, middleware.Versioned("users", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.ResetTwoFactor(w, r, db, log)
+	}))))
+
+	mux.Handle("GET /api/v1/users/{id}", middleware.AuthorizeSelf(access.ManageUsers, middleware.Versioned("users", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.Read(w, r, db, log)
+	}))))
+
+	mux.Handle("PUT /api/v1/users/{id}", middleware.AuthorizeSelf(access.ManageUsers, middleware.Versioned("users", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.Update(w, r, db, log)
+	}))))
+
+	mux.Handle("DELETE /api/v1/users/{id}", middleware.Authorize(access.ManageUsers, middleware.Versioned("users", db, log, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.Delete(w, r, db, appDirectory, log)
+	}))))
+
+	mux.Handle("POST /api/v1/users", middleware.Authorize(access.ManageUsers, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.Create(w, r, db, log)
+	})))
+
+	mux.Handle("GET /api/v1/users", middleware.Authorize(access.ManageUsers, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return users.Read(w, r, db, log)
+	})))
+}
+
+// Tokens
+
+func Tokens(
+  mux *http.ServeMux,
+  db *sql.DB,
+  log *logger.Logger,
+) {
+	mux.Handle("PUT /api/v1/tokens/{id}", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return tokens.Update(w, r, db, log)
+	}))
 
-  mux.Handle("PUT /api/v1/movies/{id}/cover", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-    covers.Update(w, r, db, appDirectory, log)
-  }))
+	mux.Handle("DELETE /api/v1/tokens/{id}", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return tokens.Delete(w, r, db, log)
+	}))
 
-  mux.Handle("DELETE /api/v1/movies/{id}/cover", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-    covers.Delete(w, r, db, appDirectory, log)
-  }))
+	mux.Handle("POST /api/v1/tokens", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return tokens.Create(w, r, db, log)
+	}))
 
- 	mux.Handle("GET /api/v1/movies/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		movies.Read(w, r, db, log)
+	mux.Handle("GET /api/v1/tokens", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return tokens.Read(w, r, db, log)
 	}))
+}
 
-	mux.Handle("PUT /api/v1/movies/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		movies.Update(w, r, db, appDirectory, log)
+// Single sign-on
+
+func SSO(
+  mux *http.ServeMux,
+  db *sql.DB,
+  provider *oidc.Provider,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/auth/oidc/login", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return sso.Login(w, r, db, provider, log)
 	}))
 
-	mux.Handle("DELETE /api/v1/movies/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		movies.Delete(w, r, db, appDirectory, log)
+	mux.Handle("GET /api/v1/auth/oidc/callback", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return sso.Callback(w, r, db, provider, log)
 	}))
 
-	mux.Handle("POST /api/v1/movies", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		movies.Create(w, r, db, appDirectory, log)
+	mux.Handle("GET /api/v1/auth/oidc/link", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return sso.Link(w, r, db, provider, log)
 	}))
 
-	mux.Handle("GET /api/v1/movies", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		movies.Read(w, r, db, log)
+	mux.Handle("DELETE /api/v1/auth/oidc/link", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return sso.Unlink(w, r, db, log)
 	}))
+}
+
+// Settings
 
+func Settings(
+  mux *http.ServeMux,
+  db *sql.DB,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/settings", middleware.Authorize(access.ManageServer, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return settings.Read(w, r, db, log)
+	})))
+
+	mux.Handle("PUT /api/v1/settings", middleware.Authorize(access.ManageServer, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return settings.Update(w, r, db, log)
+	})))
+}
+
+// Progress
+
+func Progress(
+  mux *http.ServeMux,
+  db *sql.DB,
+  log *logger.Logger,
+) {
+	mux.Handle("PUT /api/v1/progress/{mediaId}", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return progress.Update(w, r, db, log)
+	})))
+
+	mux.Handle("PUT /api/v1/progress/{mediaId}/watched", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return progress.MarkWatched(w, r, db, log)
+	})))
+
+	mux.Handle("DELETE /api/v1/progress/{mediaId}/watched", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return progress.MarkUnwatched(w, r, db, log)
+	})))
+
+	mux.Handle("PUT /api/v1/shows/{id}/watched", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return progress.MarkShowWatched(w, r, db, log)
+	})))
 
+	mux.Handle("DELETE /api/v1/shows/{id}/watched", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return progress.MarkShowUnwatched(w, r, db, log)
+	})))
+}
+
+// Home
+
+func Home(
+  mux *http.ServeMux,
+  db *sql.DB,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/home/continue", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return home.Continue(w, r, db, log)
+	})))
+
+	mux.Handle("GET /api/v1/home/next-up", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return home.NextUp(w, r, db, log)
+	})))
+}
+
+// Lists
+
+func Lists(
+  mux *http.ServeMux,
+  db *sql.DB,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/watchlist", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return lists.Read(w, r, db, "watchlist", log)
+	})))
+
+	mux.Handle("PUT /api/v1/watchlist/{mediaId}", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return lists.Add(w, r, db, "watchlist", log)
+	})))
+
+	mux.Handle("DELETE /api/v1/watchlist/{mediaId}", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return lists.Remove(w, r, db, "watchlist", log)
+	})))
+
+	mux.Handle("PUT /api/v1/watchlist/{mediaId}/position", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return lists.Move(w, r, db, "watchlist", log)
+	})))
+
+	mux.Handle("GET /api/v1/favorites", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return lists.Read(w, r, db, "favorites", log)
+	})))
+
+	mux.Handle("PUT /api/v1/favorites/{mediaId}", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return lists.Add(w, r, db, "favorites", log)
+	})))
+
+	mux.Handle("DELETE /api/v1/favorites/{mediaId}", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return lists.Remove(w, r, db, "favorites", log)
+	})))
+
+	mux.Handle("PUT /api/v1/favorites/{mediaId}/position", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return lists.Move(w, r, db, "favorites", log)
+	})))
+}
+
+// Reviews
+
+func Reviews(
+  mux *http.ServeMux,
+  db *sql.DB,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/ratings/{mediaId}", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return reviews.Read(w, r, db, log)
+	})))
+
+	mux.Handle("PUT /api/v1/ratings/{mediaId}", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return reviews.Rate(w, r, db, log)
+	})))
+
+	mux.Handle("DELETE /api/v1/ratings/{mediaId}", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return reviews.Unrate(w, r, db, log)
+	})))
+
+	mux.Handle("PUT /api/v1/reviews/{id}/hidden", middleware.Authorize(access.ModerateReviews, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return reviews.SetHidden(w, r, db, true, log)
+	})))
+
+	mux.Handle("DELETE /api/v1/reviews/{id}/hidden", middleware.Authorize(access.ModerateReviews, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return reviews.SetHidden(w, r, db, false, log)
+	})))
+
+	mux.Handle("DELETE /api/v1/reviews/{id}", middleware.Authorize(access.ModerateReviews, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return reviews.Delete(w, r, db, log)
+	})))
+}
+
+// Recommendations
+
+func Recommendations(
+  mux *http.ServeMux,
+  db *sql.DB,
+  engine *recommend.Engine,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/recommendations", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return recommendations.Read(w, r, db, engine, log)
+	})))
+
+	mux.Handle("GET /api/v1/movies/{id}/similar", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return recommendations.Similar(w, r, db, engine, log)
+	})))
+}
+
+// Suggestions
+
+func Suggestions(
+  mux *http.ServeMux,
+  index *suggest.Index,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/v1/suggest", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return suggestions.Read(w, r, index, log)
+	})))
+}
+
+// Batch
+
+func Batch(
+  mux *http.ServeMux,
+  db *sql.DB,
+  appDirectory *string,
+  index *suggest.Index,
+  log *logger.Logger,
+) {
+	mux.Handle("POST /api/v1/batch", middleware.Authorize(access.EditLibrary, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		err := batch.Execute(w, r, db, appDirectory, log)
+		if err == nil {
+			index.Refresh(log)
+		}
+		return err
+	})))
+}
+
+// GraphQL
+
+func GraphQL(
+  mux *http.ServeMux,
+  db *sql.DB,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/graphql", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return graph.Execute(w, r, db, log)
+	})))
+
+	mux.Handle("POST /api/graphql", middleware.Authorize(access.Browse, middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return graph.Execute(w, r, db, log)
+	})))
+}
+
+// OpenAPI
+
+func OpenAPI(
+  mux *http.ServeMux,
+  document *openapi.Document,
+  log *logger.Logger,
+) {
+	mux.Handle("GET /api/openapi.json", middleware.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		return spec.Read(w, r, document, log)
+	}))
 }
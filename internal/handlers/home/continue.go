@@ -0,0 +1,109 @@
+package home
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets the movies and episodes the viewer started but has not finished, most
+// recently watched first. Items the viewer is no longer allowed to watch are
+// left out.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /home/continue
+//   - Auth?       : True
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of items to return, 20 by default and at most 100.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Feed items, each returning media_type, movie or episode and show, progress.
+func Continue(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var limit int = functions.Limit(r.URL.Query().Get("limit"), defaultLimit, maximumLimit)
+	var progress []types.Progress = []types.Progress{}
+	var items []types.FeedItem = []types.FeedItem{}
+
+	rows, err := database.Query(`
+		SELECT
+			media_id, media_type, position, duration, watched, last_watched_date
+		FROM
+			progress
+		WHERE
+			viewer_id = ? AND position > 0
+		ORDER BY
+			last_watched_date DESC
+		`,
+		access.Viewer(r),
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	for rows.Next() {
+		var entry types.Progress
+
+		if err := rows.Scan(
+			&entry.MediaId,
+			&entry.MediaType,
+			&entry.Position,
+			&entry.Duration,
+			&entry.Watched,
+			&entry.LastWatchedDate,
+		); err != nil {
+			rows.Close()
+			log.Error(functionId, fmt.Sprintf("Failed to scan progress. %v", err))
+			return err
+		}
+
+		progress = append(progress, entry)
+	}
+	rows.Close()
+
+	for index := range progress {
+		var item *types.FeedItem
+
+		if len(items) == limit {
+			break
+		}
+
+		if progress[index].MediaType == "movie" {
+			item, err = movieItem(database, progress[index].MediaId, r)
+		} else {
+			item, err = episodeItem(database, progress[index].MediaId, r)
+		}
+
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		if item == nil {
+			continue
+		}
+
+		item.Progress = &progress[index]
+		items = append(items, *item)
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   items,
+	}.ToClient(w)
+	return nil
+}
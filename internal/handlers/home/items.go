@@ -0,0 +1,105 @@
+package home
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Number of items a feed returns when no limit is given, and the most it
+// returns when one is. Feeds are not paged like collections are: they are
+// ranked anew on every request, skipping items the request may not watch, so
+// a cursor into one would go stale as soon as anything is watched.
+const (
+	defaultLimit int = 20
+	maximumLimit int = 100
+)
+
+// Returns the feed item of a movie, or nil if the movie is hidden or the
+// request may not watch it.
+func movieItem(database *sql.DB, id string, r *http.Request) (*types.FeedItem, error) {
+	var movie types.Movie = types.Movie{Id: id}
+
+	condition, arguments := access.RatingCondition(r, "content_rating_id")
+
+	if err := database.QueryRow(
+		fmt.Sprintf(`
+			SELECT
+				title, description
+			FROM
+				movies
+			WHERE
+				id = ? AND hidden = 0 AND %s
+			`,
+			condition,
+		),
+		append([]any{id}, arguments...)...,
+	).Scan(
+		&movie.Title,
+		&movie.Description,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	movie.Cover = map[string]any{
+		"exists": true,
+		"url":    "/api/v1/movies/" + id + "/cover",
+	}
+
+	return &types.FeedItem{MediaType: "movie", Movie: &movie}, nil
+}
+
+// Returns the feed item of an episode along with its show, or nil if the show
+// is hidden or the request may not watch the episode.
+func episodeItem(database *sql.DB, id string, r *http.Request) (*types.FeedItem, error) {
+	var episode types.Episode = types.Episode{Id: id}
+	var show types.Show
+	var title sql.NullString
+
+	condition, arguments := access.EpisodeCondition(r)
+
+	if err := database.QueryRow(
+		fmt.Sprintf(`
+			SELECT
+				episodes.parent_id, episodes.season_number, episodes.episode_number, episodes.title, shows.title
+			FROM
+				episodes
+			JOIN
+				shows ON shows.id = episodes.parent_id
+			WHERE
+				episodes.id = ? AND shows.hidden = 0 AND %s
+			`,
+			condition,
+		),
+		append([]any{id}, arguments...)...,
+	).Scan(
+		&episode.ParentId,
+		&episode.SeasonNumber,
+		&episode.EpisodeNumber,
+		&title,
+		&show.Title,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	episode.Title = title.String
+	show.Id = episode.ParentId
+	show.Cover = map[string]any{
+		"exists": true,
+		"url":    "/api/v1/shows/" + show.Id + "/cover",
+	}
+
+	return &types.FeedItem{MediaType: "episode", Episode: &episode, Show: &show}, nil
+}
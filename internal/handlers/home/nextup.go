@@ -0,0 +1,136 @@
+package home
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets the next episode to watch of every show the viewer is watching, shows
+// watched most recently first. The next episode is the first unwatched episode
+// after the last one the viewer finished, by season and episode number.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /home/next-up
+//   - Auth?       : True
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of items to return, 20 by default and at most 100.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Feed items, each returning media_type, episode, show, progress.
+func NextUp(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var viewerId string = access.Viewer(r)
+	var limit int = functions.Limit(r.URL.Query().Get("limit"), defaultLimit, maximumLimit)
+	var items []types.FeedItem = []types.FeedItem{}
+
+	type position struct {
+		showId  string
+		season  int
+		episode int
+	}
+
+	var positions []position = []position{}
+
+	// The last finished episode of each show. SQLite fills the bare columns
+	// from the row holding the latest watch date.
+	rows, err := database.Query(`
+		SELECT
+			episodes.parent_id, episodes.season_number, episodes.episode_number, MAX(progress.last_watched_date)
+		FROM
+			progress
+		JOIN
+			episodes ON episodes.id = progress.media_id
+		WHERE
+			progress.viewer_id = ? AND progress.media_type = 'episode' AND progress.watched = 1
+		GROUP BY
+			episodes.parent_id
+		ORDER BY
+			MAX(progress.last_watched_date) DESC
+		`,
+		viewerId,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	for rows.Next() {
+		var entry position
+		var lastWatchedDate string
+
+		if err := rows.Scan(
+			&entry.showId,
+			&entry.season,
+			&entry.episode,
+			&lastWatchedDate,
+		); err != nil {
+			rows.Close()
+			log.Error(functionId, fmt.Sprintf("Failed to scan progress. %v", err))
+			return err
+		}
+
+		positions = append(positions, entry)
+	}
+	rows.Close()
+
+	condition, arguments := access.EpisodeCondition(r)
+	condition = "id NOT IN (SELECT media_id FROM progress WHERE viewer_id = ? AND watched = 1) AND " + condition
+	arguments = append([]any{viewerId}, arguments...)
+
+	for _, entry := range positions {
+		if len(items) == limit {
+			break
+		}
+
+		episodeId, err := queries.NextEpisode(database, entry.showId, entry.season, entry.episode, condition, arguments)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		if episodeId == "" {
+			continue
+		}
+
+		item, err := episodeItem(database, episodeId, r)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		if item == nil {
+			continue
+		}
+
+		progress, err := queries.ProgressFor(database, viewerId, []string{episodeId})
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		item.Progress = progress[episodeId]
+		items = append(items, *item)
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   items,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,78 @@
+package lists
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Adds a show or movie to the end of the watchlist or favorites of the viewer.
+// Adding an item that is already on the list leaves it where it is.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /watchlist/{mediaId}, /favorites/{mediaId}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - mediaId     : REQUIRED. UUID of the show or movie.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The list item, returning media_type, position, added_date.
+func Add(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  list string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("mediaId")
+	var viewerId string = access.Viewer(r)
+	var functionId string = access.RequestId(r)
+	var item types.ListItem
+
+	mediaType, err := media(database, id, r)
+	if err != nil {
+		return err
+	}
+
+	// The no-op update lets RETURNING report the existing row on a conflict.
+	if err := database.QueryRow(`
+		INSERT INTO
+			list_items (viewer_id, list, media_id, media_type, position, added_date)
+		VALUES
+			(?, ?, ?, ?, (SELECT COALESCE(MAX(position), 0) + 1 FROM list_items WHERE viewer_id = ? AND list = ?), ?)
+		ON CONFLICT (viewer_id, list, media_id) DO UPDATE SET
+			position = position
+		RETURNING
+			media_type, position, added_date
+		`,
+		viewerId,
+		list,
+		id,
+		mediaType,
+		viewerId,
+		list,
+		time.Now().Format("2006-01-02 15:04:05"),
+	).Scan(
+		&item.MediaType,
+		&item.Position,
+		&item.AddedDate,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to add to %s. %v", list, err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   item,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,37 @@
+package lists
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+)
+
+// Returns whether the id belongs to a "show" or a "movie" that the request may
+// see. Returns the problem to send to the client otherwise.
+func media(database *sql.DB, id string, r *http.Request) (string, error) {
+	var found int
+
+	condition, arguments := access.RatingCondition(r, "content_rating_id")
+
+	for _, candidate := range []struct {
+		mediaType string
+		statement string
+	}{
+		{"show", "SELECT 1 FROM shows WHERE id = ? AND hidden = 0 AND " + condition},
+		{"movie", "SELECT 1 FROM movies WHERE id = ? AND hidden = 0 AND " + condition},
+	} {
+		err := database.QueryRow(candidate.statement, append([]any{id}, arguments...)...).Scan(&found)
+		if err == nil {
+			return candidate.mediaType, nil
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", err
+		}
+	}
+
+	return "", problems.New(problems.NotFound, "No show or movie could be found with the given id.")
+}
@@ -0,0 +1,104 @@
+package lists
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Moves a show or movie to another place on the watchlist or favorites of the
+// viewer, shifting the items in between. Positions past the end of the list move
+// the item to the end.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /watchlist/{mediaId}/position, /favorites/{mediaId}/position
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - mediaId     : REQUIRED. UUID of the show or movie.
+//
+// # HTTP request multipart form:
+//   - position    : REQUIRED. New place on the list, starting at 1.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Move(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  list string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("mediaId")
+	var viewerId string = access.Viewer(r)
+	var functionId string = access.RequestId(r)
+	var current, count int
+
+	position, err := strconv.Atoi(r.FormValue("position"))
+	if err != nil || position < 1 {
+		return problems.New(problems.InvalidRequest, "The position value must be an integer of at least 1.")
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			position, (SELECT COUNT(*) FROM list_items WHERE viewer_id = ? AND list = ?)
+		FROM
+			list_items
+		WHERE
+			viewer_id = ? AND list = ? AND media_id = ?
+		`,
+		viewerId,
+		list,
+		viewerId,
+		list,
+		id,
+	).Scan(&current, &count); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return problems.New(problems.NotFound, fmt.Sprintf("The %s does not contain the given id.", list))
+		}
+
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	position = functions.Minimum(position, count)
+
+	// Shift the items between the old and the new place by one towards the
+	// place the item left, then put the item in the freed place.
+	for _, statement := range []struct {
+		query     string
+		arguments []any
+	}{
+		{
+			"UPDATE list_items SET position = position + 1 WHERE viewer_id = ? AND list = ? AND position >= ? AND position < ?",
+			[]any{viewerId, list, position, current},
+		},
+		{
+			"UPDATE list_items SET position = position - 1 WHERE viewer_id = ? AND list = ? AND position > ? AND position <= ?",
+			[]any{viewerId, list, current, position},
+		},
+		{
+			"UPDATE list_items SET position = ? WHERE viewer_id = ? AND list = ? AND media_id = ?",
+			[]any{position, viewerId, list, id},
+		},
+	} {
+		if _, err := database.Exec(statement.query, statement.arguments...); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to reorder %s. %v", list, err))
+			return err
+		}
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,152 @@
+package lists
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets the shows and movies on the watchlist or favorites of the viewer, in the
+// order the viewer arranged them. Items the viewer is no longer allowed to
+// watch are left out.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /watchlist, /favorites
+//   - Auth?       : True
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of items per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : List items, each returning media_type, position, added_date, show or movie.
+//   - total       : Number of items across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  list string,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var items []types.ListItem = []types.ListItem{}
+
+	showCondition, showArguments := access.RatingCondition(r, "shows.content_rating_id")
+	movieCondition, movieArguments := access.RatingCondition(r, "movies.content_rating_id")
+
+	page, err := queries.NewPage(r, 50, []queries.SortKey{{Expression: "list_items.position"}, {Expression: "list_items.media_id"}})
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+	}
+
+	from := `
+		list_items
+		LEFT JOIN
+			shows ON shows.id = list_items.media_id AND list_items.media_type = 'show'
+		LEFT JOIN
+			movies ON movies.id = list_items.media_id AND list_items.media_type = 'movie'
+	`
+	condition := fmt.Sprintf(`
+		list_items.viewer_id = ? AND list_items.list = ?
+		AND (
+			(shows.id IS NOT NULL AND shows.hidden = 0 AND %s)
+			OR
+			(movies.id IS NOT NULL AND movies.hidden = 0 AND %s)
+		)
+		`,
+		showCondition,
+		movieCondition,
+	)
+	arguments := append(append([]any{access.Viewer(r), list}, showArguments...), movieArguments...)
+
+	total, err := queries.Count(database, from, condition, arguments)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	seek, seekArguments := page.Condition()
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				list_items.media_id, list_items.media_type, list_items.position, list_items.added_date,
+				COALESCE(shows.title, movies.title), COALESCE(shows.description, movies.description)
+				%s
+			FROM
+				%s
+			WHERE
+				%s AND %s
+			%s
+			`,
+			page.Columns(),
+			from,
+			condition,
+			seek,
+			page.Order(),
+		),
+		append(arguments, seekArguments...)...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var item types.ListItem
+		var id, title, description string
+
+		if err := rows.Scan(append([]any{
+			&id,
+			&item.MediaType,
+			&item.Position,
+			&item.AddedDate,
+			&title,
+			&description,
+		}, page.Scan()...)...); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to scan list item. %v", err))
+			return err
+		}
+
+		if item.MediaType == "show" {
+			item.Show = &types.Show{
+				Id:          id,
+				Title:       title,
+				Description: description,
+				Cover: map[string]any{
+					"exists": true,
+					"url":    "/api/v1/shows/" + id + "/cover",
+				},
+			}
+		} else {
+			item.Movie = &types.Movie{
+				Id:          id,
+				Title:       title,
+				Description: description,
+				Cover: map[string]any{
+					"exists": true,
+					"url":    "/api/v1/movies/" + id + "/cover",
+				},
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	items = queries.Trim(page, items)
+
+	responses.Paged(r, items, total, page.Next(), page.Prev()).ToClient(w)
+	return nil
+}
@@ -0,0 +1,51 @@
+package lists
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Removes a show or movie from the watchlist or favorites of the viewer.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /watchlist/{mediaId}, /favorites/{mediaId}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - mediaId     : REQUIRED. UUID of the show or movie.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Remove(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  list string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("mediaId")
+	var functionId string = access.RequestId(r)
+
+	removed, err := queries.RemoveFromList(database, access.Viewer(r), list, id)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to remove from %s. %v", list, err))
+		return err
+	}
+
+	if !removed {
+		return problems.New(problems.NotFound, fmt.Sprintf("The %s does not contain the given id.", list))
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -7,86 +7,64 @@ import (
 	"net/http"
 	"path"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
 	"github.com/andrewdotjs/watchify-server/internal/types"
 	"github.com/andrewdotjs/watchify-server/internal/upload"
-	"github.com/google/uuid"
 )
 
-// Uploads a series, its episodes, and its cover to the database and stores them within the
+// Uploads a movie and its cover to the database and stores them within the
 // storage folder.
 //
 // # Specifications:
 //   - Method      : POST
-//   - Endpoint    : /series
-//   - Auth?       : False
+//   - Endpoint    : /movies
+//   - Auth?       : True
 //
 // # HTTP request multipart form:
-//   - video-files : REQUIRED. Uploaded video files.
-//   - name        : REQUIRED. Name of the soon-to-be uploaded series.
-//   - description : REQUIRED. Description of the series.
+//   - video       : REQUIRED. Uploaded video file.
+//   - cover       : REQUIRED. Uploaded cover, should be a 400x600 jpg.
+//   - title       : OPTIONAL. Title of the movie.
+//   - description : OPTIONAL. Description of the movie.
+//   - hidden      : OPTIONAL. "true" to hide the movie from listings.
 //
 // # HTTP response JSON contents:
 //   - status_code : HTTP status code.
-//   - message     : If error, Message detailing the error.
-//   - data        : Series id, title
+//   - data        : Movie id, title, description
 func Create(
   w http.ResponseWriter,
   r *http.Request,
   database *sql.DB,
   appDirectory *string,
   log *logger.Logger,
-) {
+) error {
 	var uploadDirectory string = path.Join(*appDirectory, "storage", "videos")
 	var uploadedVideo, uploadedCover []*multipart.FileHeader
 	var movieStruct types.Movie
-	var functionId string = uuid.NewString()
+	var functionId string = access.RequestId(r)
 
 	if err := r.ParseMultipartForm(1 << 40); err != nil { // Error handling if form data exceeds 1TB
 	  log.Error(functionId, fmt.Sprintf("%v", err))
-		responses.Error{
-			Type:   "null",
-			Title:  "Incomplete request",
-			Status: 400,
-			Detail: "The upload form exceeded 1TB.",
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "The upload form exceeded 1TB.")
 	}
 
 	uploadedCover = r.MultipartForm.File["cover"]
 	if len(uploadedCover) == 0 {
 	  log.Error(functionId, "Received no cover in request")
-		responses.Error{
-			Type:   "null",
-			Title:  "Bad request",
-			Status: 400,
-			Detail: "No uploaded cover present in form.",
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "No uploaded cover present in form.")
 	}
 
 	uploadedVideo = r.MultipartForm.File["video"]
 	if len(uploadedVideo) == 0 {
 	  log.Error(functionId, "Received no videos in request")
-		responses.Error{
-			Type:   "null",
-			Title:  "Bad request",
-			Status: 400,
-			Detail: "No uploaded videos present in form.",
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "No uploaded videos present in form.")
 	}
 
 	if len(uploadedVideo) > 1 {
 	  log.Error(functionId, "Received too many videos in request, limit is 1")
-		responses.Error{
-			Type:   "null",
-			Title:  "Bad request",
-			Status: 400,
-			Detail: "Too many uploaded videos present in form, limit is 1.",
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "Too many uploaded videos present in form, limit is 1.")
 	}
 
 	movieStruct = types.Movie{
@@ -118,4 +96,5 @@ func Create(
 		Status: 201,
 		Data:   movieStruct,
 	}.ToClient(w)
+	return nil
 }
@@ -8,236 +8,70 @@ import (
 	"os"
 	"path"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
-	"github.com/google/uuid"
 )
 
-// Deletes a series, its episodes, and its cover from the database and storage folders.
+// Deletes a movie and its cover from the database and storage folders, along
+// with everything attached to it, such as credits, tags and ratings.
 //
 // # Specifications:
 //   - Method      : DELETE
-//   - Endpoint    : /series/{id}
-//   - Auth?       : False
+//   - Endpoint    : /movies/{id}
+//   - Auth?       : True
 //
 // # HTTP request path parameters:
-//   - id          : REQUIRED. UUID of the series.
+//   - id          : REQUIRED. UUID of the movie.
 //
 // # HTTP response JSON contents:
 //   - status_code : HTTP status code.
-//   - message     : If error, Message detailing the error.
 func Delete(
   w http.ResponseWriter,
   r *http.Request,
   database *sql.DB,
   appDirectory *string,
   log *logger.Logger,
-) {
+) error {
 	var id string = r.PathValue("id")
-	var functionId string = uuid.NewString()
-	var coverFileName string = ""
-	var movieFileName string = ""
-
+	var functionId string = access.RequestId(r)
 
 	if id == "" {
-		responses.Error{
-			Type:     "null",
-			Title:    "Invalid Request",
-			Status:   400,
-			Detail:   "Id was not present in the URL.",
-			Instance: r.URL.Path,
-		}.ToClient(w)
-		return
-	}
-
-	// Stage 1, find the video that is being used by the to-be-deleted movie and delete it.
-	if err := database.QueryRow(`
-  	SELECT
-			file_name
-  	FROM
-			movies
-  	WHERE
-			id=?
-    `,
-		id,
-	).Scan(&movieFileName); err != nil && !errors.Is(err, sql.ErrNoRows) {
-		var response responses.Error
-
-		switch {
-		default:
-		  log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-		}
-
-		response.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "Id was not present in the URL.")
 	}
 
-	if err := os.Remove(path.Join(*appDirectory, "storage", "videos", movieFileName)); err != nil {
-		var response responses.Error
-
-		switch {
-		case errors.Is(err, os.ErrNotExist):
-			response = responses.Error{
-				Type:     "null",
-				Title:    "File system and database out-of-sync.",
-				Status:   500,
-				Detail:   "Attempted to delete a non-existant video file that exists in the database.",
-				Instance: r.URL.Path,
-			}
-		default:
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-			log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-		}
-
-		response.ToClient(w)
-		return
-	}
-
-	if _, err := database.Exec(`
-	  DELETE FROM
-			movies
-		WHERE
-			id=?
-		`,
-		id,
-	); err != nil {
-		var response responses.Error
-
-		switch {
-		default:
-		log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-		}
-
-		response.ToClient(w)
-		return
+	transaction, err := database.Begin()
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Error deleting movie information from the database.")
 	}
 
-	// Stage 2, find the cover that is being used by the to-be-deleted movie and delete it.
-	if err := database.QueryRow(`
-  	SELECT
-			file_name
-  	FROM
-      covers
-  	WHERE
-			parent_id=?
-    `,
-		id,
-	).Scan(&coverFileName); err != nil && !errors.Is(err, sql.ErrNoRows) {
-		var response responses.Error
-
-		switch {
-		default:
-		  log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-		}
+	defer transaction.Rollback()
 
-		response.ToClient(w)
-		return
+	files, err := queries.DeleteMedia(transaction, "movies", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return problems.New(problems.NotFound, "No movie could be found with the given id.")
 	}
 
-	// Stage 2, delete the cover from the database.
-	if _, err := database.Exec(`
-	  DELETE FROM
-			covers
-	  WHERE
-			parent_id=?
-  	`,
-		id,
-	); err != nil {
-		var response responses.Error
-
-		switch {
-		default:
-		  log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-		}
-
-		response.ToClient(w)
-		return
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Error deleting movie information from the database.")
 	}
 
-	// Stage 4, delete the cover from the storage.
-	if err := os.Remove(
-		path.Join(*appDirectory, "storage", "covers", coverFileName),
-	); err != nil && !errors.Is(err, os.ErrNotExist) {
-		var response responses.Error
-
-		switch {
-		default:
-		  log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-		}
-
-		response.ToClient(w)
-		return
+	if err := transaction.Commit(); err != nil {
+		return problems.Wrap(problems.Internal, err, "Error deleting movie information from the database.")
 	}
 
-	// Stage 5, delete the movie itself from the database.
-	if _, err := database.Exec(`
-  	DELETE FROM
-			movies
-  	WHERE
-			id=?
-  	`,
-		id,
-	); err != nil {
-		var response responses.Error
-
-		switch {
-		default:
-		  log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
+	// The movie is gone either way, so files that can't be removed are only
+	// worth logging.
+	for _, file := range files {
+		if err := os.Remove(path.Join(*appDirectory, "storage", file)); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to remove %s of the deleted movie. %v", file, err))
 		}
-
-		response.ToClient(w)
-		return
 	}
 
 	responses.Status{
 		Status: 200,
 	}.ToClient(w)
+	return nil
 }
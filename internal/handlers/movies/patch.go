@@ -0,0 +1,120 @@
+package movies
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/patch"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Changes the title, description or visibility of a movie, and returns the
+// changed movie. Unlike Update, members can be cleared, and members left out of
+// the patch are left as they are.
+//
+// # Specifications:
+//   - Method      : PATCH
+//   - Endpoint    : /movies/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the movie.
+//
+// # HTTP request body:
+//   - A JSON Merge Patch (application/merge-patch+json), a JSON Patch
+//     (application/json-patch+json) or a form of title, description and
+//     hidden. Removing the description clears it, removing hidden shows the
+//     movie, while the title can't be removed.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The changed movie, as returned by Read.
+func Patch(
+  w http.ResponseWriter,
+  r *http.Request,
+  db *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var movie types.Movie = types.Movie{Id: id}
+	var functionId string = access.RequestId(r)
+
+	condition, conditionArguments := access.RatingCondition(r, "content_rating_id")
+
+	if err := db.QueryRow(
+		fmt.Sprintf(`
+			SELECT
+				title, description, hidden
+			FROM
+				movies
+			WHERE
+				id = ? AND %s
+			`,
+			condition,
+		),
+		append([]any{id}, conditionArguments...)...,
+	).Scan(
+		&movie.Title,
+		&movie.Description,
+		&movie.Hidden,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return problems.New(problems.NotFound, "No movie could be found with the given id.")
+		}
+
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	document, err := patch.Apply(r, map[string]any{
+		"title":       movie.Title,
+		"description": movie.Description,
+		"hidden":      movie.Hidden,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := patch.Only(document, "title", "description", "hidden"); err != nil {
+		return err
+	}
+
+	if movie.Title, err = patch.Text(document, "title", 50, true); err != nil {
+		return err
+	}
+
+	if movie.Description, err = patch.Text(document, "description", 1000, false); err != nil {
+		return err
+	}
+
+	if movie.Hidden, err = patch.Bool(document, "hidden"); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(
+		`
+			UPDATE
+				movies
+			SET
+				title = ?, description = ?, hidden = ?, last_modified = ?
+			WHERE
+				id = ?
+		`,
+		movie.Title,
+		movie.Description,
+		movie.Hidden,
+		time.Now().Format("01-02-2006 15:04:05"),
+		movie.Id,
+	); err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to change the movie.")
+	}
+
+	log.Info(functionId, fmt.Sprintf("Patched movie with ID %s", id))
+	return Read(w, r, db, log)
+}
@@ -2,166 +2,330 @@ package movies
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
 	"github.com/andrewdotjs/watchify-server/internal/types"
-	"github.com/google/uuid"
 )
 
-// Gets and returns an array of series stored in the database.
+// Gets and returns a movie, or a page of the movies stored in the database.
 //
 // # Specifications:
 //   - Method      : GET
-//   - Endpoint    : /series/{id}
-//   - Auth?       : False
+//   - Endpoint    : /movies/{id}, /movies
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : OPTIONAL. UUID of the movie, the query parameters only apply without it.
 //
 // # HTTP request query parameters:
-//   - series_id   : OPTIONAL. UUID of the series.
+//   - hidden      : OPTIONAL. "true" to include hidden movies.
+//   - tags        : OPTIONAL. Comma separated tag ids or names to filter by.
+//   - match       : OPTIONAL. "all" to require every tag, "any" (default) otherwise.
+//   - orderedBy   : OPTIONAL. "upload_date" for newest first, "rating" for best rated first, title otherwise.
+//   - filter      : OPTIONAL. Filter expression, such as `year>=2000 and tag:anime`.
+//   - sort        : OPTIONAL. Comma separated fields to sort by, descending if prefixed
+//     with a minus, such as `-rating,title`. Takes precedence over orderedBy.
+//   - limit       : OPTIONAL. Number of movies per page, 30 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
 //
 // # HTTP response JSON contents:
 //   - status_code : HTTP status code.
-//   - message     : If error, Message detailing the error.
-//   - data        : Series contents, each returning id, episode count, title, description.
+//   - data        : Movies, each returning id, title, description and cover.
+//   - total       : Number of movies across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
 func Read(
   w http.ResponseWriter,
   r *http.Request,
   database *sql.DB,
   log *logger.Logger,
-) {
+) error {
 	var id string = r.PathValue("id")
 	var hidden bool = (r.URL.Query().Get("hidden") == "true")
-	var movieStruct types.Movie = types.Movie{}
-	var functionId string = uuid.NewString()
+	var tagFilter []string = functions.SplitList(r.URL.Query().Get("tags"))
+	var orderedBy string = r.URL.Query().Get("orderedBy")
+	var functionId string = access.RequestId(r)
 
 	// Return all movies if no ID.
 	if id == "" {
-		var movieArray []types.Movie
+		var tagQuery string = ""
+		var arguments []any = []any{hidden}
+		var order []queries.SortKey = []queries.SortKey{{Expression: "title"}, {Expression: "id"}}
 
-		log.Info(functionId, "No ID given, attempting to return all movies")
-		log.Info(functionId, "Querying database for all movies, limit 30")
-		rows, err := database.Query(`
-			SELECT
-				id, title, description, upload_date, last_modified
-			FROM
-				movies
-			WHERE
-			  hidden = ?
-			LIMIT
-				30
-    	`,
-			hidden,
-		)
+		switch orderedBy {
+		case "upload_date":
+			order = []queries.SortKey{{Expression: "upload_date", Descending: true}, {Expression: "id"}}
+		case "rating":
+			order = []queries.SortKey{{Expression: queries.AverageRating("movies"), Descending: true}, {Expression: "id"}}
+		}
 
-		if err != nil {
-		  if !errors.Is(err, sql.ErrNoRows) {
-				log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
-				responses.Status{
-					Type:     "null",
-					Title:    "Unknown Error",
-					Status:   500,
-					Detail:   fmt.Sprintf("%v", err),
-					Instance: r.URL.Path,
-				}.ToClient(w)
-				return
+		if sort := r.URL.Query().Get("sort"); sort != "" {
+			keys, err := queries.Sort(sort, queries.MovieFields)
+			if err != nil {
+				return queries.Malformed(err)
 			}
 
-			log.Info(functionId, "No movies present in database")
-			responses.Status{
-				Status: 200,
-				Data:   nil,
-			}.ToClient(w)
-			return
+			order = keys
 		}
 
-		defer rows.Close()
-		for rows.Next() {
-			var movie types.Movie
-
-			if err := rows.Scan(
-				&movie.Id,
-				&movie.Title,
-				&movie.Description,
-				&movie.UploadDate,
-				&movie.LastModified,
-			); err != nil {
-				defer database.Close()
-				log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
-			}
+		filter, filterArguments, err := queries.Filter(r.URL.Query().Get("filter"), queries.MovieFields)
+		if err != nil {
+			return queries.Malformed(err)
+		}
 
-			movie.Cover = map[string]any{
-				"exists": true,
-				"url":    ("/api/v1/movies/" + movie.Id + "/cover"),
-			}
+		page, err := queries.NewPage(r, 30, order)
+		if err != nil {
+			return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+		}
+
+		condition, conditionArguments := access.RatingCondition(r, "content_rating_id")
+		tagQuery = "AND " + condition
+		arguments = append(arguments, conditionArguments...)
+
+		if len(tagFilter) > 0 {
+			subquery, subqueryArguments := queries.TagFilter(tagFilter, r.URL.Query().Get("match") == "all")
+			tagQuery += fmt.Sprintf(" AND id IN (%s)", subquery)
+			arguments = append(arguments, subqueryArguments...)
+		}
 
-			movieArray = append(movieArray, movie)
+		tagQuery += " AND " + filter
+		arguments = append(arguments, filterArguments...)
+
+		total, err := queries.Count(database, "movies", "hidden = ? "+tagQuery, arguments)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		log.Info(functionId, "No ID given, attempting to return all movies")
+		log.Info(functionId, fmt.Sprintf("Querying database for a page of movies, limit %d", page.Limit))
+		movieArray, err := queries.MoviePage(database, "hidden = ? "+tagQuery, arguments, page)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		if err := embedTags(r, database, movieArray, log, &functionId); err != nil {
+			return err
+		}
+
+		if err := embedContentRatings(r, database, movieArray, log, &functionId); err != nil {
+			return err
+		}
+
+		if err := embedProgress(r, database, movieArray, log, &functionId); err != nil {
+			return err
+		}
+
+		if err := embedLists(r, database, movieArray, log, &functionId); err != nil {
+			return err
+		}
+
+		if err := embedRatings(r, database, movieArray, log, &functionId); err != nil {
+			return err
 		}
 
 		log.Info(functionId, fmt.Sprintf("Returned %d movies", len(movieArray)))
-		responses.Status{
-			Status: 200,
-			Data:   movieArray,
-		}.ToClient(w)
-		return
+		responses.Paged(r, movieArray, total, page.Next(), page.Prev()).ToClient(w)
+		return nil
 	}
 
 	log.Info(functionId, fmt.Sprintf("ID %s given, attempting to return movie", id))
 
-	if err := database.QueryRow(
-		`
-		SELECT
-			id, title, description, hidden, file_extension, file_name, upload_date, last_modified
-		FROM
-			movies
-		WHERE
-			id = ?
-		`,
-		id,
-	).Scan(
-		&movieStruct.Id,
-		&movieStruct.Title,
-		&movieStruct.Description,
-		&movieStruct.Hidden,
-		&movieStruct.FileExtension,
-		&movieStruct.FileName,
-		&movieStruct.UploadDate,
-		&movieStruct.LastModified,
-	); err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-		  log.Info(functionId, "No movie found with provided ID")
-			responses.Error{
-				Type:     "null",
-				Title:    "Data not found",
-				Status:   400,
-				Detail:   "No movie could be found with the given id.",
-				Instance: r.URL.Path,
-			}.ToClient(w)
-		default:
-		  log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
-			responses.Error{
-				Type:     "null",
-				Title:    "Unknown Error",
-				Status:   500,
-				Detail:   fmt.Sprintf("%v", err),
-				Instance: r.URL.Path,
-			}.ToClient(w)
-		}
+	condition, conditionArguments := access.RatingCondition(r, "content_rating_id")
 
-		return
+	movieArray, err := queries.Movies(database, "id = ? AND "+condition, append([]any{id}, conditionArguments...), "")
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
 	}
 
-	movieStruct.Cover = map[string]any{
-		"exists": true,
-		"url":    ("/api/v1/movie/" + movieStruct.Id + "/cover"),
+	if len(movieArray) == 0 {
+		log.Info(functionId, "No movie found with provided ID")
+		return problems.New(problems.NotFound, "No movie could be found with the given id.")
 	}
 
+	if err := embedTags(r, database, movieArray, log, &functionId); err != nil {
+		return err
+	}
+
+	if err := embedContentRatings(r, database, movieArray, log, &functionId); err != nil {
+		return err
+	}
+
+	if err := embedProgress(r, database, movieArray, log, &functionId); err != nil {
+		return err
+	}
+
+	if err := embedLists(r, database, movieArray, log, &functionId); err != nil {
+		return err
+	}
+
+	if err := embedRatings(r, database, movieArray, log, &functionId); err != nil {
+		return err
+	}
+
+	credits, err := queries.CreditsFor(database, []string{id})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to retrieve credits. %v", err))
+		return err
+	}
+
+	movieArray[0].Credits = credits[id]
+
 	log.Info(functionId, fmt.Sprintf("Successfully returned information on movie with ID %s", id))
 	responses.Status{
 		Status: 200,
-		Data:   movieStruct,
+		Data:   movieArray[0],
 	}.ToClient(w)
+	return nil
+}
+
+// Attaches the tags of every given movie to it. Returns the error if the tags
+// could not be retrieved.
+func embedTags(
+  r *http.Request,
+  database *sql.DB,
+  movies []types.Movie,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	var ids []string
+
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+	}
+
+	tags, err := queries.TagsFor(database, ids)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve tags. %v", err))
+		return err
+	}
+
+	for index := range movies {
+		movies[index].Tags = tags[movies[index].Id]
+	}
+
+	return nil
+}
+
+// Attaches the content rating of every given movie to it. Returns the error if
+// the ratings could not be retrieved.
+func embedContentRatings(
+  r *http.Request,
+  database *sql.DB,
+  movies []types.Movie,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	ratings, err := queries.ContentRatings(database)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve content ratings. %v", err))
+		return err
+	}
+
+	for index := range movies {
+		movies[index].ContentRating = ratings[movies[index].ContentRatingId]
+	}
+
+	return nil
+}
+
+// Attaches the playback progress of the viewer to every given movie they have
+// played. Returns the error if the progress could not be retrieved.
+func embedProgress(
+  r *http.Request,
+  database *sql.DB,
+  movies []types.Movie,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	var ids []string
+
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+	}
+
+	progress, err := queries.ProgressFor(database, access.Viewer(r), ids)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve progress. %v", err))
+		return err
+	}
+
+	for index := range movies {
+		movies[index].Progress = progress[movies[index].Id]
+	}
+
+	return nil
+}
+
+// Attaches whether every given movie is on the watchlist and favorites of the
+// viewer. Returns the error if the lists could not be retrieved.
+func embedLists(
+  r *http.Request,
+  database *sql.DB,
+  movies []types.Movie,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	var ids []string
+
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+	}
+
+	watchlist, err := queries.ListMembership(database, access.Viewer(r), queries.Watchlist, ids)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve watchlist. %v", err))
+		return err
+	}
+
+	favorites, err := queries.ListMembership(database, access.Viewer(r), queries.Favorites, ids)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve favorites. %v", err))
+		return err
+	}
+
+	for index := range movies {
+		movies[index].InWatchlist = watchlist[movies[index].Id]
+		movies[index].Favorite = favorites[movies[index].Id]
+	}
+
+	return nil
+}
+
+// Attaches the average user score of every given movie to it, along with the
+// score the viewer gave. Returns the error if the scores could not be
+// retrieved.
+func embedRatings(
+  r *http.Request,
+  database *sql.DB,
+  movies []types.Movie,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	var ids []string
+
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+	}
+
+	ratings, err := queries.RatingsFor(database, access.Viewer(r), ids)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve user ratings. %v", err))
+		return err
+	}
+
+	for index := range movies {
+		movies[index].Rating = ratings[movies[index].Id]
+	}
+
+	return nil
 }
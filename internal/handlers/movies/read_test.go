@@ -0,0 +1,56 @@
+package movies_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+// Returns the url member of the object name of item.
+func link(item map[string]any, name string) string {
+	object, _ := item[name].(map[string]any)
+	url, _ := object["url"].(string)
+	return url
+}
+
+func TestRead(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+
+	var titles []string = []string{"Arrival", "Blade Runner", "Contact"}
+	var ids []string
+
+	for _, title := range titles {
+		ids = append(ids, admin.CreateMovie(title))
+	}
+
+	// Pages follow each other in title order, and link to the cover of every
+	// movie.
+	var read []string
+	for target := "/api/v1/movies?limit=2"; target != ""; {
+		page := admin.Send("GET", target).Expect(200).JSON()
+		target, _ = page["next"].(string)
+
+		data, _ := page["data"].([]any)
+		for _, value := range data {
+			movie, _ := value.(map[string]any)
+			title, _ := movie["title"].(string)
+			read = append(read, title)
+
+			admin.Send("GET", link(movie, "cover")).Expect(200)
+		}
+	}
+
+	if strings.Join(read, ", ") != strings.Join(titles, ", ") {
+		t.Errorf("the pages held %v", read)
+	}
+
+	movie := admin.Send("GET", "/api/v1/movies/"+ids[1]).Expect(200).Data()
+	if movie["title"] != titles[1] || movie["file_name"] == nil || link(movie, "cover") != "/api/v1/movies/"+ids[1]+"/cover" {
+		t.Errorf("read the movie %v", movie)
+	}
+
+	admin.Send("GET", "/api/v1/movies/unknown").Expect(404)
+}
+
@@ -0,0 +1,128 @@
+package people
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/andrewdotjs/watchify-server/internal/upload"
+	"github.com/google/uuid"
+)
+
+// Adds an actor, director or writer to the people database. The headshot is
+// stored alongside the covers of shows and movies.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /people
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - name        : REQUIRED. Full name of the person.
+//   - biography   : OPTIONAL. Short biography of the person.
+//   - headshot    : OPTIONAL. Uploaded headshot image, should be a 400x600 jpg.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The created person.
+func Create(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  appDirectory *string,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var currentTime string = time.Now().Format("2006-01-02 15:04:05")
+	var uploadDirectory string = path.Join(*appDirectory, "storage", "covers")
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) { // Error handling if form data exceeds 10MB
+		log.Error(functionId, fmt.Sprintf("%v", err))
+		return problems.New(problems.InvalidRequest, "The upload form exceeded 10MB.")
+	}
+
+	person := types.Person{
+		Id:           uuid.NewString(),
+		Name:         functions.Sanitize(r.FormValue("name")),
+		Biography:    functions.Sanitize(r.FormValue("biography")),
+		UploadDate:   currentTime,
+		LastModified: currentTime,
+	}
+
+	if err := validate(&person); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			people (id, name, biography, upload_date, last_modified)
+		VALUES
+			(?, ?, ?, ?, ?)
+		`,
+		person.Id,
+		person.Name,
+		person.Biography,
+		person.UploadDate,
+		person.LastModified,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to insert person. %v", err))
+		return err
+	}
+
+	if r.MultipartForm != nil && len(r.MultipartForm.File["headshot"]) > 0 {
+		headshot := types.Cover{ParentId: person.Id}
+
+		if err := upload.Cover(
+			r.MultipartForm.File["headshot"][0],
+			&headshot,
+			database,
+			&uploadDirectory,
+			log,
+			&functionId,
+		); err != nil {
+			return err
+		}
+	}
+
+	person.Headshot = map[string]any{
+		"exists": true,
+		"url":    ("/api/v1/people/" + person.Id + "/headshot"),
+	}
+
+	log.Info(functionId, fmt.Sprintf("Created person with ID %s", person.Id))
+	responses.Status{
+		Status: 201,
+		Data:   person,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks that the name and biography of a person are acceptable. Returns the
+// problem to send to the client if they are not.
+func validate(person *types.Person) error {
+	var name string = ""
+	var detail string = ""
+
+	switch {
+	case person.Name == "":
+		name, detail = "name", "The name value was empty."
+	case len(person.Name) > 100:
+		name, detail = "name", "The name value was larger than 100 bytes. In UTF-8 encoding, English characters are 1 byte each."
+	case len(person.Biography) > 1000:
+		name, detail = "biography", "The biography value was larger than 1000 bytes. In UTF-8 encoding, English characters are 1 byte each."
+	default:
+		return nil
+	}
+
+	return problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+}
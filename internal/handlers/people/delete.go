@@ -0,0 +1,77 @@
+package people
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/covers"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Deletes a person, their credits and their headshot from the database and
+// storage folders.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /people/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the person.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Delete(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  appDirectory *string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	result, err := database.Exec(`
+		DELETE FROM
+			people
+		WHERE
+			id = ?
+		`,
+		id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete person. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No person could be found with the given id.")
+	}
+
+	if _, err := database.Exec(`
+		DELETE FROM
+			credits
+		WHERE
+			person_id = ?
+		`,
+		id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete the person's credits. %v", err))
+		return err
+	}
+
+	if err := covers.Remove(database, appDirectory, id); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to remove the person's headshot. %v", err))
+		return err
+	}
+
+	log.Info(functionId, fmt.Sprintf("Deleted person with ID %s", id))
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,162 @@
+package people
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets and returns either every person stored in the database, or a single
+// person along with their filmography within the library.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /people/{id}, /people
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : OPTIONAL. UUID of the person.
+//
+// # HTTP request query parameters:
+//   - name        : OPTIONAL. Only return people whose name contains this value.
+//   - limit       : OPTIONAL. Number of people per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Person contents, each returning id, name, biography and headshot.
+//   - total       : Number of people across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var name string = r.URL.Query().Get("name")
+	var person types.Person = types.Person{}
+	var functionId string = access.RequestId(r)
+
+	// Return all people if no ID.
+	if id == "" {
+		var people []types.Person = []types.Person{}
+
+		page, err := queries.NewPage(r, 50, []queries.SortKey{{Expression: "name"}, {Expression: "id"}})
+		if err != nil {
+			return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+		}
+
+		total, err := queries.Count(database, "people", "name LIKE '%' || ? || '%'", []any{name})
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		seek, seekArguments := page.Condition()
+
+		rows, err := database.Query(
+			fmt.Sprintf(`
+				SELECT
+					id, name, biography, upload_date, last_modified %s
+				FROM
+					people
+				WHERE
+					name LIKE '%%' || ? || '%%' AND %s
+				%s
+				`,
+				page.Columns(),
+				seek,
+				page.Order(),
+			),
+			append([]any{name}, seekArguments...)...,
+		)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			var person types.Person
+
+			if err := rows.Scan(append([]any{
+				&person.Id,
+				&person.Name,
+				&person.Biography,
+				&person.UploadDate,
+				&person.LastModified,
+			}, page.Scan()...)...); err != nil {
+				log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+				return err
+			}
+
+			person.Headshot = map[string]any{
+				"exists": true,
+				"url":    ("/api/v1/people/" + person.Id + "/headshot"),
+			}
+
+			people = append(people, person)
+		}
+
+		people = queries.Trim(page, people)
+
+		responses.Paged(r, people, total, page.Next(), page.Prev()).ToClient(w)
+		return nil
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			id, name, biography, upload_date, last_modified
+		FROM
+			people
+		WHERE
+			id = ?
+		`,
+		id,
+	).Scan(
+		&person.Id,
+		&person.Name,
+		&person.Biography,
+		&person.UploadDate,
+		&person.LastModified,
+	); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			log.Info(functionId, "No person found with provided ID")
+			return problems.New(problems.NotFound, "No person could be found with the given id.")
+		default:
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+	}
+
+	filmography, err := queries.Filmography(database, person.Id, func(ratingColumn string) (string, []any) {
+		return access.RatingCondition(r, ratingColumn)
+	})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to retrieve filmography. %v", err))
+		return err
+	}
+
+	person.Filmography = filmography
+	person.Headshot = map[string]any{
+		"exists": true,
+		"url":    ("/api/v1/people/" + person.Id + "/headshot"),
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   person,
+	}.ToClient(w)
+	return nil
+}
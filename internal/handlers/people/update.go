@@ -0,0 +1,105 @@
+package people
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Updates the name or biography of a person. Values that are not present in the
+// form are left untouched. Headshots are replaced through /people/{id}/headshot.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /people/{id}
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - name        : OPTIONAL. Full name of the person.
+//   - biography   : OPTIONAL. Short biography of the person.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The updated person.
+func Update(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+	var person types.Person = types.Person{}
+
+	if err := database.QueryRow(`
+		SELECT
+			id, name, biography, upload_date
+		FROM
+			people
+		WHERE
+			id = ?
+		`,
+		id,
+	).Scan(
+		&person.Id,
+		&person.Name,
+		&person.Biography,
+		&person.UploadDate,
+	); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			log.Info(functionId, "No person found with provided ID")
+			return problems.New(problems.NotFound, "No person could be found with the given id.")
+		default:
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+	}
+
+	if name := functions.Sanitize(r.FormValue("name")); name != "" {
+		person.Name = name
+	}
+
+	if biography := functions.Sanitize(r.FormValue("biography")); biography != "" {
+		person.Biography = biography
+	}
+
+	person.LastModified = time.Now().Format("2006-01-02 15:04:05")
+
+	if err := validate(&person); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if _, err := database.Exec(`
+		UPDATE
+			people
+		SET
+			name = ?, biography = ?, last_modified = ?
+		WHERE
+			id = ?
+		`,
+		person.Name,
+		person.Biography,
+		person.LastModified,
+		person.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to update person. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   person,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,82 @@
+package profiles
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/handlers/covers"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+)
+
+// Returns the avatar of a profile of the logged in user, or a placeholder if it
+// has none.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /profiles/{id}/avatar
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the profile.
+func ReadAvatar(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  appDirectory *string,
+  log *logger.Logger,
+) error {
+	if _, err := ownedProfile(database, r.PathValue("id"), r); err != nil {
+		return err
+	}
+
+	return covers.Read(w, r, database, appDirectory, log)
+}
+
+// Replaces the avatar of a profile of the logged in user with the uploaded image.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /profiles/{id}/avatar
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - cover       : REQUIRED. Uploaded image, should be a square jpg.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func UpdateAvatar(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  appDirectory *string,
+  log *logger.Logger,
+) error {
+	if _, err := ownedProfile(database, r.PathValue("id"), r); err != nil {
+		return err
+	}
+
+	return covers.Update(w, r, database, appDirectory, log)
+}
+
+// Removes the avatar of a profile of the logged in user.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /profiles/{id}/avatar
+//   - Auth?       : True
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func DeleteAvatar(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  appDirectory *string,
+  log *logger.Logger,
+) error {
+	if _, err := ownedProfile(database, r.PathValue("id"), r); err != nil {
+		return err
+	}
+
+	return covers.Delete(w, r, database, appDirectory, log)
+}
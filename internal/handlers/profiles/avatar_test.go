@@ -0,0 +1,30 @@
+package profiles_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestAvatarConditional(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	id := admin.Form("POST", "/api/v1/profiles", url.Values{"name": {"Kids"}}).Expect(201).String("id")
+
+	target := "/api/v1/profiles/" + id
+	avatar := []servertest.File{{Field: "cover", Name: "avatar.jpg", Content: []byte("avatar")}}
+	before := admin.Send("GET", target).Expect(200).Header.Get("ETag")
+
+	admin.Upload("PUT", target+"/avatar", nil, avatar).Expect(428)
+	admin.Upload("PUT", target+"/avatar", nil, avatar, "If-Match", before).Expect(200)
+
+	// The avatar belongs to the profile, so replacing it changes the profile.
+	after := admin.Send("GET", target).Expect(200).Header.Get("ETag")
+	if after == before {
+		t.Fatal("replacing the avatar left the ETag of the profile as it was")
+	}
+
+	admin.Send("DELETE", target+"/avatar", "If-Match", before).Expect(412)
+	admin.Send("DELETE", target+"/avatar", "If-Match", after).Expect(200)
+}
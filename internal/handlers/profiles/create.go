@@ -0,0 +1,245 @@
+package profiles
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Creates a new viewing profile for the logged in user. A profile with a maximum
+// content rating must also be given a PIN, otherwise anyone could lift the limit
+// again.
+//
+// # Specifications:
+//   - Method                : POST
+//   - Endpoint              : /profiles
+//   - Auth?                 : True
+//
+// # HTTP request multipart form:
+//   - name                  : REQUIRED. Display name of the profile.
+//   - audio_language        : OPTIONAL. Preferred audio language as a BCP 47 tag, such as "en" or "pt-BR".
+//   - subtitle_language     : OPTIONAL. Preferred subtitle language as a BCP 47 tag.
+//   - max_content_rating_id : OPTIONAL. UUID of the most mature rating the profile may watch.
+//   - pin                   : OPTIONAL. 4 to 8 digit PIN, required with max_content_rating_id.
+//
+// # HTTP response JSON contents:
+//   - status_code           : HTTP status code.
+//   - data                  : The created profile.
+func Create(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var ratingId string = r.FormValue("max_content_rating_id")
+	var pin string = r.FormValue("pin")
+	var maxRatingId any = nil
+	var profile types.Profile = types.Profile{
+		Id:               uuid.NewString(),
+		UserId:           access.User(r).Id,
+		Name:             functions.Sanitize(r.FormValue("name")),
+		AudioLanguage:    r.FormValue("audio_language"),
+		SubtitleLanguage: r.FormValue("subtitle_language"),
+		UploadDate:       time.Now().Format("2006-01-02 15:04:05"),
+	}
+
+	profile.LastModified = profile.UploadDate
+
+	if err := validate(&profile); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if pin != "" {
+		if err := validatePin(pin); err != nil {
+			return err
+		}
+
+		hash, err := secrets.Hash(pin)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to hash PIN. %v", err))
+			return err
+		}
+
+		profile.PinHash = hash
+		profile.HasPin = true
+	}
+
+	if ratingId != "" {
+		if !profile.HasPin {
+			return problems.New(problems.InvalidRequest, "A pin is required when setting max_content_rating_id.")
+		}
+
+		ratings, err := queries.ContentRatings(database)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		if ratings[ratingId] == nil {
+			return problems.New(problems.InvalidRequest, "No content rating could be found with the given max_content_rating_id.")
+		}
+
+		profile.MaxContentRating = ratings[ratingId]
+		maxRatingId = ratingId
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			profiles (id, user_id, name, max_content_rating_id, audio_language, subtitle_language, pin_hash, upload_date, last_modified)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+		profile.Id,
+		profile.UserId,
+		profile.Name,
+		maxRatingId,
+		profile.AudioLanguage,
+		profile.SubtitleLanguage,
+		profile.PinHash,
+		profile.UploadDate,
+		profile.LastModified,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to insert profile. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 201,
+		Data:   profile,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks that the values of a profile are acceptable. Returns the problem to
+// send to the client if they are not.
+func validate(profile *types.Profile) error {
+	var name string = ""
+	var detail string = ""
+
+	switch {
+	case profile.Name == "":
+		name, detail = "name", "The name value is required."
+	case len(profile.Name) > 50:
+		name, detail = "name", "The name value was larger than 50 bytes. In UTF-8 encoding, English characters are 1 byte each."
+	case !isLanguage(profile.AudioLanguage) || !isLanguage(profile.SubtitleLanguage):
+		name, detail = "audio_language", "The audio_language and subtitle_language values must be BCP 47 language tags, such as \"en\" or \"pt-BR\"."
+	default:
+		return nil
+	}
+
+	return problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+}
+
+// Reports whether the value looks like a BCP 47 language tag: a 2 or 3 letter
+// language code, optionally followed by subtags of 1 to 8 letters or digits.
+// Empty values stand for no preference and are accepted as well.
+func isLanguage(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	subtags := strings.Split(value, "-")
+
+	if len(value) > 35 || len(subtags[0]) < 2 || len(subtags[0]) > 3 {
+		return false
+	}
+
+	for index, subtag := range subtags {
+		if len(subtag) == 0 || len(subtag) > 8 {
+			return false
+		}
+
+		for _, character := range subtag {
+			isLetter := (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+			isDigit := character >= '0' && character <= '9'
+
+			if !isLetter && !(isDigit && index > 0) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Checks that a new PIN consists of 4 to 8 digits. Returns the problem to send
+// to the client if it does not.
+func validatePin(pin string) error {
+	var valid bool = len(pin) >= 4 && len(pin) <= 8
+
+	for _, character := range pin {
+		if character < '0' || character > '9' {
+			valid = false
+		}
+	}
+
+	if valid {
+		return nil
+	}
+
+	return problems.New(problems.InvalidRequest, "A PIN must consist of 4 to 8 digits.", problems.Param("pin", "A PIN must consist of 4 to 8 digits."))
+}
+
+// Checks the pin form value against the PIN of the profile. Profiles without a
+// PIN let every change through. Wrong PINs are counted, and once too many were
+// entered the profile is locked for a while, so that the PIN can't be guessed.
+// Returns the problem to send to the client if the PIN is missing, wrong or
+// can't be entered yet.
+func checkPin(database *sql.DB, profile *types.Profile, w http.ResponseWriter, r *http.Request) error {
+	if !profile.HasPin {
+		return nil
+	}
+
+	lockedUntil, err := queries.PinLockedUntil(database, profile.Id)
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to check the PIN.")
+	}
+
+	if wait := time.Until(lockedUntil); wait > 0 {
+		return pinLocked(w, wait)
+	}
+
+	if secrets.Verify(profile.PinHash, r.FormValue("pin")) {
+		if err := queries.ResetPinFailures(database, profile.Id); err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to check the PIN.")
+		}
+
+		return nil
+	}
+
+	lockedUntil, err = queries.FailPin(database, profile.Id)
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to check the PIN.")
+	}
+
+	if wait := time.Until(lockedUntil); wait > 0 {
+		return pinLocked(w, wait)
+	}
+
+	return problems.New(problems.Forbidden, "The pin value is missing or does not match the PIN of the profile.")
+}
+
+// Returns the problem telling the client that no PIN can be entered for wait,
+// which the Retry-After header carries in seconds.
+func pinLocked(w http.ResponseWriter, wait time.Duration) error {
+	var seconds int = int(math.Ceil(wait.Seconds()))
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	return problems.New(problems.TooManyAttempts, fmt.Sprintf("Too many wrong PINs were entered. Try again in %d seconds.", seconds))
+}
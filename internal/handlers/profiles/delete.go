@@ -0,0 +1,66 @@
+package profiles
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/covers"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Deletes a profile of the logged in user along with its avatar and watch
+// history. Profiles with a PIN can only be deleted with that PIN, so that a
+// limited profile can not be removed to get around its limit.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /profiles/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the profile.
+//
+// # HTTP request multipart form:
+//   - pin         : OPTIONAL. PIN of the profile, required if it has one.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Delete(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  appDirectory *string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	profile, err := ownedProfile(database, id, r)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPin(database, profile, w, r); err != nil {
+		log.Info(functionId, "Rejected profile deletion with a wrong PIN")
+		return err
+	}
+
+	if err := covers.Remove(database, appDirectory, profile.Id); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to remove avatar. %v", err))
+		return err
+	}
+
+	if err := queries.DeleteProfile(database, profile.Id); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete profile. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,169 @@
+package profiles
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns the watch history of a profile of the logged in user, most recently
+// watched first. An entry is kept for every movie and episode whose playback
+// was started while the profile was selected.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /profiles/{id}/history
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the profile.
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of entries per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Entries, each returning media_id, media_type, title, play_count and last_watched_date.
+//   - total       : Number of entries across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func ReadHistory(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var entries []types.WatchHistoryEntry = []types.WatchHistoryEntry{}
+
+	profile, err := ownedProfile(database, r.PathValue("id"), r)
+	if err != nil {
+		return err
+	}
+
+	page, err := queries.NewPage(r, 50, []queries.SortKey{{Expression: "watch_history.last_watched_date", Descending: true}, {Expression: "watch_history.media_id"}})
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+	}
+
+	total, err := queries.Count(database, "watch_history", "profile_id = ?", []any{profile.Id})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	seek, seekArguments := page.Condition()
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				watch_history.media_id,
+				watch_history.media_type,
+				COALESCE(movies.title, episodes.title, ''),
+				watch_history.play_count,
+				watch_history.last_watched_date
+				%s
+			FROM
+				watch_history
+			LEFT JOIN
+				movies ON movies.id = watch_history.media_id
+			LEFT JOIN
+				episodes ON episodes.id = watch_history.media_id
+			WHERE
+				watch_history.profile_id = ? AND %s
+			%s
+			`,
+			page.Columns(),
+			seek,
+			page.Order(),
+		),
+		append([]any{profile.Id}, seekArguments...)...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var entry types.WatchHistoryEntry
+
+		if err := rows.Scan(append([]any{
+			&entry.MediaId,
+			&entry.MediaType,
+			&entry.Title,
+			&entry.PlayCount,
+			&entry.LastWatchedDate,
+		}, page.Scan()...)...); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to scan row. %v", err))
+			return err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	entries = queries.Trim(page, entries)
+
+	responses.Paged(r, entries, total, page.Next(), page.Prev()).ToClient(w)
+	return nil
+}
+
+// Clears the watch history of a profile of the logged in user. Profiles with a
+// PIN require that PIN.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /profiles/{id}/history
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the profile.
+//
+// # HTTP request multipart form:
+//   - pin         : OPTIONAL. PIN of the profile, required if it has one.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func DeleteHistory(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+
+	profile, err := ownedProfile(database, r.PathValue("id"), r)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPin(database, profile, w, r); err != nil {
+		log.Info(functionId, "Rejected clearing watch history with a wrong PIN")
+		return err
+	}
+
+	if _, err := database.Exec(`
+		DELETE FROM
+			watch_history
+		WHERE
+			profile_id = ?
+		`,
+		profile.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to clear watch history. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
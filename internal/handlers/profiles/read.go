@@ -0,0 +1,153 @@
+package profiles
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets and returns either every profile of the logged in user or a single one,
+// along with the maximum content rating of each. PINs are never returned.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /profiles/{id}, /profiles
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : OPTIONAL. UUID of the profile.
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of profiles per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Profiles, each returning id, name, max_content_rating,
+//     audio_language, subtitle_language, has_pin and selected.
+//   - total       : Number of profiles across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+	var selected *types.Profile = access.Profile(r)
+
+	if id != "" {
+		profile, err := ownedProfile(database, id, r)
+		if err != nil {
+			return err
+		}
+
+		responses.Status{
+			Status: 200,
+			Data:   profile,
+		}.ToClient(w)
+		return nil
+	}
+
+	var profiles []types.Profile = []types.Profile{}
+
+	ratings, err := queries.ContentRatings(database)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	page, err := queries.NewPage(r, 50, []queries.SortKey{{Expression: "name"}, {Expression: "id"}})
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+	}
+
+	total, err := queries.Count(database, "profiles", "user_id = ?", []any{access.User(r).Id})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	seek, seekArguments := page.Condition()
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				id, name, COALESCE(max_content_rating_id, ''), audio_language, subtitle_language, pin_hash, upload_date, last_modified %s
+			FROM
+				profiles
+			WHERE
+				user_id = ? AND %s
+			%s
+			`,
+			page.Columns(),
+			seek,
+			page.Order(),
+		),
+		append([]any{access.User(r).Id}, seekArguments...)...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var profile types.Profile
+		var maxRatingId string
+
+		if err := rows.Scan(append([]any{
+			&profile.Id,
+			&profile.Name,
+			&maxRatingId,
+			&profile.AudioLanguage,
+			&profile.SubtitleLanguage,
+			&profile.PinHash,
+			&profile.UploadDate,
+			&profile.LastModified,
+		}, page.Scan()...)...); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to scan row. %v", err))
+			return err
+		}
+
+		profile.HasPin = (profile.PinHash != "")
+		profile.Selected = (selected != nil && selected.Id == profile.Id)
+		profile.MaxContentRating = ratings[maxRatingId]
+		profiles = append(profiles, profile)
+	}
+
+	profiles = queries.Trim(page, profiles)
+
+	responses.Paged(r, profiles, total, page.Next(), page.Prev()).ToClient(w)
+	return nil
+}
+
+// Returns the profile with the given id if it belongs to the logged in user.
+// Profiles of other users are treated as missing. Returns the problem to send
+// to the client otherwise.
+func ownedProfile(database *sql.DB, id string, r *http.Request) (*types.Profile, error) {
+	var selected *types.Profile = access.Profile(r)
+
+	profile, err := queries.Profile(database, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if profile == nil || profile.UserId != access.User(r).Id {
+		return nil, problems.New(problems.NotFound, "No profile could be found with the given id.")
+	}
+
+	profile.Selected = (selected != nil && selected.Id == profile.Id)
+
+	return profile, nil
+}
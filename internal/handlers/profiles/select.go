@@ -0,0 +1,165 @@
+package profiles
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns the profile that requests made with the current session or API token
+// are scoped to.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /profiles/selected
+//   - Auth?       : True
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : OPTIONAL. The selected profile, left out if none is selected.
+func ReadSelected(
+  w http.ResponseWriter,
+  r *http.Request,
+) error {
+	var status responses.Status = responses.Status{Status: 200}
+
+	// A nil profile would be marshalled as null rather than left out.
+	if profile := access.Profile(r); profile != nil {
+		status.Data = profile
+	}
+
+	status.ToClient(w)
+	return nil
+}
+
+// Scopes every following request made with the current session or API token to
+// a profile of the logged in user, which applies its content rating limit and
+// records what it watches. Profiles with a PIN can only be selected with that
+// PIN. Leaving a limited profile for one with a higher limit and no PIN of its
+// own requires the PIN of the limited profile instead.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /profiles/selected
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - profile_id  : REQUIRED. UUID of the profile.
+//   - pin         : OPTIONAL. PIN of the profile, or of the selected profile when leaving it requires one.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The selected profile.
+func Select(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var current *types.Profile = access.Profile(r)
+
+	profile, err := ownedProfile(database, r.FormValue("profile_id"), r)
+	if err != nil {
+		return err
+	}
+
+	guard := profile
+	if !profile.HasPin && current != nil && current.Id != profile.Id && lessLimited(current, profile) {
+		guard = current
+	}
+
+	if err := checkPin(database, guard, w, r); err != nil {
+		log.Info(functionId, "Rejected profile selection with a wrong PIN")
+		return err
+	}
+
+	if err := changeSelection(database, profile.Id, r); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	profile.Selected = true
+
+	responses.Status{
+		Status: 200,
+		Data:   profile,
+	}.ToClient(w)
+	return nil
+}
+
+// Stops scoping requests made with the current session or API token to a
+// profile. Leaving a limited profile requires its PIN, as requests without a
+// profile are not limited at all.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /profiles/selected
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - pin         : OPTIONAL. PIN of the selected profile, required if it is limited.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Deselect(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var current *types.Profile = access.Profile(r)
+
+	if current == nil {
+		responses.Status{
+			Status: 200,
+		}.ToClient(w)
+		return nil
+	}
+
+	if current.MaxContentRating != nil {
+		if err := checkPin(database, current, w, r); err != nil {
+			log.Info(functionId, "Rejected leaving a limited profile with a wrong PIN")
+			return err
+		}
+	}
+
+	if err := changeSelection(database, "", r); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Reports whether the target profile may watch content that the current profile
+// may not.
+func lessLimited(current *types.Profile, target *types.Profile) bool {
+	if current.MaxContentRating == nil {
+		return false
+	}
+
+	return target.MaxContentRating == nil || target.MaxContentRating.Rank > current.MaxContentRating.Rank
+}
+
+// Stores the profile selection on the session or API token the request was
+// authenticated with. Returns the problem to send to the client on failure.
+func changeSelection(database *sql.DB, profileId string, r *http.Request) error {
+	table, column, digest := access.Credential(r)
+
+	if err := queries.SelectProfile(database, table, column, digest, profileId); err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to change the selected profile.")
+	}
+
+	return nil
+}
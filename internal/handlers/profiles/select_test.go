@@ -0,0 +1,31 @@
+package profiles_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestSelectPinLockout(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	profile := admin.Form("POST", "/api/v1/profiles", url.Values{"name": {"Kids"}, "pin": {"1234"}}).Expect(201).String("id")
+
+	for attempt := 1; attempt < queries.PinAttempts; attempt++ {
+		admin.Form("PUT", "/api/v1/profiles/selected", url.Values{"profile_id": {profile}, "pin": {"0000"}}).Expect(403)
+	}
+
+	if retry := admin.Form("PUT", "/api/v1/profiles/selected", url.Values{"profile_id": {profile}, "pin": {"0000"}}).Expect(429); retry.Header.Get("Retry-After") == "" {
+		t.Error("the lockout carries no Retry-After")
+	}
+
+	admin.Form("PUT", "/api/v1/profiles/selected", url.Values{"profile_id": {profile}, "pin": {"1234"}}).Expect(429)
+
+	if _, err := server.DB.Exec("UPDATE pin_failures SET locked_until = '2000-01-01 00:00:00'"); err != nil {
+		t.Fatal(err)
+	}
+
+	admin.Form("PUT", "/api/v1/profiles/selected", url.Values{"profile_id": {profile}, "pin": {"1234"}}).Expect(200)
+}
@@ -0,0 +1,151 @@
+package profiles
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+)
+
+// Updates the name, languages, maximum content rating or PIN of a profile of the
+// logged in user. Renaming and changing languages is always allowed, while
+// changing the limit or the PIN requires the current PIN. A profile without a
+// PIN must be given one through new_pin when a limit is set.
+//
+// # Specifications:
+//   - Method                : PUT
+//   - Endpoint              : /profiles/{id}
+//   - Auth?                 : True
+//
+// # HTTP request multipart form:
+//   - name                  : OPTIONAL. Display name of the profile.
+//   - audio_language        : OPTIONAL. Preferred audio language as a BCP 47 tag, empty to clear it.
+//   - subtitle_language     : OPTIONAL. Preferred subtitle language as a BCP 47 tag, empty to clear it.
+//   - max_content_rating_id : OPTIONAL. UUID of the most mature rating, empty to lift the limit.
+//   - pin                   : OPTIONAL. Current PIN, required when changing the limit or PIN.
+//   - new_pin               : OPTIONAL. New 4 to 8 digit PIN.
+//
+// # HTTP response JSON contents:
+//   - status_code           : HTTP status code.
+//   - data                  : The updated profile.
+func Update(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+	var newPin string = r.FormValue("new_pin")
+	var maxRatingId any = nil
+
+	profile, err := ownedProfile(database, id, r)
+	if err != nil {
+		return err
+	}
+
+	if name := functions.Sanitize(r.FormValue("name")); name != "" {
+		profile.Name = name
+	}
+
+	if _, changes := r.Form["audio_language"]; changes {
+		profile.AudioLanguage = r.FormValue("audio_language")
+	}
+
+	if _, changes := r.Form["subtitle_language"]; changes {
+		profile.SubtitleLanguage = r.FormValue("subtitle_language")
+	}
+
+	if err := validate(profile); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	_, changesRating := r.Form["max_content_rating_id"]
+
+	if changesRating || newPin != "" {
+		if err := checkPin(database, profile, w, r); err != nil {
+			log.Info(functionId, "Rejected profile change with a wrong PIN")
+			return err
+		}
+	}
+
+	if newPin != "" {
+		if err := validatePin(newPin); err != nil {
+			return err
+		}
+
+		hash, err := secrets.Hash(newPin)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to hash PIN. %v", err))
+			return err
+		}
+
+		profile.PinHash = hash
+		profile.HasPin = true
+	}
+
+	if changesRating {
+		var ratingId string = r.FormValue("max_content_rating_id")
+
+		profile.MaxContentRating = nil
+
+		if ratingId != "" {
+			if !profile.HasPin {
+				return problems.New(problems.InvalidRequest, "A new_pin is required when limiting a profile without a PIN.")
+			}
+
+			ratings, err := queries.ContentRatings(database)
+			if err != nil {
+				log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+				return err
+			}
+
+			if ratings[ratingId] == nil {
+				return problems.New(problems.InvalidRequest, "No content rating could be found with the given max_content_rating_id.")
+			}
+
+			profile.MaxContentRating = ratings[ratingId]
+		}
+	}
+
+	if profile.MaxContentRating != nil {
+		maxRatingId = profile.MaxContentRating.Id
+	}
+
+	profile.LastModified = time.Now().Format("2006-01-02 15:04:05")
+
+	if _, err := database.Exec(`
+		UPDATE
+			profiles
+		SET
+			name = ?, max_content_rating_id = ?, audio_language = ?, subtitle_language = ?, pin_hash = ?, last_modified = ?
+		WHERE
+			id = ?
+		`,
+		profile.Name,
+		maxRatingId,
+		profile.AudioLanguage,
+		profile.SubtitleLanguage,
+		profile.PinHash,
+		profile.LastModified,
+		profile.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to update profile. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   profile,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,181 @@
+package progress
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Stores how far the selected profile, or the user when no profile is selected,
+// got into a movie or episode. Clients report this periodically during
+// playback. Once the position passes the watched_threshold setting the video is
+// marked as watched and its resume position goes back to the start. Finished
+// movies are taken off the watchlist.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /progress/{mediaId}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - mediaId     : REQUIRED. UUID of the movie or episode.
+//
+// # HTTP request multipart form:
+//   - position    : REQUIRED. Playback position in seconds.
+//   - duration    : REQUIRED. Length of the video in seconds.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The stored progress.
+func Update(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("mediaId")
+	var functionId string = access.RequestId(r)
+	var progress types.Progress = types.Progress{MediaId: id}
+	var reachedEnd bool
+
+	position, err := seconds(r, "position")
+	if err != nil {
+		return err
+	}
+
+	duration, err := seconds(r, "duration")
+	if err != nil {
+		return err
+	}
+
+	if duration == 0 {
+		return problems.New(problems.InvalidRequest, "The duration must be above 0.", problems.Param("duration", "The duration must be above 0."))
+	}
+
+	mediaType, err := media(database, id, r)
+	if err != nil {
+		return err
+	}
+
+	threshold, err := queries.WatchedThreshold(database)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if position > duration {
+		position = duration
+	}
+
+	// Finished videos start over the next time they are played.
+	if reachedEnd = (position/duration*100 >= threshold); reachedEnd {
+		position = 0
+	}
+
+	if err := database.QueryRow(`
+		INSERT INTO
+			progress (viewer_id, media_id, media_type, position, duration, watched, last_watched_date)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (viewer_id, media_id) DO UPDATE SET
+			position = excluded.position,
+			duration = excluded.duration,
+			watched = watched OR excluded.watched,
+			last_watched_date = excluded.last_watched_date
+		RETURNING
+			media_type, position, duration, watched, last_watched_date
+		`,
+		access.Viewer(r),
+		id,
+		mediaType,
+		position,
+		duration,
+		reachedEnd,
+		time.Now().Format("2006-01-02 15:04:05"),
+	).Scan(
+		&progress.MediaType,
+		&progress.Position,
+		&progress.Duration,
+		&progress.Watched,
+		&progress.LastWatchedDate,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to store progress. %v", err))
+		return err
+	}
+
+	if reachedEnd && mediaType == "movie" {
+		if err := finishMovie(database, id, r); err != nil {
+			log.Error(functionId, err.Error())
+			return err
+		}
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   progress,
+	}.ToClient(w)
+	return nil
+}
+
+// Takes a movie the viewer finished off their watchlist. Returns the problem to
+// send to the client if that failed.
+func finishMovie(database *sql.DB, id string, r *http.Request) error {
+	if _, err := queries.RemoveFromList(database, access.Viewer(r), queries.Watchlist, id); err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to remove from watchlist.")
+	}
+
+	return nil
+}
+
+// Returns whether the id belongs to a "movie" or an "episode" that the request
+// may watch. Returns the problem to send to the client if it belongs to
+// neither.
+func media(database *sql.DB, id string, r *http.Request) (string, error) {
+	var found int
+
+	movieCondition, movieArguments := access.RatingCondition(r, "content_rating_id")
+	episodeCondition, episodeArguments := access.EpisodeCondition(r)
+
+	for _, candidate := range []struct {
+		mediaType string
+		statement string
+		arguments []any
+	}{
+		{"movie", "SELECT 1 FROM movies WHERE id = ? AND " + movieCondition, movieArguments},
+		{"episode", "SELECT 1 FROM episodes WHERE id = ? AND " + episodeCondition, episodeArguments},
+	} {
+		err := database.QueryRow(candidate.statement, append([]any{id}, candidate.arguments...)...).Scan(&found)
+		if err == nil {
+			return candidate.mediaType, nil
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", err
+		}
+	}
+
+	return "", problems.New(problems.NotFound, "No movie or episode could be found with the given id.")
+}
+
+// Returns the form value name as a finite, non-negative number of seconds.
+// ParseFloat also accepts "NaN" and "Inf", which would be stored as is.
+func seconds(r *http.Request, name string) (float64, error) {
+	value, err := strconv.ParseFloat(r.FormValue(name), 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		detail := fmt.Sprintf("The %s must be a finite number of seconds, not below 0.", name)
+		return 0, problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+	}
+
+	return value, nil
+}
@@ -0,0 +1,45 @@
+package progress_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestUpdate(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	movie := admin.CreateMovie("Heat")
+
+	for _, values := range []url.Values{
+		{"position": {"NaN"}, "duration": {"600"}},
+		{"position": {"60"}, "duration": {"Inf"}},
+		{"position": {"-Inf"}, "duration": {"600"}},
+		{"position": {"-1"}, "duration": {"600"}},
+		{"position": {"60"}, "duration": {"0"}},
+	} {
+		if kind := admin.Form("PUT", "/api/v1/progress/"+movie, values).Expect(400).Problem(); kind != problems.InvalidRequest.URI {
+			t.Errorf("%v failed with %s", values, kind)
+		}
+	}
+
+	var stored int
+	if err := server.DB.QueryRow("SELECT COUNT(*) FROM progress").Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored != 0 {
+		t.Errorf("%d invalid positions were stored", stored)
+	}
+
+	progress := admin.Form("PUT", "/api/v1/progress/"+movie, url.Values{
+		"position": {"60.5"},
+		"duration": {"600"},
+	}).Expect(200).Data()
+
+	if position, _ := progress["position"].(float64); position != 60.5 {
+		t.Errorf("stored the position %v", progress["position"])
+	}
+}
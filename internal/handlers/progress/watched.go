@@ -0,0 +1,263 @@
+package progress
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Marks a movie or episode as watched without playing it. Movies are taken off
+// the watchlist.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /progress/{mediaId}/watched
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - mediaId     : REQUIRED. UUID of the movie or episode.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func MarkWatched(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("mediaId")
+	var functionId string = access.RequestId(r)
+
+	mediaType, err := media(database, id, r)
+	if err != nil {
+		return err
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			progress (viewer_id, media_id, media_type, position, duration, watched, last_watched_date)
+		VALUES
+			(?, ?, ?, 0, 0, 1, ?)
+		ON CONFLICT (viewer_id, media_id) DO UPDATE SET
+			position = 0, watched = 1, last_watched_date = excluded.last_watched_date
+		`,
+		access.Viewer(r),
+		id,
+		mediaType,
+		time.Now().Format("2006-01-02 15:04:05"),
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to mark as watched. %v", err))
+		return err
+	}
+
+	if mediaType == "movie" {
+		if err := finishMovie(database, id, r); err != nil {
+			log.Error(functionId, err.Error())
+			return err
+		}
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Marks a movie or episode as unwatched, which also forgets its resume position.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /progress/{mediaId}/watched
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - mediaId     : REQUIRED. UUID of the movie or episode.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func MarkUnwatched(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+
+	if _, err := database.Exec(`
+		DELETE FROM
+			progress
+		WHERE
+			viewer_id = ? AND media_id = ?
+		`,
+		access.Viewer(r),
+		r.PathValue("mediaId"),
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to mark as unwatched. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Marks every episode of a show, or of one of its seasons, as watched.
+//
+// # Specifications:
+//   - Method        : PUT
+//   - Endpoint      : /shows/{id}/watched
+//   - Auth?         : True
+//
+// # HTTP request path parameters:
+//   - id            : REQUIRED. UUID of the show.
+//
+// # HTTP request multipart form:
+//   - season_number : OPTIONAL. Only mark the episodes of this season.
+//
+// # HTTP response JSON contents:
+//   - status_code   : HTTP status code.
+//   - data          : Number of episodes marked.
+func MarkShowWatched(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+
+	condition, arguments, err := showEpisodes(database, r)
+	if err != nil {
+		return err
+	}
+
+	result, err := database.Exec(
+		fmt.Sprintf(`
+			INSERT INTO
+				progress (viewer_id, media_id, media_type, position, duration, watched, last_watched_date)
+			SELECT
+				?, id, 'episode', 0, 0, 1, ?
+			FROM
+				episodes
+			WHERE
+				%s
+			ON CONFLICT (viewer_id, media_id) DO UPDATE SET
+				position = 0, watched = 1, last_watched_date = excluded.last_watched_date
+			`,
+			condition,
+		),
+		append([]any{access.Viewer(r), time.Now().Format("2006-01-02 15:04:05")}, arguments...)...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to mark as watched. %v", err))
+		return err
+	}
+
+	marked, _ := result.RowsAffected()
+
+	responses.Status{
+		Status: 200,
+		Data:   marked,
+	}.ToClient(w)
+	return nil
+}
+
+// Marks every episode of a show, or of one of its seasons, as unwatched.
+//
+// # Specifications:
+//   - Method        : DELETE
+//   - Endpoint      : /shows/{id}/watched
+//   - Auth?         : True
+//
+// # HTTP request path parameters:
+//   - id            : REQUIRED. UUID of the show.
+//
+// # HTTP request query parameters:
+//   - season_number : OPTIONAL. Only mark the episodes of this season.
+//
+// # HTTP response JSON contents:
+//   - status_code   : HTTP status code.
+//   - data          : Number of episodes marked.
+func MarkShowUnwatched(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+
+	condition, arguments, err := showEpisodes(database, r)
+	if err != nil {
+		return err
+	}
+
+	result, err := database.Exec(
+		fmt.Sprintf(`
+			DELETE FROM
+				progress
+			WHERE
+				viewer_id = ? AND media_id IN (SELECT id FROM episodes WHERE %s)
+			`,
+			condition,
+		),
+		append([]any{access.Viewer(r)}, arguments...)...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to mark as unwatched. %v", err))
+		return err
+	}
+
+	marked, _ := result.RowsAffected()
+
+	responses.Status{
+		Status: 200,
+		Data:   marked,
+	}.ToClient(w)
+	return nil
+}
+
+// Builds an SQL condition on the episodes table that matches the episodes of
+// the show in the path that the request may watch, limited to the season_number
+// form value when one is given. Returns the problem to send to the client if
+// the show does not exist or the season is not a number.
+func showEpisodes(database *sql.DB, r *http.Request) (string, []any, error) {
+	var id string = r.PathValue("id")
+	var found int
+
+	showCondition, showArguments := access.RatingCondition(r, "content_rating_id")
+
+	if err := database.QueryRow(
+		"SELECT 1 FROM shows WHERE id = ? AND "+showCondition,
+		append([]any{id}, showArguments...)...,
+	).Scan(&found); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, problems.New(problems.NotFound, "No show could be found with the given id.")
+		}
+
+		return "", nil, err
+	}
+
+	episodeCondition, episodeArguments := access.EpisodeCondition(r)
+	condition := "parent_id = ? AND " + episodeCondition
+	arguments := append([]any{id}, episodeArguments...)
+
+	if season := r.FormValue("season_number"); season != "" {
+		number, err := strconv.Atoi(season)
+		if err != nil {
+			return "", nil, problems.New(problems.InvalidRequest, "The season_number value must be an integer.")
+		}
+
+		condition += " AND season_number = ?"
+		arguments = append(arguments, number)
+	}
+
+	return condition, arguments, nil
+}
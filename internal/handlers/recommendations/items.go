@@ -0,0 +1,73 @@
+package recommendations
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/recommend"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Turns ranked items into recommendations, skipping items that are hidden or
+// that the request may not watch, until limit recommendations are found.
+func recommendations(database *sql.DB, ranked []recommend.Neighbor, limit int, r *http.Request) ([]types.Recommendation, error) {
+	var list []types.Recommendation = []types.Recommendation{}
+
+	condition, arguments := access.RatingCondition(r, "content_rating_id")
+
+	for _, item := range ranked {
+		var title, description string
+
+		if len(list) == limit {
+			break
+		}
+
+		table := "movies"
+		if item.MediaType == "show" {
+			table = "shows"
+		}
+
+		if err := database.QueryRow(
+			fmt.Sprintf(`
+				SELECT
+					title, description
+				FROM
+					%s
+				WHERE
+					id = ? AND hidden = 0 AND %s
+				`,
+				table,
+				condition,
+			),
+			append([]any{item.Id}, arguments...)...,
+		).Scan(
+			&title,
+			&description,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		recommendation := types.Recommendation{MediaType: item.MediaType, Score: item.Score}
+		cover := map[string]any{
+			"exists": true,
+			"url":    "/api/v1/" + table + "/" + item.Id + "/cover",
+		}
+
+		if item.MediaType == "show" {
+			recommendation.Show = &types.Show{Id: item.Id, Title: title, Description: description, Cover: cover}
+		} else {
+			recommendation.Movie = &types.Movie{Id: item.Id, Title: title, Description: description, Cover: cover}
+		}
+
+		list = append(list, recommendation)
+	}
+
+	return list, nil
+}
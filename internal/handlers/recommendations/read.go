@@ -0,0 +1,127 @@
+package recommendations
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/recommend"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Gets shows and movies the viewer has not watched yet, best match first.
+// Matches come from what the viewer watched and rated. Viewers with little
+// history are recommended what most viewers watched. Unlike collections, the
+// recommendations are not paged, since they are ranked anew every time the
+// engine refreshes, which would leave cursors into them pointing nowhere.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /recommendations
+//   - Auth?       : True
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of items to return, 20 by default and at most 100.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Recommendations, each returning media_type, score, show or movie.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  engine *recommend.Engine,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var limit int = functions.Limit(r.URL.Query().Get("limit"), 20, 100)
+
+	ranked, err := engine.Recommend(access.Viewer(r))
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to rank recommendations. %v", err))
+		return err
+	}
+
+	list, err := recommendations(database, ranked, limit, r)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   list,
+	}.ToClient(w)
+	return nil
+}
+
+// Gets the shows and movies most similar to a movie, most similar first.
+// Similarity comes from viewers watching both, shared tags and shared people.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /movies/{id}/similar
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the movie.
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of items to return, 20 by default and at most 50.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Recommendations, each returning media_type, score, show or movie.
+func Similar(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  engine *recommend.Engine,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+	var limit int = functions.Limit(r.URL.Query().Get("limit"), 20, 50)
+	var found int
+
+	condition, arguments := access.RatingCondition(r, "content_rating_id")
+
+	if err := database.QueryRow(
+		fmt.Sprintf(`
+			SELECT
+				1
+			FROM
+				movies
+			WHERE
+				id = ? AND %s
+			`,
+			condition,
+		),
+		append([]any{id}, arguments...)...,
+	).Scan(&found); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return problems.New(problems.NotFound, "No movie could be found with the given id.")
+		}
+
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	// Ask for every neighbor, as some may turn out to be hidden from the request.
+	list, err := recommendations(database, engine.Similar(id, 50), limit, r)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   list,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,40 @@
+package reviews
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+)
+
+// Returns whether the id belongs to a "movie", "show" or "episode" that the
+// request may see. Returns the problem to send to the client otherwise.
+func media(database *sql.DB, id string, r *http.Request) (string, error) {
+	var found int
+
+	condition, arguments := access.RatingCondition(r, "content_rating_id")
+	episodeCondition, episodeArguments := access.EpisodeCondition(r)
+
+	for _, candidate := range []struct {
+		mediaType string
+		statement string
+		arguments []any
+	}{
+		{"movie", "SELECT 1 FROM movies WHERE id = ? AND " + condition, arguments},
+		{"show", "SELECT 1 FROM shows WHERE id = ? AND " + condition, arguments},
+		{"episode", "SELECT 1 FROM episodes WHERE id = ? AND " + episodeCondition, episodeArguments},
+	} {
+		err := database.QueryRow(candidate.statement, append([]any{id}, candidate.arguments...)...).Scan(&found)
+		if err == nil {
+			return candidate.mediaType, nil
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", err
+		}
+	}
+
+	return "", problems.New(problems.NotFound, "No movie, show or episode could be found with the given id.")
+}
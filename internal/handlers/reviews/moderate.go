@@ -0,0 +1,107 @@
+package reviews
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Hides or shows again the review of another user. The score still counts
+// towards the average while the review is hidden. Requires the
+// moderate_reviews permission.
+//
+// # Specifications:
+//   - Method      : PUT, DELETE
+//   - Endpoint    : /reviews/{id}/hidden
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the review.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func SetHidden(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  hidden bool,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	result, err := database.Exec(`
+		UPDATE
+			user_ratings
+		SET
+			hidden = ?
+		WHERE
+			id = ?
+		`,
+		hidden,
+		id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to moderate review. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No review could be found with the given id.")
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Removes the review of another user along with its score. Requires the
+// moderate_reviews permission.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /reviews/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the review.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Delete(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	result, err := database.Exec(`
+		DELETE FROM
+			user_ratings
+		WHERE
+			id = ?
+		`,
+		id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete review. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No review could be found with the given id.")
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,175 @@
+package reviews
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Scores that a thumbs up or down stands for.
+var thumbScores = map[string]int{
+	"up":   10,
+	"down": 1,
+}
+
+// Rates a movie, show or episode for the viewer, optionally with a short review.
+// Rating it again replaces the earlier score and review. Reviews hidden by a
+// moderator stay hidden when they are edited.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /ratings/{mediaId}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - mediaId     : REQUIRED. UUID of the movie, show or episode.
+//
+// # HTTP request multipart form:
+//   - score       : OPTIONAL. Score from 1 to 10. Required without thumb.
+//   - thumb       : OPTIONAL. "up" or "down", stored as a score of 10 or 1. Required without score.
+//   - review      : OPTIONAL. Review of at most 2000 bytes.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The stored review.
+func Rate(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("mediaId")
+	var functionId string = access.RequestId(r)
+	var now string = time.Now().Format("2006-01-02 15:04:05")
+	var review types.Review = types.Review{
+		MediaId: id,
+		Review:  functions.Sanitize(r.FormValue("review")),
+	}
+
+	if thumb := r.FormValue("thumb"); thumb != "" {
+		review.Score = thumbScores[thumb]
+	} else {
+		review.Score, _ = strconv.Atoi(r.FormValue("score"))
+	}
+
+	if review.Score < 1 || review.Score > 10 {
+		return problems.New(problems.InvalidRequest, "Either a score from 1 to 10 or a thumb of \"up\" or \"down\" is required.")
+	}
+
+	if len(review.Review) > 2000 {
+		return problems.New(problems.InvalidRequest, "The review value was larger than 2000 bytes. In UTF-8 encoding, English characters are 1 byte each.")
+	}
+
+	mediaType, err := media(database, id, r)
+	if err != nil {
+		return err
+	}
+
+	if err := database.QueryRow(`
+		INSERT INTO
+			user_ratings (id, viewer_id, media_id, media_type, score, review, hidden, created_date, last_modified)
+		VALUES
+			(?, ?, ?, ?, ?, ?, 0, ?, ?)
+		ON CONFLICT (viewer_id, media_id) DO UPDATE SET
+			score = excluded.score,
+			review = excluded.review,
+			last_modified = excluded.last_modified
+		RETURNING
+			id, media_type, hidden, created_date, last_modified
+		`,
+		uuid.NewString(),
+		access.Viewer(r),
+		id,
+		mediaType,
+		review.Score,
+		review.Review,
+		now,
+		now,
+	).Scan(
+		&review.Id,
+		&review.MediaType,
+		&review.Hidden,
+		&review.CreatedDate,
+		&review.LastModified,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to store rating. %v", err))
+		return err
+	}
+
+	review.Author = author(r)
+
+	responses.Status{
+		Status: 200,
+		Data:   review,
+	}.ToClient(w)
+	return nil
+}
+
+// Removes the score and review the viewer gave a movie, show or episode.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /ratings/{mediaId}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - mediaId     : REQUIRED. UUID of the movie, show or episode.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Unrate(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("mediaId")
+	var functionId string = access.RequestId(r)
+
+	result, err := database.Exec(`
+		DELETE FROM
+			user_ratings
+		WHERE
+			viewer_id = ? AND media_id = ?
+		`,
+		access.Viewer(r),
+		id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to remove rating. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No rating could be found for the given id.")
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Returns the name reviews of the viewer are shown under: the name of the
+// selected profile, or the username when no profile is selected.
+func author(r *http.Request) string {
+	if profile := access.Profile(r); profile != nil {
+		return profile.Name
+	}
+
+	if user := access.User(r); user != nil {
+		return user.Username
+	}
+
+	return ""
+}
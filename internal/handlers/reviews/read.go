@@ -0,0 +1,137 @@
+package reviews
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets the average user score of a movie, show or episode along with its
+// reviews, most recently written first. Reviews hidden by a moderator are only
+// returned to moderators.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /ratings/{mediaId}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - mediaId     : REQUIRED. UUID of the movie, show or episode.
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of reviews per page, 20 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Returns rating, the average, count and score of the viewer, and reviews.
+//   - total       : Number of reviews across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("mediaId")
+	var functionId string = access.RequestId(r)
+	var moderator bool = access.Allowed(r, access.ModerateReviews)
+	var reviews []types.Review = []types.Review{}
+
+	if _, err := media(database, id, r); err != nil {
+		return err
+	}
+
+	ratings, err := queries.RatingsFor(database, access.Viewer(r), []string{id})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to retrieve user ratings. %v", err))
+		return err
+	}
+
+	page, err := queries.NewPage(r, 20, []queries.SortKey{{Expression: "last_modified", Descending: true}, {Expression: "id"}})
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+	}
+
+	condition := "media_id = ? AND review != '' AND (hidden = 0 OR ?)"
+
+	total, err := queries.Count(database, "user_ratings", condition, []any{id, moderator})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	seek, seekArguments := page.Condition()
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				id, media_id, media_type, score, review, hidden, created_date, last_modified,
+				COALESCE(
+					(SELECT name FROM profiles WHERE profiles.id = user_ratings.viewer_id),
+					(SELECT username FROM users WHERE users.id = user_ratings.viewer_id),
+					''
+				)
+				%s
+			FROM
+				user_ratings
+			WHERE
+				%s AND %s
+			%s
+			`,
+			page.Columns(),
+			condition,
+			seek,
+			page.Order(),
+		),
+		append([]any{id, moderator}, seekArguments...)...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var review types.Review
+
+		if err := rows.Scan(append([]any{
+			&review.Id,
+			&review.MediaId,
+			&review.MediaType,
+			&review.Score,
+			&review.Review,
+			&review.Hidden,
+			&review.CreatedDate,
+			&review.LastModified,
+			&review.Author,
+		}, page.Scan()...)...); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to scan review. %v", err))
+			return err
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	reviews = queries.Trim(page, reviews)
+
+	responses.Paged(
+		r,
+		map[string]any{
+			"rating":  ratings[id],
+			"reviews": reviews,
+		},
+		total,
+		page.Next(),
+		page.Prev(),
+	).ToClient(w)
+	return nil
+}
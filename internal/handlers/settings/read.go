@@ -0,0 +1,62 @@
+package settings
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Returns every server setting. Requires the manage_server permission.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /settings
+//   - Auth?       : True
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Settings, keyed by name.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var settings map[string]string = map[string]string{}
+
+	rows, err := database.Query(`
+		SELECT
+			key, value
+		FROM
+			settings
+		`,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+
+		if err := rows.Scan(&key, &value); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to scan setting. %v", err))
+			continue
+		}
+
+		settings[key] = value
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   settings,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,103 @@
+package settings
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Checks for the values of each setting, along with the detail returned to the
+// client when a value is rejected.
+var checks = map[string]struct {
+	valid  func(value string) bool
+	detail string
+}{
+	"require_two_factor": {
+		valid:  func(value string) bool { return value == "true" || value == "false" },
+		detail: "The require_two_factor value must be \"true\" or \"false\".",
+	},
+	"watched_threshold": {
+		valid: func(value string) bool {
+			number, err := strconv.ParseFloat(value, 64)
+			return err == nil && number > 0 && number <= 100
+		},
+		detail: "The watched_threshold value must be a percentage above 0 and at most 100.",
+	},
+	"recommendation_interval": {
+		valid: func(value string) bool {
+			number, err := strconv.Atoi(value)
+			return err == nil && number >= 1
+		},
+		detail: "The recommendation_interval value must be a whole number of minutes of at least 1.",
+	},
+}
+
+// Changes server settings. Only the settings present in the form are changed.
+// Requires the manage_server permission.
+//
+// # Specifications:
+//   - Method                  : PUT
+//   - Endpoint                : /settings
+//   - Auth?                   : True
+//
+// # HTTP request multipart form:
+//   - require_two_factor      : OPTIONAL. "true" to require admins and uploaders to
+//     enable two-factor authentication before using anything but /auth routes.
+//   - watched_threshold       : OPTIONAL. Percentage of a video that must be played
+//     for it to be marked as watched.
+//   - recommendation_interval : OPTIONAL. Minutes between recomputing recommendations.
+//
+// # HTTP response JSON contents:
+//   - status_code             : HTTP status code.
+func Update(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var changes map[string]string = map[string]string{}
+
+	for key, check := range checks {
+		value := r.FormValue(key)
+		if value == "" {
+			continue
+		}
+
+		if !check.valid(value) {
+			return problems.New(problems.InvalidRequest, check.detail)
+		}
+
+		changes[key] = value
+	}
+
+	for key, value := range changes {
+		if _, err := database.Exec(`
+			UPDATE
+				settings
+			SET
+				value = ?
+			WHERE
+				key = ?
+			`,
+			value,
+			key,
+		); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to update setting. %v", err))
+			return err
+		}
+
+		log.Info(functionId, fmt.Sprintf("Changed setting %s to %s", key, value))
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
 	"time"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
 	"github.com/andrewdotjs/watchify-server/internal/types"
 	"github.com/andrewdotjs/watchify-server/internal/upload"
@@ -18,37 +21,32 @@ import (
 // storage folder.
 //
 // # Specifications:
-//   - Method      : POST
-//   - Endpoint    : /series
-//   - Auth?       : False
+//   - Method        : POST
+//   - Endpoint      : /shows
+//   - Auth?         : True
 //
 // # HTTP request multipart form:
-//   - video-files : REQUIRED. Uploaded video files.
-//   - name        : REQUIRED. Name of the soon-to-be uploaded series.
-//   - description : REQUIRED. Description of the series.
+//   - videos        : REQUIRED. Uploaded video files.
+//   - cover         : REQUIRED. Uploaded cover, should be a 400x600 jpg.
+//   - title         : OPTIONAL. Title of the series.
+//   - description   : OPTIONAL. Description of the series.
+//   - season_number : OPTIONAL. Season of the uploaded episodes, 1 by default.
 //
 // # HTTP response JSON contents:
-//   - status_code : HTTP status code.
-//   - message     : If error, Message detailing the error.
-//   - data        : Series id, title
+//   - status_code   : HTTP status code.
+//   - data          : Series id, title
 func Create(
   w http.ResponseWriter,
   r *http.Request,
   database *sql.DB,
   appDirectory *string,
   log *logger.Logger,
-) {
-  var functionId string = uuid.NewString()
+) error {
+  var functionId string = access.RequestId(r)
 
 	if err := r.ParseMultipartForm(1 << 40); err != nil { // Error handling if form data exceeds 1TB
 		log.Error(functionId, fmt.Sprintf("%v", err))
-		responses.Error{
-			Type:   "null",
-			Title:  "Incomplete request",
-			Status: 400,
-			Detail: "The upload form exceeded 1TB",
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "The upload form exceeded 1TB")
 	}
 
 	uploadDirectory := path.Join(*appDirectory, "storage", "videos")
@@ -58,49 +56,12 @@ func Create(
 	uploadedCover := r.MultipartForm.File["cover"]
 	if len(uploadedCover) == 0 {
 	  log.Error(functionId, "Received no cover in request")
-		responses.Error{
-			Type:   "null",
-			Title:  "Bad request",
-			Status: 400,
-			Detail: "No uploaded cover present in form",
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "No uploaded cover present in form")
 	}
-	//if err != nil {
-	//	var response responses.Error
-	//
-	//	switch {
-	//	case errors.Is(err, http.ErrMissingFile):
-	//		log.Print("Received no cover in request.")
-	//		response = responses.Error{
-	//			Type:   "null",
-	//			Title:  "Bad request",
-	//			Status: 400,
-	//			Detail: "No uploaded cover present in form",
-	//		}
-	//	default:
-	//		log.Print(err)
-	//		response = responses.Error{
-	//			Type:   "null",
-	//			Title:  "Unaccounted Error",
-	//			Status: 500,
-	//			Detail: fmt.Sprintf("%v", err),
-	//		}
-	//	}
-	//
-	//	response.ToClient(w)
-	//	return
-	//}
 
 	if len(uploadedVideos) == 0 {
 		log.Error(functionId, "Received no videos in request")
-		responses.Error{
-			Type:   "null",
-			Title:  "Bad request",
-			Status: 400,
-			Detail: "No uploaded videos present in form",
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "No uploaded videos present in form")
 	}
 
 	show := types.Show{
@@ -114,6 +75,7 @@ func Create(
 	// Handle upload for every file that was passed in the form.
 	for index, uploadedFile := range uploadedVideos {
 		video := types.Episode{ParentId: show.Id}
+		video.SeasonNumber, _ = strconv.Atoi(r.FormValue("season_number"))
 		upload.Episode(uploadedFile, &video, database, &uploadDirectory, log, &functionId)
 		show.EpisodeCount = index + 1
 	}
@@ -132,7 +94,7 @@ func Create(
 
 	if _, err := database.Exec(`
    	INSERT INTO
-      shows
+      shows (id, title, description, episode_count, hidden, upload_date, last_modified)
    	VALUES
 			(?, ?, ?, ?, ?, ?, ?)
     `,
@@ -151,4 +113,5 @@ func Create(
 		Status: 201,
 		Data:   show,
 	}.ToClient(w)
+	return nil
 }
@@ -8,220 +8,70 @@ import (
 	"os"
 	"path"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
 )
 
-// Deletes a series, its episodes, and its cover from the database and storage folders.
+// Deletes a series, its episodes, and its cover from the database and storage folders,
+// along with everything attached to them, such as credits, tags and ratings.
 //
 // # Specifications:
 //   - Method      : DELETE
 //   - Endpoint    : /shows/{id}
-//   - Auth?       : False
+//   - Auth?       : True
 //
 // # HTTP request path parameters:
 //   - id          : REQUIRED. UUID of the series.
 //
 // # HTTP response JSON contents:
 //   - status_code : HTTP status code.
-//   - message     : If error, Message detailing the error.
 func Delete(
   w http.ResponseWriter,
   r *http.Request,
   database *sql.DB,
   appDirectory *string,
   log *logger.Logger,
-) {
-	var videoStorageDirectory string = path.Join(*appDirectory, "storage", "videos")
+) error {
 	var id string = r.PathValue("id")
-	var coverFileName string = ""
-	var functionId string = ""
+	var functionId string = access.RequestId(r)
 
 	if id == "" {
-		responses.Error{
-			Type:     "null",
-			Title:    "Invalid Request",
-			Status:   400,
-			Detail:   "Id was not present in the URL.",
-			Instance: r.URL.Path,
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "Id was not present in the URL.")
 	}
 
-	// Stage 1, find all videos that are in the to-be-deleted series and delete them.
-	rows, err := database.Query(
-		`
-  	SELECT
-			file_name
-  	FROM
-			episodes
-  	WHERE
-			parent_id=?
-    `,
-		id,
-	)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		var response responses.Error
-
-		switch {
-		default:
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-			log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-		}
-
-		response.ToClient(w)
-		return
-	}
-
-	defer rows.Close()
-
-	for rows.Next() {
-		var videoFileName string
-
-		if err := rows.Scan(&videoFileName); err != nil {
-			defer database.Close()
-			log.Error(functionId, fmt.Sprintf("%v", err))
-		}
-
-		if err := os.Remove(path.Join(videoStorageDirectory, videoFileName)); err != nil {
-			var response responses.Error
-
-			switch {
-			case errors.Is(err, os.ErrNotExist):
-				response = responses.Error{
-					Type:     "null",
-					Title:    "File system and database out-of-sync.",
-					Status:   500,
-					Detail:   "Attempted to delete a non-existant video file that exists in the database.",
-					Instance: r.URL.Path,
-				}
-			default:
-				response = responses.Error{
-					Type:     "null",
-					Title:    "An unknown error has occurred.",
-					Status:   500,
-					Detail:   "Sorry, but this error hasn't been properly logged yet.",
-					Instance: r.URL.Path,
-				}
-				log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			}
-
-			response.ToClient(w)
-			return
-		}
+	transaction, err := database.Begin()
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Error deleting series information from the database.")
 	}
 
-	if _, err := database.Exec(`
-			DELETE FROM
-				episodes
-			WHERE
-				parent_id=?
-		`,
-		id,
-	); err != nil {
-		var response responses.Error
-
-		switch {
-		default:
-			log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-		}
+	defer transaction.Rollback()
 
-		response.ToClient(w)
-		return
+	files, err := queries.DeleteMedia(transaction, "shows", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return problems.New(problems.NotFound, "No series could be found with the given id.")
 	}
 
-	// Stage 2, delete the cover from the database.
-	if _, err := database.Exec(
-		`
-	  DELETE FROM
-			covers
-	  WHERE
-			parent_id=?
-  	`,
-		id,
-	); err != nil {
-		var response responses.Error
-
-		switch {
-		default:
-			log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-		}
-
-		response.ToClient(w)
-		return
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Error deleting series information from the database.")
 	}
 
-	// Stage 3, delete the cover from the storage.
-	if err := os.Remove(
-		path.Join(*appDirectory, "storage", "covers", coverFileName),
-	); err != nil && !errors.Is(err, os.ErrNotExist) {
-		var response responses.Error
-
-		switch {
-		default:
-			log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-		}
-
-		response.ToClient(w)
-		return
+	if err := transaction.Commit(); err != nil {
+		return problems.Wrap(problems.Internal, err, "Error deleting series information from the database.")
 	}
 
-	// Stage 4, delete the series itself from the database.
-	if _, err := database.Exec(
-		`
-  	DELETE FROM
-      shows
-  	WHERE
-			id=?
-  	`,
-		id,
-	); err != nil {
-		var response responses.Error
-
-		switch {
-		default:
-			log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
+	// The series is gone either way, so files that can't be removed are only
+	// worth logging.
+	for _, file := range files {
+		if err := os.Remove(path.Join(*appDirectory, "storage", file)); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to remove %s of the deleted series. %v", file, err))
 		}
-
-		response.ToClient(w)
-		return
 	}
 
 	responses.Status{
 		Status: 200,
 	}.ToClient(w)
+	return nil
 }
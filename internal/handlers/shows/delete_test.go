@@ -0,0 +1,45 @@
+package shows_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestDelete(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	show := admin.CreateShow("Andor")
+
+	var video, cover string
+	if err := server.DB.QueryRow("SELECT file_name FROM episodes WHERE parent_id = ?", show).Scan(&video); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.DB.QueryRow("SELECT file_name FROM covers WHERE parent_id = ?", show).Scan(&cover); err != nil {
+		t.Fatal(err)
+	}
+
+	admin.Send("DELETE", "/api/v1/shows/"+show, "If-Match", "*").Expect(200)
+
+	for _, file := range []string{path.Join("videos", video), path.Join("covers", cover)} {
+		if _, err := os.Stat(path.Join(server.AppDirectory, "storage", file)); !os.IsNotExist(err) {
+			t.Errorf("%s is still stored: %v", file, err)
+		}
+	}
+
+	for _, table := range []string{"shows WHERE id = ?", "episodes WHERE parent_id = ?", "covers WHERE parent_id = ?"} {
+		var count int
+		if err := server.DB.QueryRow("SELECT COUNT(*) FROM "+table, show).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+
+		if count != 0 {
+			t.Errorf("%d rows of %s are left", count, table)
+		}
+	}
+
+	admin.Send("DELETE", "/api/v1/shows/"+show, "If-Match", "*").Expect(404)
+}
@@ -0,0 +1,111 @@
+package shows
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/patch"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Changes the title or description of a series, and returns the changed
+// series. Unlike Update, members left out of the patch are left as they are.
+//
+// # Specifications:
+//   - Method      : PATCH
+//   - Endpoint    : /shows/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the series.
+//
+// # HTTP request body:
+//   - A JSON Merge Patch (application/merge-patch+json), a JSON Patch
+//     (application/json-patch+json) or a form of title and description.
+//     Removing the description clears it, while the title can't be removed.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The changed series, as returned by Read.
+func Patch(
+  w http.ResponseWriter,
+  r *http.Request,
+  db *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var show types.Show = types.Show{Id: id}
+	var functionId string = access.RequestId(r)
+
+	condition, conditionArguments := access.RatingCondition(r, "content_rating_id")
+
+	if err := db.QueryRow(
+		fmt.Sprintf(`
+			SELECT
+				title, description
+			FROM
+				shows
+			WHERE
+				id = ? AND %s
+			`,
+			condition,
+		),
+		append([]any{id}, conditionArguments...)...,
+	).Scan(
+		&show.Title,
+		&show.Description,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return problems.New(problems.NotFound, "No series could be found with the given id.")
+		}
+
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	document, err := patch.Apply(r, map[string]any{
+		"title":       show.Title,
+		"description": show.Description,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := patch.Only(document, "title", "description"); err != nil {
+		return err
+	}
+
+	if show.Title, err = patch.Text(document, "title", 50, true); err != nil {
+		return err
+	}
+
+	if show.Description, err = patch.Text(document, "description", 1000, false); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(
+		`
+			UPDATE
+				shows
+			SET
+				title = ?, description = ?, last_modified = ?
+			WHERE
+				id = ?
+		`,
+		show.Title,
+		show.Description,
+		time.Now().Format("01-02-2006 15:04:05"),
+		show.Id,
+	); err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to change the series.")
+	}
+
+	log.Info(functionId, fmt.Sprintf("Patched series with ID %s", id))
+	return Read(w, r, db, log)
+}
@@ -2,178 +2,285 @@ package shows
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
 	"github.com/andrewdotjs/watchify-server/internal/types"
-	"github.com/google/uuid"
 )
 
-// Gets and returns an array of series stored in the database.
+// Gets and returns a series, or a page of the series stored in the database.
 //
 // # Specifications:
 //   - Method      : GET
-//   - Endpoint    : /series/{id}
-//   - Auth?       : False
+//   - Endpoint    : /shows/{id}, /shows
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : OPTIONAL. UUID of the series, the query parameters only apply without it.
 //
 // # HTTP request query parameters:
-//   - series_id   : OPTIONAL. UUID of the series.
+//   - tags        : OPTIONAL. Comma separated tag ids or names to filter by.
+//   - match       : OPTIONAL. "all" to require every tag, "any" (default) otherwise.
+//   - orderedBy   : OPTIONAL. "upload_date" for newest first, "rating" for best rated first, title otherwise.
+//   - filter      : OPTIONAL. Filter expression, such as `year>=2000 and tag:anime`.
+//   - sort        : OPTIONAL. Comma separated fields to sort by, descending if prefixed
+//     with a minus, such as `-rating,title`. Takes precedence over orderedBy.
+//   - limit       : OPTIONAL. Number of series per page, 15 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
 //
 // # HTTP response JSON contents:
 //   - status_code : HTTP status code.
-//   - message     : If error, Message detailing the error.
 //   - data        : Series contents, each returning id, episode count, title, description.
+//   - total       : Number of series across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
 func Read(
   w http.ResponseWriter,
   r *http.Request,
   database *sql.DB,
   log *logger.Logger,
-) {
+) error {
 	var id string = r.PathValue("id")
 	var orderedBy string = r.URL.Query().Get("orderedBy")
-	var orderedByQuery string = ""
-	var show types.Show = types.Show{}
-	var functionId string = uuid.NewString()
+	var tagFilter []string = functions.SplitList(r.URL.Query().Get("tags"))
+	var whereQuery string = ""
+	var arguments []any
+	var functionId string = access.RequestId(r)
 
 	// Return all series if no ID.
 	if id == "" {
-		var shows []types.Show
-
-		if orderedBy != "" {
-			switch orderedBy {
-			case "upload_date":
-				orderedByQuery = "ORDER BY upload_date DESC"
-			default:
-				orderedByQuery = ""
+		var order []queries.SortKey = []queries.SortKey{{Expression: "title"}, {Expression: "id"}}
+
+		switch orderedBy {
+		case "upload_date":
+			order = []queries.SortKey{{Expression: "upload_date", Descending: true}, {Expression: "id"}}
+		case "rating":
+			order = []queries.SortKey{{Expression: queries.AverageRating("shows"), Descending: true}, {Expression: "id"}}
+		}
+
+		if sort := r.URL.Query().Get("sort"); sort != "" {
+			keys, err := queries.Sort(sort, queries.ShowFields)
+			if err != nil {
+				return queries.Malformed(err)
 			}
+
+			order = keys
 		}
 
-		rows, err := database.Query(
-			fmt.Sprintf(`
-					SELECT
-						id, title, description, episode_count, upload_date, last_modified
-					FROM
-					  shows
-					%v
-					LIMIT
-						15
-				`,
-				orderedByQuery,
-			),
-		)
+		filter, filterArguments, err := queries.Filter(r.URL.Query().Get("filter"), queries.ShowFields)
+		if err != nil {
+			return queries.Malformed(err)
+		}
 
+		page, err := queries.NewPage(r, 15, order)
 		if err != nil {
-			switch {
-			case errors.Is(err, sql.ErrNoRows):
-				responses.Status{
-					Status: 200,
-					Data:   nil,
-				}.ToClient(w)
-			default:
-				responses.Error{
-					Type:     "null",
-					Title:    "Unknown Error",
-					Status:   500,
-					Detail:   fmt.Sprintf("%v", err),
-					Instance: r.URL.Path,
-				}.ToClient(w)
-			}
+			return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+		}
+
+		condition, conditionArguments := access.RatingCondition(r, "content_rating_id")
+		whereQuery = condition
+		arguments = append(arguments, conditionArguments...)
 
-			return
+		if len(tagFilter) > 0 {
+			subquery, subqueryArguments := queries.TagFilter(tagFilter, r.URL.Query().Get("match") == "all")
+			whereQuery += fmt.Sprintf(" AND id IN (%s)", subquery)
+			arguments = append(arguments, subqueryArguments...)
 		}
 
-		defer rows.Close()
-		for rows.Next() {
-			var show types.Show
-
-			if err := rows.Scan(
-				&show.Id,
-				&show.Title,
-				&show.Description,
-				&show.EpisodeCount,
-				&show.UploadDate,
-				&show.LastModified,
-			); err != nil {
-				defer database.Close()
-				log.Error(functionId, fmt.Sprintf("%v", err))
-			}
+		whereQuery += " AND " + filter
+		arguments = append(arguments, filterArguments...)
 
-			show.Episodes = map[string]any{
-				"count": show.EpisodeCount,
-				"url":   ("/api/v1/series/" + show.Id + "/episodes"),
-			}
+		total, err := queries.Count(database, "shows", whereQuery, arguments)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
 
-			show.Cover = map[string]any{
-				"exists": true,
-				"url":    ("/api/v1/series/" + show.Id + "/cover"),
-			}
+		shows, err := queries.ShowPage(database, whereQuery, arguments, page)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		if err := embedTags(r, database, shows, log, &functionId); err != nil {
+			return err
+		}
+
+		if err := embedContentRatings(r, database, shows, log, &functionId); err != nil {
+			return err
+		}
 
-			shows = append(shows, show)
+		if err := embedLists(r, database, shows, log, &functionId); err != nil {
+			return err
 		}
 
-		responses.Status{
-			Status: 200,
-			Data:   shows,
-		}.ToClient(w)
-		return
-	}
-
-	if err := database.QueryRow(
-		`
-		SELECT
-			id, title, description, episode_count, upload_date, last_modified
-		FROM
-		  shows
-		WHERE
-			id=?
-		`,
-		id,
-	).Scan(
-		&show.Id,
-		&show.Title,
-		&show.Description,
-		&show.EpisodeCount,
-		&show.UploadDate,
-		&show.LastModified,
-	); err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			responses.Error{
-				Type:     "null",
-				Title:    "Data not found",
-				Status:   400,
-				Detail:   "No series could be found with the given id.",
-				Instance: r.URL.Path,
-			}.ToClient(w)
-		default:
-			responses.Error{
-				Type:     "null",
-				Title:    "Unknown Error",
-				Status:   500,
-				Detail:   fmt.Sprintf("%v", err),
-				Instance: r.URL.Path,
-			}.ToClient(w)
+		if err := embedRatings(r, database, shows, log, &functionId); err != nil {
+			return err
 		}
 
-		return
+		responses.Paged(r, shows, total, page.Next(), page.Prev()).ToClient(w)
+		return nil
+	}
+
+	condition, conditionArguments := access.RatingCondition(r, "content_rating_id")
+
+	shows, err := queries.Shows(database, "id = ? AND "+condition, append([]any{id}, conditionArguments...), "")
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if len(shows) == 0 {
+		return problems.New(problems.NotFound, "No series could be found with the given id.")
+	}
+
+	if err := embedTags(r, database, shows, log, &functionId); err != nil {
+		return err
+	}
+
+	if err := embedContentRatings(r, database, shows, log, &functionId); err != nil {
+		return err
 	}
 
-	// Assemble
-	show.Episodes = map[string]any{
-		"count": show.EpisodeCount,
-		"url":   ("/api/v1/series/" + show.Id + "/episodes"),
+	if err := embedLists(r, database, shows, log, &functionId); err != nil {
+		return err
 	}
 
-	show.Cover = map[string]any{
-		"exists": true,
-		"url":    ("/api/v1/series/" + show.Id + "/cover"),
+	if err := embedRatings(r, database, shows, log, &functionId); err != nil {
+		return err
 	}
 
+	credits, err := queries.CreditsFor(database, []string{id})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to retrieve credits. %v", err))
+		return err
+	}
+
+	shows[0].Credits = credits[id]
+
 	responses.Status{
 		Status: 200,
-		Data:   show,
+		Data:   shows[0],
 	}.ToClient(w)
+	return nil
+}
+
+// Attaches the tags of every given show to it. Returns the error if the tags
+// could not be retrieved.
+func embedTags(
+  r *http.Request,
+  database *sql.DB,
+  shows []types.Show,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	var ids []string
+
+	for _, show := range shows {
+		ids = append(ids, show.Id)
+	}
+
+	tags, err := queries.TagsFor(database, ids)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve tags. %v", err))
+		return err
+	}
+
+	for index := range shows {
+		shows[index].Tags = tags[shows[index].Id]
+	}
+
+	return nil
+}
+
+// Attaches the content rating of every given show to it. Returns the error if
+// the ratings could not be retrieved.
+func embedContentRatings(
+  r *http.Request,
+  database *sql.DB,
+  shows []types.Show,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	ratings, err := queries.ContentRatings(database)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve content ratings. %v", err))
+		return err
+	}
+
+	for index := range shows {
+		shows[index].ContentRating = ratings[shows[index].ContentRatingId]
+	}
+
+	return nil
+}
+
+// Attaches whether every given show is on the watchlist and favorites of the
+// viewer. Returns the error if the lists could not be retrieved.
+func embedLists(
+  r *http.Request,
+  database *sql.DB,
+  shows []types.Show,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	var ids []string
+
+	for _, show := range shows {
+		ids = append(ids, show.Id)
+	}
+
+	watchlist, err := queries.ListMembership(database, access.Viewer(r), queries.Watchlist, ids)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve watchlist. %v", err))
+		return err
+	}
+
+	favorites, err := queries.ListMembership(database, access.Viewer(r), queries.Favorites, ids)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve favorites. %v", err))
+		return err
+	}
+
+	for index := range shows {
+		shows[index].InWatchlist = watchlist[shows[index].Id]
+		shows[index].Favorite = favorites[shows[index].Id]
+	}
+
+	return nil
+}
+
+// Attaches the average user score of every given show to it, along with the
+// score the viewer gave. Returns the error if the scores could not be
+// retrieved.
+func embedRatings(
+  r *http.Request,
+  database *sql.DB,
+  shows []types.Show,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	var ids []string
+
+	for _, show := range shows {
+		ids = append(ids, show.Id)
+	}
+
+	ratings, err := queries.RatingsFor(database, access.Viewer(r), ids)
+	if err != nil {
+		log.Error(*functionId, fmt.Sprintf("Failed to retrieve user ratings. %v", err))
+		return err
+	}
+
+	for index := range shows {
+		shows[index].Rating = ratings[shows[index].Id]
+	}
+
+	return nil
 }
@@ -0,0 +1,108 @@
+package shows_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+// Returns the url member of the object name of item.
+func link(item map[string]any, name string) string {
+	object, _ := item[name].(map[string]any)
+	url, _ := object["url"].(string)
+	return url
+}
+
+func TestRead(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+
+	var titles []string = []string{"Andor", "Blue Eye Samurai", "Chernobyl"}
+	var ids []string
+
+	for _, title := range titles {
+		ids = append(ids, admin.CreateShow(title))
+	}
+
+	// Pages follow each other in title order, and link to the cover and the
+	// episodes of every show.
+	var read []string
+	for target := "/api/v1/shows?limit=2"; target != ""; {
+		page := admin.Send("GET", target).Expect(200).JSON()
+		target, _ = page["next"].(string)
+
+		data, _ := page["data"].([]any)
+		for _, value := range data {
+			show, _ := value.(map[string]any)
+			title, _ := show["title"].(string)
+			read = append(read, title)
+
+			admin.Send("GET", link(show, "cover")).Expect(200)
+			admin.Send("GET", link(show, "episodes")).Expect(200)
+		}
+	}
+
+	if strings.Join(read, ", ") != strings.Join(titles, ", ") {
+		t.Errorf("the pages held %v", read)
+	}
+
+	show := admin.Send("GET", "/api/v1/shows/"+ids[1]).Expect(200).Data()
+	if show["title"] != titles[1] || link(show, "cover") != "/api/v1/shows/"+ids[1]+"/cover" {
+		t.Errorf("read the show %v", show)
+	}
+
+	admin.Send("GET", "/api/v1/shows/unknown").Expect(404)
+}
+
+func TestReadMalformedQuery(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	admin.CreateShow("Severance")
+
+	for _, test := range []struct {
+		query  url.Values
+		name   string
+		reason string
+	}{
+		{url.Values{"filter": {"rating>=7 and nonsense=1"}}, "filter", `Unexpected "nonsense" at position 15: unknown field.`},
+		{url.Values{"filter": {"(rating>=7"}}, "filter", "Ended unexpectedly at position 11: expected a closing parenthesis."},
+		{url.Values{"sort": {"-nonsense"}}, "sort", `Unexpected "nonsense" at position 1: unknown field.`},
+		{url.Values{"filter": {strings.Repeat("year>0 or ", 102) + "year>0"}}, "filter", `Unexpected ">" at position 1025: the filter is longer than 1024 bytes.`},
+		{url.Values{"filter": {strings.Repeat("(", 33) + "year>0" + strings.Repeat(")", 33)}}, "filter", `Unexpected "(" at position 33: the filter nests deeper than 32 levels.`},
+		{url.Values{"filter": {strings.Repeat("not ", 33) + "year>0"}}, "filter", `Unexpected "not" at position 129: the filter nests deeper than 32 levels.`},
+	} {
+		params, _ := admin.Send("GET", "/api/v1/shows?"+test.query.Encode()).Expect(400).JSON()["invalid_params"].([]any)
+		if len(params) != 1 {
+			t.Errorf("%v rejected the parameters %v", test.query, params)
+			continue
+		}
+
+		param, _ := params[0].(map[string]any)
+		if param["name"] != test.name || param["reason"] != test.reason {
+			t.Errorf("%v rejected %v", test.query, param)
+		}
+	}
+
+	admin.Send("GET", "/api/v1/shows?filter="+url.QueryEscape("rating>=0 or tag:drama")).Expect(200)
+}
+
+func TestReadFilter(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	admin.CreateShow("Severance")
+
+	for filter, want := range map[string]float64{
+		"year>=2000 and title=severance": 1,
+		"year<2000":                      0,
+		// As long a list of terms as fits, and as deep a nesting as is allowed.
+		strings.Repeat("year<2000 or ", 78) + "title~sev":                1,
+		strings.Repeat("(", 32) + "year>=2000" + strings.Repeat(")", 32): 1,
+	} {
+		page := admin.Send("GET", "/api/v1/shows?filter="+url.QueryEscape(filter)).Expect(200).JSON()
+		if page["total"] != want {
+			t.Errorf("%.40q let %v shows through, want %v", filter, page["total"], want)
+		}
+	}
+}
@@ -2,15 +2,14 @@ package shows
 
 import (
 	"database/sql"
-	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
 	"github.com/andrewdotjs/watchify-server/internal/types"
-	"github.com/google/uuid"
 )
 
 func Update(
@@ -19,21 +18,13 @@ func Update(
   db *sql.DB,
   appDirectory *string,
   log *logger.Logger,
-) {
+) error {
 	var id string = r.PathValue("id")
 	var episodeCount int = 0
-	var functionId string = uuid.NewString()
 
 
 	if id == "" {
-		responses.Error{
-			Type:     "null",
-			Title:    "Invalid Request",
-			Status:   400,
-			Detail:   "Id was not present in the URL.",
-			Instance: r.URL.Path,
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "Id was not present in the URL.")
 	}
 
 	// update episode count
@@ -47,35 +38,13 @@ func Update(
 		`,
 		id,
 	).Scan(&episodeCount); err != nil {
-		var response responses.Error
-
-		switch {
-		default:
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-			log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-		}
-
-		response.ToClient(w)
-		return
+		return err
 	}
 
 	description := r.FormValue("description")
 
 	if len(description) > 1000 {
-		responses.Error{
-			Type:     "null",
-			Title:    "Invalid Request",
-			Status:   400,
-			Detail:   "The description was larger than 1000 bytes. In UTF-8 encoding, the usual English characters are 1 byte each.",
-			Instance: r.URL.Path,
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "The description was larger than 1000 bytes. In UTF-8 encoding, the usual English characters are 1 byte each.")
 	}
 
 	description = strings.ReplaceAll(description, "\n", "&#13;") // Cleanse 1
@@ -103,24 +72,11 @@ func Update(
 		updatedShow.LastModified,
 		updatedShow.Id,
 	); err != nil {
-		var response responses.Error
-
-		switch {
-		default:
-			response = responses.Error{
-				Type:     "null",
-				Title:    "An unknown error has occurred.",
-				Status:   500,
-				Detail:   "Sorry, but this error hasn't been properly logged yet.",
-				Instance: r.URL.Path,
-			}
-			log.Error(functionId, fmt.Sprintf("Failed to give an accurate error response as it was not logged yet. Please log immediately. %v", err))
-		}
-
-		response.ToClient(w)
+		return err
 	}
 
 	responses.Status{
 		Status: 200,
 	}.ToClient(w)
+	return nil
 }
@@ -0,0 +1,43 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/openapi"
+)
+
+// Returns the OpenAPI 3.1 document describing every route of the API, for
+// generating clients and documentation. Sent as it is rather than within the
+// status envelope, so that tools can read it.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /api/openapi.json
+//   - Auth?       : False
+//
+// # HTTP response JSON contents:
+//   - The OpenAPI document.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  document *openapi.Document,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to encode OpenAPI document. %v", err))
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", "en")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+	return nil
+}
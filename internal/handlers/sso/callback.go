@@ -0,0 +1,341 @@
+package sso
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/oidc"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Completes a single sign-on login once the provider sends the browser back. The
+// user linked to the provider account is logged in, and is created first if
+// there is none. Logins started through /auth/oidc/link link the account instead.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /auth/oidc/callback
+//   - Auth?       : False
+//
+// # HTTP request query parameters:
+//   - code        : REQUIRED. Authorization code issued by the provider.
+//   - state       : REQUIRED. State of the pending login.
+//
+// # HTTP response:
+//   - 302 redirect to the landing URL, with a session cookie. Users with
+//     two-factor authentication enabled get a two_factor_challenge query
+//     parameter instead, to complete through /auth/login/2fa.
+func Callback(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  provider *oidc.Provider,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var state string = r.URL.Query().Get("state")
+	var nonce, verifier string
+	var linkUserId sql.NullString
+
+	if provider == nil {
+		return notConfigured()
+	}
+
+	cookie, err := r.Cookie(stateCookie)
+	if err != nil || state == "" || cookie.Value != state {
+		return problems.New(problems.InvalidRequest, "The login was not started in this browser. Start it again.")
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: stateCookie, Path: "/api/v1/auth/oidc", MaxAge: -1})
+
+	// Every pending login can only be completed once.
+	if err := database.QueryRow(`
+		DELETE FROM
+			oidc_logins
+		WHERE
+			state = ? AND expiry_date > ?
+		RETURNING
+			nonce, verifier, user_id
+		`,
+		secrets.Digest(state),
+		time.Now().Format("2006-01-02 15:04:05"),
+	).Scan(&nonce, &verifier, &linkUserId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return problems.New(problems.InvalidRequest, "The login has expired or was already completed. Start it again.")
+		}
+
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if providerError := r.URL.Query().Get("error"); providerError != "" {
+		return problems.New(problems.Unauthorized, fmt.Sprintf("The identity provider refused the login with %q.", providerError))
+	}
+
+	claims, err := provider.Exchange(r.Context(), r.URL.Query().Get("code"), verifier, nonce)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to complete single sign-on. %v", err))
+		return problems.New(problems.Unauthorized, fmt.Sprintf("The login could not be verified. %v", err))
+	}
+
+	user, err := identify(database, provider.Config, claims, linkUserId.String, r)
+	if err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if !linkUserId.Valid && user.TwoFactorEnabled {
+		// The identity provider vouched for the user, so telling them their
+		// second factor is locked gives nothing away.
+		lockedUntil, err := queries.TwoFactorLockedUntil(database, user.Id)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to check the second factor. %v", err))
+			return err
+		}
+
+		if wait := time.Until(lockedUntil); wait > 0 {
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			return problems.New(problems.TooManyAttempts, fmt.Sprintf("Too many wrong codes were entered. Try again in %d seconds.", seconds))
+		}
+
+		challenge, _, err := queries.CreateChallenge(database, user.Id)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to create login challenge. %v", err))
+			return err
+		}
+
+		landing, err := url.Parse(provider.Config.LandingUrl)
+		if err != nil {
+			landing = &url.URL{Path: "/"}
+		}
+
+		query := landing.Query()
+		query.Set("two_factor_challenge", challenge)
+		landing.RawQuery = query.Encode()
+
+		http.Redirect(w, r, landing.String(), http.StatusFound)
+		return nil
+	}
+
+	if !linkUserId.Valid {
+		token, expiry, err := queries.CreateSession(database, user.Id)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to create session. %v", err))
+			return err
+		}
+
+		access.SetSessionCookie(w, r, token, expiry)
+	}
+
+	log.Info(functionId, fmt.Sprintf("User %s logged in through single sign-on", user.Username))
+	http.Redirect(w, r, provider.Config.LandingUrl, http.StatusFound)
+	return nil
+}
+
+// Returns the user linked to the provider account that the claims describe. The
+// account is linked to linkUserId when it is not empty, and to a newly created
+// user when it is not linked to anyone yet. Roles are updated from the claims
+// on every login. Returns the problem to send to the client on failure.
+func identify(
+  database *sql.DB,
+  config *oidc.Config,
+  claims *oidc.Claims,
+  linkUserId string,
+  r *http.Request,
+) (*types.User, error) {
+	var userId string
+	var role string = mappedRole(config, claims)
+	var now string = time.Now().Format("2006-01-02 15:04:05")
+
+	unknownError := func(err error) error {
+		return err
+	}
+
+	err := database.QueryRow(`
+		SELECT
+			user_id
+		FROM
+			user_identities
+		WHERE
+			issuer = ? AND subject = ?
+		`,
+		claims.Issuer,
+		claims.Subject,
+	).Scan(&userId)
+
+	switch {
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		return nil, unknownError(err)
+	case linkUserId != "" && err == nil && userId != linkUserId:
+		return nil, problems.New(problems.Conflict, "The account at the identity provider is already linked to another user.")
+	case linkUserId != "" && err == nil:
+		return queryUser(database, userId)
+	case linkUserId != "":
+		userId = linkUserId
+	case errors.Is(err, sql.ErrNoRows):
+		// Provision a user for accounts that log in for the first time.
+		username, err := availableUsername(database, claims)
+		if err != nil {
+			return nil, unknownError(err)
+		}
+
+		userId = uuid.NewString()
+
+		if role == "" && access.IsRole(config.DefaultRole) {
+			role = config.DefaultRole
+		} else if role == "" {
+			role = "viewer"
+		}
+
+		if _, err := database.Exec(`
+			INSERT INTO
+				users (id, username, password_hash, role, upload_date, last_modified)
+			VALUES
+				(?, ?, '', ?, ?, ?)
+			`,
+			userId,
+			username,
+			role,
+			now,
+			now,
+		); err != nil {
+			return nil, unknownError(err)
+		}
+	default:
+		if role != "" {
+			if _, err := database.Exec(`
+				UPDATE
+					users
+				SET
+					role = ?, last_modified = ?
+				WHERE
+					id = ? AND role != ?
+				`,
+				role,
+				now,
+				userId,
+				role,
+			); err != nil {
+				return nil, unknownError(err)
+			}
+		}
+
+		return queryUser(database, userId)
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			user_identities (issuer, subject, user_id, email, upload_date)
+		VALUES
+			(?, ?, ?, ?, ?)
+		`,
+		claims.Issuer,
+		claims.Subject,
+		userId,
+		claims.Email,
+		now,
+	); err != nil {
+		return nil, unknownError(err)
+	}
+
+	return queryUser(database, userId)
+}
+
+// Returns the user with the given id, or the problem to send to the client.
+func queryUser(database *sql.DB, id string) (*types.User, error) {
+	user, err := queries.User(database, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Returns the most privileged role that the role claim maps to, or an empty
+// string if none of its values are mapped.
+func mappedRole(config *oidc.Config, claims *oidc.Claims) string {
+	var mapped map[string]bool = map[string]bool{}
+
+	for _, value := range oidc.Strings(claims.Raw[config.RoleClaim]) {
+		if role := config.RoleMapping[value]; role != "" {
+			mapped[role] = true
+		}
+	}
+
+	for _, role := range access.Roles() {
+		if mapped[role.Name] {
+			return role.Name
+		}
+	}
+
+	return ""
+}
+
+// Picks a username for a provisioned user from its preferred username or email,
+// numbering it when it is already taken.
+func availableUsername(database *sql.DB, claims *oidc.Claims) (string, error) {
+	var base string = claims.PreferredUsername
+	var builder strings.Builder
+
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	for _, character := range base {
+		switch {
+		case character >= 'a' && character <= 'z', character >= 'A' && character <= 'Z',
+			character >= '0' && character <= '9', character == '.', character == '-', character == '_':
+			builder.WriteRune(character)
+		}
+	}
+
+	base = builder.String()
+
+	if len(base) > 28 {
+		base = base[:28]
+	}
+
+	if len(base) < 3 {
+		base = "user"
+	}
+
+	for number := 1; ; number++ {
+		var candidate string = base
+		var existing int = 0
+
+		if number > 1 {
+			candidate = fmt.Sprintf("%s%d", base, number)
+		}
+
+		if err := database.QueryRow(`
+			SELECT
+				COUNT(*)
+			FROM
+				users
+			WHERE
+				username = ?
+			`,
+			candidate,
+		).Scan(&existing); err != nil {
+			return "", err
+		}
+
+		if existing == 0 {
+			return candidate, nil
+		}
+	}
+}
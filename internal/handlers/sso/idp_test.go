@@ -0,0 +1,204 @@
+package sso_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/oidc"
+)
+
+// Client id the server is registered with at the stand-in provider.
+const clientId string = "watchify"
+
+// Id of the key the stand-in provider signs with.
+const keyId string = "stand-in"
+
+// A pending authorization at the stand-in provider.
+type authorization struct {
+	redirectUri string
+	nonce       string
+	challenge   string
+}
+
+// A stand-in OpenID Connect provider serving discovery, a key set, and the
+// authorization and token endpoints of the authorization code flow with PKCE.
+// Every authorization is granted right away to the account of subject.
+type identityProvider struct {
+	*httptest.Server
+
+	mutex          sync.Mutex
+	key            *rsa.PrivateKey // published in the key set
+	signer         *rsa.PrivateKey // signs ID tokens, key unless a test swaps it
+	codes          map[string]authorization
+	subject        string
+	claims         map[string]any            // claims added to every ID token
+	tamper         func(claims map[string]any) // changes the claims of ID tokens, if set
+	rejectedGrants int
+}
+
+func newIdentityProvider(t *testing.T) *identityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &identityProvider{
+		key:     key,
+		signer:  key,
+		codes:   map[string]authorization{},
+		subject: "subject-1",
+		claims: map[string]any{
+			"email":              "ada@example.com",
+			"preferred_username": "ada",
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", provider.discovery)
+	mux.HandleFunc("GET /jwks", provider.keySet)
+	mux.HandleFunc("GET /authorize", provider.authorize)
+	mux.HandleFunc("POST /token", provider.token)
+
+	provider.Server = httptest.NewServer(mux)
+	t.Cleanup(provider.Close)
+
+	return provider
+}
+
+// Returns the configuration of a server logging in through the provider, with
+// members of the media-admins group mapped to admins.
+func (provider *identityProvider) config() *oidc.Config {
+	return &oidc.Config{
+		Issuer:      provider.URL,
+		ClientId:    clientId,
+		Scopes:      []string{"profile", "email"},
+		RoleClaim:   "groups",
+		RoleMapping: map[string]string{"media-admins": "admin"},
+		DefaultRole: "viewer",
+		LandingUrl:  "/",
+	}
+}
+
+func (provider *identityProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]any{
+		"issuer":                 provider.URL,
+		"authorization_endpoint": provider.URL + "/authorize",
+		"token_endpoint":         provider.URL + "/token",
+		"jwks_uri":               provider.URL + "/jwks",
+	})
+}
+
+func (provider *identityProvider) keySet(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyId,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(provider.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(provider.key.E)).Bytes()),
+		}},
+	})
+}
+
+// Grants the authorization and sends the browser back with a code.
+func (provider *identityProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	var query url.Values = r.URL.Query()
+
+	if query.Get("response_type") != "code" || query.Get("client_id") != clientId ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	code := rand.Text()
+
+	provider.mutex.Lock()
+	provider.codes[code] = authorization{
+		redirectUri: query.Get("redirect_uri"),
+		nonce:       query.Get("nonce"),
+		challenge:   query.Get("code_challenge"),
+	}
+	provider.mutex.Unlock()
+
+	callback, _ := url.Parse(query.Get("redirect_uri"))
+	callbackQuery := callback.Query()
+	callbackQuery.Set("code", code)
+	callbackQuery.Set("state", query.Get("state"))
+	callback.RawQuery = callbackQuery.Encode()
+
+	http.Redirect(w, r, callback.String(), http.StatusFound)
+}
+
+// Redeems a code for an ID token, once, as long as the code verifier matches
+// the challenge the authorization was started with.
+func (provider *identityProvider) token(w http.ResponseWriter, r *http.Request) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	granted, exists := provider.codes[r.FormValue("code")]
+	delete(provider.codes, r.FormValue("code"))
+
+	if !exists || r.FormValue("grant_type") != "authorization_code" || r.FormValue("client_id") != clientId ||
+		r.FormValue("redirect_uri") != granted.redirectUri || oidc.Challenge(r.FormValue("code_verifier")) != granted.challenge {
+		provider.rejectedGrants++
+		writeJson(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	var now time.Time = time.Now()
+	var claims map[string]any = map[string]any{
+		"iss":   provider.URL,
+		"sub":   provider.subject,
+		"aud":   clientId,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": granted.nonce,
+	}
+
+	for name, value := range provider.claims {
+		claims[name] = value
+	}
+
+	if provider.tamper != nil {
+		provider.tamper(claims)
+	}
+
+	writeJson(w, http.StatusOK, map[string]string{
+		"token_type": "Bearer",
+		"id_token":   provider.sign(claims),
+	})
+}
+
+// Returns claims as a compact JWS signed with RS256.
+func (provider *identityProvider) sign(claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": keyId, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, provider.signer, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJson(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
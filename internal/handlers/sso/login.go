@@ -0,0 +1,139 @@
+package sso
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/oidc"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+)
+
+// Name of the cookie that ties a pending login to the browser that started it,
+// which keeps others from completing a login in someone else's browser.
+const stateCookie string = "watchify_oidc_state"
+
+// How long users have to log in at the provider.
+const loginLifetime time.Duration = 10 * time.Minute
+
+// Starts a single sign-on login by sending the browser to the provider.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /auth/oidc/login
+//   - Auth?       : False
+//
+// # HTTP response:
+//   - 302 redirect to the authorization endpoint of the provider.
+func Login(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  provider *oidc.Provider,
+  log *logger.Logger,
+) error {
+	return start(w, r, database, provider, "", log)
+}
+
+// Starts linking the logged in user to an account at the provider. Once the
+// user logs in at the provider, they can log in with single sign-on.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /auth/oidc/link
+//   - Auth?       : True
+//
+// # HTTP response:
+//   - 302 redirect to the authorization endpoint of the provider.
+func Link(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  provider *oidc.Provider,
+  log *logger.Logger,
+) error {
+	if !access.FullAccess(r) {
+		return problems.New(problems.Forbidden, "Accounts can only be linked with a session or an admin scoped API token.")
+	}
+
+	return start(w, r, database, provider, access.User(r).Id, log)
+}
+
+// Records a pending login and redirects the browser to the provider. A non-empty
+// userId links the provider account to that user instead of logging in.
+func start(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  provider *oidc.Provider,
+  userId string,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var linkUserId any = nil
+	var values []string = []string{}
+
+	if provider == nil {
+		return notConfigured()
+	}
+
+	for range 3 {
+		value, err := secrets.Token()
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to generate login values. %v", err))
+			return err
+		}
+
+		values = append(values, value)
+	}
+
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	if userId != "" {
+		linkUserId = userId
+	}
+
+	authorizationUrl, err := provider.AuthorizationUrl(r.Context(), state, nonce, verifier)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to reach the identity provider. %v", err))
+		return problems.New(problems.BadGateway, "The identity provider could not be reached.")
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			oidc_logins (state, nonce, verifier, user_id, expiry_date)
+		VALUES
+			(?, ?, ?, ?, ?)
+		`,
+		secrets.Digest(state),
+		nonce,
+		verifier,
+		linkUserId,
+		time.Now().Add(loginLifetime).Format("2006-01-02 15:04:05"),
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to insert pending login. %v", err))
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    state,
+		Path:     "/api/v1/auth/oidc",
+		MaxAge:   int(loginLifetime.Seconds()),
+		HttpOnly: true,
+		Secure:   access.IsSecure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authorizationUrl, http.StatusFound)
+	return nil
+}
+
+// Returns the problem sent when single sign-on is not configured.
+func notConfigured() error {
+	return problems.New(problems.NotFound, "Single sign-on is not configured on this server.")
+}
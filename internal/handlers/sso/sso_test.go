@@ -0,0 +1,244 @@
+package sso_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/oidc"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+// Starts a server logging in through a new stand-in provider.
+func setup(t *testing.T) (*servertest.Server, *identityProvider) {
+	idp := newIdentityProvider(t)
+	config := idp.config()
+
+	server := servertest.NewWithProvider(t, oidc.New(config))
+	config.RedirectUrl = server.URL + "/api/v1/auth/oidc/callback"
+
+	return server, idp
+}
+
+// Follows a login started at start through the provider, and returns the
+// response of the callback.
+func signIn(t *testing.T, client *servertest.Client, start string) *servertest.Response {
+	t.Helper()
+
+	authorization := client.Send("GET", start).Expect(302).Header.Get("Location")
+	callback := client.Send("GET", authorization).Expect(302).Header.Get("Location")
+
+	if !strings.Contains(callback, "/api/v1/auth/oidc/callback") {
+		t.Fatalf("the provider sent the browser to %s", callback)
+	}
+
+	return client.Send("GET", callback)
+}
+
+// Returns the number of users of the server.
+func userCount(t *testing.T, server *servertest.Server) int {
+	var count int
+
+	if err := server.DB.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+
+	return count
+}
+
+func TestLogin(t *testing.T) {
+	server, idp := setup(t)
+	server.Admin(t)
+
+	client := server.Client(t)
+	if location := signIn(t, client, "/api/v1/auth/oidc/login").Expect(302).Header.Get("Location"); location != "/" {
+		t.Errorf("the browser was sent to %s rather than the landing page", location)
+	}
+
+	// Accounts logging in for the first time get a user of the default role.
+	me := client.Send("GET", "/api/v1/auth/me").Expect(200)
+	if username, role := me.String("username"), me.String("role"); username != "ada" || role != "viewer" {
+		t.Errorf("provisioned %s as a %s", username, role)
+	}
+
+	// Later logins log the same user in, with the role the claims map to.
+	idp.claims["groups"] = []string{"family", "media-admins"}
+
+	other := server.Client(t)
+	signIn(t, other, "/api/v1/auth/oidc/login").Expect(302)
+
+	me = other.Send("GET", "/api/v1/auth/me").Expect(200)
+	if username, role := me.String("username"), me.String("role"); username != "ada" || role != "admin" {
+		t.Errorf("logged in as %s, a %s", username, role)
+	}
+
+	if count := userCount(t, server); count != 2 {
+		t.Errorf("the server has %d users rather than the admin and ada", count)
+	}
+
+	// Pending logins can only be completed once.
+	authorization := client.Send("GET", "/api/v1/auth/oidc/login").Expect(302).Header.Get("Location")
+	callback := client.Send("GET", authorization).Expect(302).Header.Get("Location")
+	client.Send("GET", callback).Expect(302)
+	server.Client(t).Send("GET", callback).Expect(400)
+}
+
+func TestRejectedTokens(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		tamper func(idp *identityProvider, claims map[string]any)
+	}{
+		{"bad signature", func(idp *identityProvider, claims map[string]any) {
+			idp.signer = otherKey
+		}},
+		{"wrong audience", func(idp *identityProvider, claims map[string]any) {
+			claims["aud"] = "another-client"
+		}},
+		{"wrong issuer", func(idp *identityProvider, claims map[string]any) {
+			claims["iss"] = "https://impostor.example.com"
+		}},
+		{"wrong nonce", func(idp *identityProvider, claims map[string]any) {
+			claims["nonce"] = "replayed"
+		}},
+		{"expired", func(idp *identityProvider, claims map[string]any) {
+			claims["iat"] = time.Now().Add(-time.Hour).Unix()
+			claims["exp"] = time.Now().Add(-10 * time.Minute).Unix()
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			server, idp := setup(t)
+			idp.tamper = func(claims map[string]any) { test.tamper(idp, claims) }
+
+			client := server.Client(t)
+			if kind := signIn(t, client, "/api/v1/auth/oidc/login").Expect(401).Problem(); kind != problems.Unauthorized.URI {
+				t.Errorf("the login failed with %s", kind)
+			}
+
+			client.Send("GET", "/api/v1/auth/me").Expect(401)
+
+			if count := userCount(t, server); count != 0 {
+				t.Errorf("a user was provisioned for a rejected token")
+			}
+		})
+	}
+}
+
+func TestPkce(t *testing.T) {
+	server, idp := setup(t)
+
+	// The provider only hands out tokens for the verifier the challenge was
+	// derived from.
+	client := server.Client(t)
+	signIn(t, client, "/api/v1/auth/oidc/login").Expect(302)
+
+	if idp.rejectedGrants != 0 {
+		t.Fatalf("the provider rejected %d code verifiers", idp.rejectedGrants)
+	}
+
+	// A code redeemed with another verifier, as an attacker that intercepted
+	// it would, is refused.
+	authorization := client.Send("GET", "/api/v1/auth/oidc/login").Expect(302).Header.Get("Location")
+	callback := client.Send("GET", authorization).Expect(302).Header.Get("Location")
+
+	if _, err := server.DB.Exec("UPDATE oidc_logins SET verifier = 'intercepted'"); err != nil {
+		t.Fatal(err)
+	}
+
+	client.Send("GET", callback).Expect(401)
+
+	if idp.rejectedGrants != 1 {
+		t.Errorf("the provider rejected %d code verifiers rather than one", idp.rejectedGrants)
+	}
+
+	// Callbacks from browsers that did not start the login are refused before
+	// the code is redeemed.
+	authorization = client.Send("GET", "/api/v1/auth/oidc/login").Expect(302).Header.Get("Location")
+	callback = client.Send("GET", authorization).Expect(302).Header.Get("Location")
+	server.Client(t).Send("GET", callback).Expect(400)
+}
+
+func TestLinking(t *testing.T) {
+	server, idp := setup(t)
+	admin := server.Admin(t)
+
+	// Linking is only for logged in users.
+	server.Client(t).Send("GET", "/api/v1/auth/oidc/link").Expect(401)
+
+	if location := signIn(t, admin, "/api/v1/auth/oidc/link").Expect(302).Header.Get("Location"); location != "/" {
+		t.Errorf("the browser was sent to %s rather than the landing page", location)
+	}
+
+	// Single sign-on now logs the existing user in rather than provisioning
+	// a new one.
+	client := server.Client(t)
+	signIn(t, client, "/api/v1/auth/oidc/login").Expect(302)
+
+	if username := client.Send("GET", "/api/v1/auth/me").Expect(200).String("username"); username != servertest.AdminUsername {
+		t.Errorf("logged in as %s rather than the linked admin", username)
+	}
+
+	if count := userCount(t, server); count != 1 {
+		t.Errorf("the server has %d users rather than only the admin", count)
+	}
+
+	// An account can't be linked to two users.
+	idp.subject = "subject-2"
+	second := server.Client(t)
+	signIn(t, second, "/api/v1/auth/oidc/login").Expect(302)
+
+	idp.subject = "subject-1"
+	if kind := signIn(t, second, "/api/v1/auth/oidc/link").Expect(409).Problem(); kind != problems.Conflict.URI {
+		t.Errorf("linking an account linked to someone else failed with %s", kind)
+	}
+
+	// Once unlinked, the account gets a user of its own.
+	admin.Send("DELETE", "/api/v1/auth/oidc/link").Expect(200)
+
+	unlinked := server.Client(t)
+	signIn(t, unlinked, "/api/v1/auth/oidc/login").Expect(302)
+
+	if username := unlinked.Send("GET", "/api/v1/auth/me").Expect(200).String("username"); username == servertest.AdminUsername {
+		t.Error("an unlinked account still logs in as the admin")
+	}
+}
+
+func TestLoginLockedTwoFactor(t *testing.T) {
+	server, _ := setup(t)
+	admin := server.Admin(t)
+	signIn(t, admin, "/api/v1/auth/oidc/link").Expect(302)
+	admin.Send("POST", "/api/v1/auth/2fa").Expect(200)
+
+	if _, err := server.DB.Exec("UPDATE users SET totp_enabled = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Users with a locked second factor are not handed a challenge, which
+	// could not be completed anyway.
+	if _, err := server.DB.Exec(
+		"INSERT INTO two_factor_failures (user_id, failures, locked_until) SELECT id, 5, ? FROM users",
+		time.Now().Add(time.Minute).Format("2006-01-02 15:04:05"),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	locked := signIn(t, server.Client(t), "/api/v1/auth/oidc/login").Expect(429)
+	if kind := locked.Problem(); kind != problems.TooManyAttempts.URI || locked.Header.Get("Retry-After") == "" {
+		t.Errorf("the lockout was answered with %s, Retry-After %q", kind, locked.Header.Get("Retry-After"))
+	}
+
+	if _, err := server.DB.Exec("DELETE FROM two_factor_failures"); err != nil {
+		t.Fatal(err)
+	}
+
+	if location := signIn(t, server.Client(t), "/api/v1/auth/oidc/login").Expect(302).Header.Get("Location"); !strings.Contains(location, "two_factor_challenge=") {
+		t.Errorf("the browser was sent to %s rather than to enter a code", location)
+	}
+}
@@ -0,0 +1,58 @@
+package sso
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Unlinks the logged in user from their accounts at identity providers. Users
+// without a password must set one first, as they could not log in otherwise.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /auth/oidc/link
+//   - Auth?       : True
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Unlink(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var user *types.User = access.User(r)
+
+	if !access.FullAccess(r) {
+		return problems.New(problems.Forbidden, "Accounts can only be unlinked with a session or an admin scoped API token.")
+	}
+
+	if user.PasswordHash == "" {
+		return problems.New(problems.Conflict, "Set a password before unlinking, otherwise you could not log in anymore.")
+	}
+
+	if _, err := database.Exec(`
+		DELETE FROM
+			user_identities
+		WHERE
+			user_id = ?
+		`,
+		user.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to unlink identities. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -2,6 +2,7 @@ package stream
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
@@ -10,16 +11,21 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/functions"
-	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/google/uuid"
 )
 
-// Returns a video stream to the client using the id.
+// Returns a video stream to the client using the id. Starting playback from the
+// beginning records the video in the watch history of the selected profile.
 //
 // # Specifications:
 //   - Method   : GET
 //   - Endpoint : /stream/{id}
-//   - Auth?    : False
+//   - Auth?    : True
 //
 // # HTTP request path parameters:
 //   - id       : REQUIRED. UUID of the video.
@@ -28,24 +34,23 @@ func Read(
   r *http.Request,
   database *sql.DB,
   appDirectory *string,
-) {
+  log *logger.Logger,
+) error {
 	var id string = r.PathValue("id")
 	var streamType string = "movies"
 	var fileName string = ""
+	var mediaType string = "movie"
 
 	if len(r.URL.Query().Get("type")) > 10 {
-  	responses.Status{
-  		Type:     "null",
-  		Title:    "Unknown Error",
-  		Status:   400,
-  		Detail:   "Value in type query too large, limit is 10",
-  		Instance: r.URL.Path,
-  	}.ToClient(w)
-  	return
+  	return problems.New(problems.InvalidRequest, "Value in type query too large, limit is 10")
 	}
 
+	condition, conditionArguments := access.RatingCondition(r, "content_rating_id")
+
 	if r.URL.Query().Get("type") == "show" {
     streamType = "episodes"
+    mediaType = "episode"
+    condition, conditionArguments = access.EpisodeCondition(r)
 	}
 
 	if err := database.QueryRow(
@@ -56,33 +61,30 @@ func Read(
   			FROM
   			  %s
   			WHERE
-  			  id=?
+  			  id=? AND %s
   		`,
       streamType,
+      condition,
 		),
-		id,
+		append([]any{id}, conditionArguments...)...,
 	).Scan(&fileName); err != nil {
-	  responses.Status{
-  		Type:     "null",
-  		Title:    "Unknown Error",
-  		Status:   500,
-  		Detail:   fmt.Sprintf("%v", err),
-  		Instance: r.URL.Path,
-  	}.ToClient(w)
-  	return
+	  if errors.Is(err, sql.ErrNoRows) {
+	    return problems.New(problems.NotFound, "No video could be found with the given id.")
+	  }
+
+	  return err
+	}
+
+	if profile := access.Profile(r); profile != nil && isStart(r) {
+		if err := queries.RecordWatch(database, profile.Id, id, mediaType); err != nil {
+			log.Error(uuid.NewString(), fmt.Sprintf("Failed to record watch history. %v", err))
+		}
 	}
 
 	filePath := path.Join(*appDirectory, "storage", "videos", fileName)
 	videoFile, err := os.Open(filePath)
 	if err != nil {
-	  responses.Status{
-  		Type:     "null",
-  		Title:    "Unknown Error",
-  		Status:   500,
-  		Detail:   fmt.Sprintf("%v", err),
-  		Instance: r.URL.Path,
-  	}.ToClient(w)
-  	return
+	  return err
 	}
 
 	defer videoFile.Close()
@@ -90,14 +92,7 @@ func Read(
 	// Get file information
 	fileInfo, err := videoFile.Stat()
 	if err != nil {
-	  responses.Status{
-  		Type:     "null",
-  		Title:    "Unknown Error",
-  		Status:   500,
-  		Detail:   fmt.Sprintf("%v", err),
-  		Instance: r.URL.Path,
-  	}.ToClient(w)
-  	return
+	  return err
 	}
 
 	w.Header().Set("Content-Type", "video/mp4")
@@ -123,10 +118,18 @@ func Read(
 		// Seek to the specified position and stream the partial content
 		videoFile.Seek(start, 0)
 		http.ServeContent(w, r, filePath, fileInfo.ModTime(), videoFile)
-		return
+		return nil
 	}
 
 	// If no Range header is present, serve the entire file
 	w.Header().Set("Content-Length", fmt.Sprintf("%d", fileInfo.Size()))
 	http.ServeContent(w, r, filePath, fileInfo.ModTime(), videoFile)
+	return nil
+}
+
+// Reports whether the request asks for the video from its first byte, which
+// players do once when playback starts.
+func isStart(r *http.Request) bool {
+	var rangeHeader string = r.Header.Get("Range")
+	return rangeHeader == "" || strings.HasPrefix(rangeHeader, "bytes=0-")
 }
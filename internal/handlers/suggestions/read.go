@@ -0,0 +1,60 @@
+package suggestions
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/suggest"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets titles of shows and movies, names of people and names of tags starting
+// with what the viewer typed so far, for autocompleting searches. Served from
+// memory, so it is cheap to call on every keystroke.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /suggest
+//   - Auth?       : True
+//
+// # HTTP request query parameters:
+//   - q           : REQUIRED. What the viewer typed. Every word is matched as the start
+//     of a word, and nothing is suggested for less than two characters.
+//   - limit       : OPTIONAL. Number of suggestions to return, 10 by default and at most 25.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Suggestions, best match first, each returning id, type, text and url.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  index *suggest.Index,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var query string = strings.TrimSpace(r.URL.Query().Get("q"))
+	var limit int = functions.Limit(r.URL.Query().Get("limit"), 10, 25)
+	var maxRank int = -1
+	var suggestions []types.Suggestion = []types.Suggestion{}
+
+	if profile := access.Profile(r); profile != nil && profile.MaxContentRating != nil {
+		maxRank = profile.MaxContentRating.Rank
+	}
+
+	if utf8.RuneCountInString(query) >= 2 {
+		suggestions = index.Lookup(query, maxRank, limit)
+	}
+
+	log.Info(functionId, fmt.Sprintf("Returned %d suggestions", len(suggestions)))
+	responses.Status{
+		Status: 200,
+		Data:   suggestions,
+	}.ToClient(w)
+	return nil
+}
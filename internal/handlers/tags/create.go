@@ -0,0 +1,119 @@
+package tags
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Creates a new genre or tag that shows and movies can be categorized with.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /tags
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - name        : REQUIRED. Name of the tag, unique regardless of case.
+//   - kind        : OPTIONAL. Either "genre" or "tag", defaults to "tag".
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The created tag.
+func Create(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var currentTime string = time.Now().Format("2006-01-02 15:04:05")
+	var tag types.Tag = types.Tag{
+		Id:           uuid.NewString(),
+		Name:         functions.Sanitize(r.FormValue("name")),
+		Kind:         r.FormValue("kind"),
+		UploadDate:   currentTime,
+		LastModified: currentTime,
+	}
+	var existing int = 0
+
+	if tag.Kind == "" {
+		tag.Kind = "tag"
+	}
+
+	if err := validate(&tag); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			tags
+		WHERE
+			name = ?
+		`,
+		tag.Name,
+	).Scan(&existing); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if existing > 0 {
+		log.Info(functionId, fmt.Sprintf("Tag named %s already exists", tag.Name))
+		return problems.New(problems.Conflict, "A tag with the given name already exists.")
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			tags (id, name, kind, upload_date, last_modified)
+		VALUES
+			(?, ?, ?, ?, ?)
+		`,
+		tag.Id,
+		tag.Name,
+		tag.Kind,
+		tag.UploadDate,
+		tag.LastModified,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to insert tag. %v", err))
+		return err
+	}
+
+	log.Info(functionId, fmt.Sprintf("Created tag with ID %s", tag.Id))
+	responses.Status{
+		Status: 201,
+		Data:   tag,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks that the name and kind of a tag are acceptable. Returns the
+// problem to send to the client if they are not.
+func validate(tag *types.Tag) error {
+	var name string = ""
+	var detail string = ""
+
+	switch {
+	case tag.Name == "":
+		name, detail = "name", "The name value was empty."
+	case len(tag.Name) > 50:
+		name, detail = "name", "The name value was larger than 50 bytes. In UTF-8 encoding, English characters are 1 byte each."
+	case tag.Kind != "genre" && tag.Kind != "tag":
+		name, detail = "kind", "The kind value must be either genre or tag."
+	default:
+		return nil
+	}
+
+	return problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+}
@@ -0,0 +1,69 @@
+package tags
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Deletes a tag and removes it from every show and movie it was attached to.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /tags/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the tag.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Delete(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	result, err := database.Exec(`
+		DELETE FROM
+			tags
+		WHERE
+			id = ?
+		`,
+		id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete tag. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No tag could be found with the given id.")
+	}
+
+	if _, err := database.Exec(`
+		DELETE FROM
+			taggings
+		WHERE
+			tag_id = ?
+		`,
+		id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete the tag's links. %v", err))
+		return err
+	}
+
+	log.Info(functionId, fmt.Sprintf("Deleted tag with ID %s", id))
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,144 @@
+package tags
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets and returns every tag that is in use along with the amount of shows or
+// movies carrying it. When tags are passed, only content matching them is
+// counted, so clients can narrow down a browse view one tag at a time.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /tags/facets
+//   - Auth?       : True
+//
+// # HTTP request query parameters:
+//   - type        : REQUIRED. Either "shows" or "movies".
+//   - tags        : OPTIONAL. Comma separated tag ids or names to filter by.
+//   - match       : OPTIONAL. "all" to require every tag, "any" (default) otherwise.
+//   - limit       : OPTIONAL. Number of tags per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Tags, each returning id, name, kind and count.
+//   - total       : Number of tags in use across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func Facets(
+  w http.ResponseWriter,
+  r *http.Request,
+  db *sql.DB,
+  log *logger.Logger,
+) error {
+	var parentType string = r.URL.Query().Get("type")
+	var filter []string = functions.SplitList(r.URL.Query().Get("tags"))
+	var matchAll bool = (r.URL.Query().Get("match") == "all")
+	var filterQuery string = ""
+	var arguments []any
+	var facets []types.Tag = []types.Tag{}
+	var functionId string = access.RequestId(r)
+
+	if parentType != "shows" && parentType != "movies" {
+		return problems.New(problems.InvalidRequest, "The type query must be either shows or movies.")
+	}
+
+	// Only what browsing returns is counted, which leaves out content above the
+	// rating limit of the profile, and hidden movies.
+	condition, conditionArguments := access.RatingCondition(r, "parent.content_rating_id")
+	filterQuery = "AND " + condition
+	arguments = append(arguments, conditionArguments...)
+
+	if parentType == "movies" {
+		filterQuery += " AND parent.hidden = 0"
+	}
+
+	if len(filter) > 0 {
+		subquery, subqueryArguments := queries.TagFilter(filter, matchAll)
+		filterQuery += fmt.Sprintf(" AND taggings.parent_id IN (%s)", subquery)
+		arguments = append(arguments, subqueryArguments...)
+	}
+
+	page, err := queries.NewPage(r, 50, []queries.SortKey{{Expression: "tags.kind"}, {Expression: "tags.name"}, {Expression: "tags.id"}})
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+	}
+
+	var from string = fmt.Sprintf(`
+		tags
+		JOIN
+			taggings ON taggings.tag_id = tags.id
+		JOIN
+			%s AS parent ON parent.id = taggings.parent_id
+		`,
+		parentType,
+	)
+
+	total, err := queries.Count(db, fmt.Sprintf("(SELECT tags.id FROM %s WHERE 1 = 1 %s GROUP BY tags.id)", from, filterQuery), "1 = 1", arguments)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	seek, seekArguments := page.Condition()
+
+	rows, err := db.Query(
+		fmt.Sprintf(`
+			SELECT
+				tags.id, tags.name, tags.kind, COUNT(DISTINCT taggings.parent_id) %s
+			FROM
+				%s
+			WHERE
+				%s %s
+			GROUP BY
+				tags.id
+			%s
+			`,
+			page.Columns(),
+			from,
+			seek,
+			filterQuery,
+			page.Order(),
+		),
+		append(seekArguments, arguments...)...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var facet types.Tag
+		var count int
+
+		if err := rows.Scan(append([]any{&facet.Id, &facet.Name, &facet.Kind, &count}, page.Scan()...)...); err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		facet.Count = &count
+		facets = append(facets, facet)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	facets = queries.Trim(page, facets)
+
+	responses.Paged(r, facets, total, page.Next(), page.Prev()).ToClient(w)
+	return nil
+}
@@ -0,0 +1,113 @@
+package tags_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+// Returns the counts of the facets of movies matching query, keyed by tag name.
+func facets(client *servertest.Client, query url.Values) map[string]float64 {
+	var counts map[string]float64 = map[string]float64{}
+
+	query.Set("type", "movies")
+	for _, facet := range client.Send("GET", "/api/v1/tags/facets?"+query.Encode()).Expect(200).JSON()["data"].([]any) {
+		facet := facet.(map[string]any)
+		counts[facet["name"].(string)] = facet["count"].(float64)
+	}
+
+	return counts
+}
+
+func TestFacets(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+
+	drama := admin.Form("POST", "/api/v1/tags", url.Values{"name": {"Drama"}}).Expect(201).String("id")
+	comedy := admin.Form("POST", "/api/v1/tags", url.Values{"name": {"Comedy"}}).Expect(201).String("id")
+
+	family := admin.Form("POST", "/api/v1/content-ratings", url.Values{"system": {"Test"}, "code": {"PG"}, "rank": {"1"}}).Expect(201).String("id")
+	mature := admin.Form("POST", "/api/v1/content-ratings", url.Values{"system": {"Test"}, "code": {"R"}, "rank": {"5"}}).Expect(201).String("id")
+
+	movies := map[string]struct {
+		rating string
+		tags   []string
+		hidden bool
+	}{
+		"Paddington": {family, []string{drama, comedy}, false},
+		"Wall-E":     {family, []string{drama}, false},
+		"Heat":       {mature, []string{drama}, false},
+		"Cats":       {family, []string{drama}, true},
+	}
+
+	for title, movie := range movies {
+		id := admin.CreateMovie(title)
+		admin.Form("PUT", "/api/v1/movies/"+id+"/content-rating", url.Values{"content_rating_id": {movie.rating}}, "If-Match", "*").Expect(200)
+
+		for _, tag := range movie.tags {
+			admin.Send("PUT", "/api/v1/movies/"+id+"/tags/"+tag, "If-Match", "*").Expect(200)
+		}
+
+		if movie.hidden {
+			admin.JSON("POST", "/api/v1/batch", map[string]any{
+				"operations": []map[string]any{{"op": "hide", "id": id, "if_match": "*"}},
+			}).Expect(200)
+		}
+	}
+
+	// Hidden movies are not browsed, so they are not counted either.
+	if counts := facets(admin, url.Values{}); counts["Drama"] != 3 || counts["Comedy"] != 1 {
+		t.Errorf("counted %v, want 3 dramas and 1 comedy", counts)
+	}
+
+	// A tag given twice, or by both its id and its name, is required once.
+	for _, tags := range []string{"Drama,Drama", drama + ",Drama", "Drama,Comedy," + comedy} {
+		want := map[string]float64{"Drama": 3, "Comedy": 1}
+		if tags == "Drama,Comedy,"+comedy {
+			want = map[string]float64{"Drama": 1, "Comedy": 1}
+		}
+
+		counts := facets(admin, url.Values{"tags": {tags}, "match": {"all"}})
+		if counts["Drama"] != want["Drama"] || counts["Comedy"] != want["Comedy"] {
+			t.Errorf("counted %v for all of %s, want %v", counts, tags, want)
+		}
+	}
+
+	profile := admin.Form("POST", "/api/v1/profiles", url.Values{
+		"name":                  {"Kids"},
+		"max_content_rating_id": {family},
+		"pin":                   {"1234"},
+	}).Expect(201).String("id")
+	admin.Form("PUT", "/api/v1/profiles/selected", url.Values{"profile_id": {profile}, "pin": {"1234"}}).Expect(200)
+
+	// Heat is above the limit of the profile, so it is counted no more than
+	// it is browsed.
+	if counts := facets(admin, url.Values{}); counts["Drama"] != 2 || counts["Comedy"] != 1 {
+		t.Errorf("counted %v within the limit, want 2 dramas and 1 comedy", counts)
+	}
+
+	// Facets are paged like tags are, by kind and name.
+	var paged []string
+	for target := "/api/v1/tags/facets?type=movies&limit=1"; target != ""; {
+		page := admin.Send("GET", target).Expect(200).JSON()
+		target, _ = page["next"].(string)
+
+		if page["total"] != float64(2) {
+			t.Errorf("a page counts %v facets, want 2", page["total"])
+		}
+
+		for _, facet := range page["data"].([]any) {
+			paged = append(paged, facet.(map[string]any)["name"].(string))
+		}
+	}
+
+	if len(paged) != 2 || paged[0] != "Comedy" || paged[1] != "Drama" {
+		t.Errorf("paged through %v, want Comedy and Drama", paged)
+	}
+
+	browsed := admin.Send("GET", "/api/v1/movies?tags="+drama).Expect(200).JSON()["data"].([]any)
+	if len(browsed) != 2 {
+		t.Errorf("browsed %d dramas, want 2", len(browsed))
+	}
+}
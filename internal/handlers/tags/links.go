@@ -0,0 +1,206 @@
+package tags
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets and returns the tags attached to a show or movie.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /shows/{id}/tags, /movies/{id}/tags
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the show or movie.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Tags, each returning id, name, kind.
+func ReadParent(
+  w http.ResponseWriter,
+  r *http.Request,
+  db *sql.DB,
+  parentType string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	if err := requireParent(db, parentType, id, log, &functionId); err != nil {
+		return err
+	}
+
+	tags, err := queries.TagsFor(db, []string{id})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if tags[id] == nil {
+		tags[id] = []types.Tag{}
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   tags[id],
+	}.ToClient(w)
+	return nil
+}
+
+// Attaches a tag to a show or movie. Attaching a tag twice has no effect.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /shows/{id}/tags/{tagId}, /movies/{id}/tags/{tagId}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the show or movie.
+//   - tagId       : REQUIRED. UUID of the tag.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Attach(
+  w http.ResponseWriter,
+  r *http.Request,
+  db *sql.DB,
+  parentType string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var tagId string = r.PathValue("tagId")
+	var functionId string = access.RequestId(r)
+	var tagCount int = 0
+
+	if err := requireParent(db, parentType, id, log, &functionId); err != nil {
+		return err
+	}
+
+	if err := db.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			tags
+		WHERE
+			id = ?
+		`,
+		tagId,
+	).Scan(&tagCount); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if tagCount == 0 {
+		return problems.New(problems.NotFound, "No tag could be found with the given id.")
+	}
+
+	if _, err := db.Exec(`
+		INSERT OR IGNORE INTO
+			taggings
+		VALUES
+			(?, ?)
+		`,
+		tagId,
+		id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to attach tag. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Detaches a tag from a show or movie.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /shows/{id}/tags/{tagId}, /movies/{id}/tags/{tagId}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the show or movie.
+//   - tagId       : REQUIRED. UUID of the tag.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Detach(
+  w http.ResponseWriter,
+  r *http.Request,
+  db *sql.DB,
+  parentType string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var tagId string = r.PathValue("tagId")
+	var functionId string = access.RequestId(r)
+
+	if err := requireParent(db, parentType, id, log, &functionId); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(`
+		DELETE FROM
+			taggings
+		WHERE
+			tag_id = ? AND parent_id = ?
+		`,
+		tagId,
+		id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to detach tag. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks that the show or movie that is being tagged exists. Returns a not
+// found problem if it does not.
+func requireParent(
+  db *sql.DB,
+  parentType string,
+  id string,
+  log *logger.Logger,
+  functionId *string,
+) error {
+	var count int = 0
+
+	if err := db.QueryRow(
+		fmt.Sprintf(`
+			SELECT
+				COUNT(*)
+			FROM
+				%s
+			WHERE
+				id = ?
+			`,
+			parentType,
+		),
+		id,
+	).Scan(&count); err != nil {
+		log.Error(*functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if count == 0 {
+		return problems.New(problems.NotFound, "No content could be found with the given id.")
+	}
+
+	return nil
+}
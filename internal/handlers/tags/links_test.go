@@ -0,0 +1,38 @@
+package tags_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestLinks(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	show := admin.CreateShow("Andor")
+	drama := admin.Form("POST", "/api/v1/tags", url.Values{"name": {"Drama"}}).Expect(201).String("id")
+
+	target := "/api/v1/shows/" + show + "/tags"
+	if tags := admin.Send("GET", target).Expect(200).JSON()["data"].([]any); len(tags) != 0 {
+		t.Fatalf("a new show carries %v", tags)
+	}
+
+	// Attaching a tag twice has no effect.
+	admin.Send("PUT", target+"/"+drama, "If-Match", "*").Expect(200)
+	admin.Send("PUT", target+"/"+drama, "If-Match", "*").Expect(200)
+
+	tags := admin.Send("GET", target).Expect(200).JSON()["data"].([]any)
+	if len(tags) != 1 || tags[0].(map[string]any)["id"] != drama {
+		t.Fatalf("the show carries %v, want only Drama", tags)
+	}
+
+	admin.Send("PUT", target+"/nonexistent", "If-Match", "*").Expect(404)
+	admin.Send("GET", "/api/v1/shows/nonexistent/tags").Expect(404)
+	admin.Send("GET", "/api/v1/movies/"+show+"/tags").Expect(404)
+
+	admin.Send("DELETE", target+"/"+drama, "If-Match", "*").Expect(200)
+	if tags := admin.Send("GET", target).Expect(200).JSON()["data"].([]any); len(tags) != 0 {
+		t.Errorf("the show still carries %v", tags)
+	}
+}
@@ -0,0 +1,142 @@
+package tags
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets and returns either a single tag or every tag stored in the database.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /tags/{id}, /tags
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : OPTIONAL. UUID of the tag.
+//
+// # HTTP request query parameters:
+//   - kind        : OPTIONAL. Only return tags of this kind, "genre" or "tag".
+//   - limit       : OPTIONAL. Number of tags per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Tag contents, each returning id, name, kind.
+//   - total       : Number of tags across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var kind string = r.URL.Query().Get("kind")
+	var tag types.Tag = types.Tag{}
+	var functionId string = access.RequestId(r)
+
+	// Return all tags if no ID.
+	if id == "" {
+		var tags []types.Tag = []types.Tag{}
+
+		page, err := queries.NewPage(r, 50, []queries.SortKey{{Expression: "kind"}, {Expression: "name"}, {Expression: "id"}})
+		if err != nil {
+			return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+		}
+
+		total, err := queries.Count(database, "tags", "(? = '' OR kind = ?)", []any{kind, kind})
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		seek, seekArguments := page.Condition()
+
+		rows, err := database.Query(
+			fmt.Sprintf(`
+				SELECT
+					id, name, kind, upload_date, last_modified %s
+				FROM
+					tags
+				WHERE
+					(? = '' OR kind = ?) AND %s
+				%s
+				`,
+				page.Columns(),
+				seek,
+				page.Order(),
+			),
+			append([]any{kind, kind}, seekArguments...)...,
+		)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			var tag types.Tag
+
+			if err := rows.Scan(append([]any{
+				&tag.Id,
+				&tag.Name,
+				&tag.Kind,
+				&tag.UploadDate,
+				&tag.LastModified,
+			}, page.Scan()...)...); err != nil {
+				log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+				return err
+			}
+
+			tags = append(tags, tag)
+		}
+
+		tags = queries.Trim(page, tags)
+
+		responses.Paged(r, tags, total, page.Next(), page.Prev()).ToClient(w)
+		return nil
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			id, name, kind, upload_date, last_modified
+		FROM
+			tags
+		WHERE
+			id = ?
+		`,
+		id,
+	).Scan(
+		&tag.Id,
+		&tag.Name,
+		&tag.Kind,
+		&tag.UploadDate,
+		&tag.LastModified,
+	); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			log.Info(functionId, "No tag found with provided ID")
+			return problems.New(problems.NotFound, "No tag could be found with the given id.")
+		default:
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   tag,
+	}.ToClient(w)
+	return nil
+}
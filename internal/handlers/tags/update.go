@@ -0,0 +1,125 @@
+package tags
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Renames a tag or changes its kind. Values that are not present in the form
+// are left untouched.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /tags/{id}
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - name        : OPTIONAL. New name of the tag.
+//   - kind        : OPTIONAL. New kind of the tag, "genre" or "tag".
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The updated tag.
+func Update(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+	var tag types.Tag = types.Tag{}
+	var existing int = 0
+
+	if err := database.QueryRow(`
+		SELECT
+			id, name, kind, upload_date
+		FROM
+			tags
+		WHERE
+			id = ?
+		`,
+		id,
+	).Scan(
+		&tag.Id,
+		&tag.Name,
+		&tag.Kind,
+		&tag.UploadDate,
+	); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			log.Info(functionId, "No tag found with provided ID")
+			return problems.New(problems.NotFound, "No tag could be found with the given id.")
+		default:
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+	}
+
+	if name := functions.Sanitize(r.FormValue("name")); name != "" {
+		tag.Name = name
+	}
+
+	if kind := r.FormValue("kind"); kind != "" {
+		tag.Kind = kind
+	}
+
+	tag.LastModified = time.Now().Format("2006-01-02 15:04:05")
+
+	if err := validate(&tag); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			tags
+		WHERE
+			name = ? AND id != ?
+		`,
+		tag.Name,
+		tag.Id,
+	).Scan(&existing); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if existing > 0 {
+		return problems.New(problems.Conflict, "A tag with the given name already exists.")
+	}
+
+	if _, err := database.Exec(`
+		UPDATE
+			tags
+		SET
+			name = ?, kind = ?, last_modified = ?
+		WHERE
+			id = ?
+		`,
+		tag.Name,
+		tag.Kind,
+		tag.LastModified,
+		tag.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to update tag. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   tag,
+	}.ToClient(w)
+	return nil
+}
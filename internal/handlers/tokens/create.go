@@ -0,0 +1,139 @@
+package tokens
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Prefix of every API token, which makes leaked tokens easy to recognize.
+const tokenPrefix string = "wfy_"
+
+// Creates a long-lived API token for the logged in user, to be sent in the
+// Authorization header as a Bearer token. The token is only returned once, in a
+// response marked no-store, so that it is not kept for replaying it either.
+//
+// # Specifications:
+//   - Method          : POST
+//   - Endpoint        : /tokens
+//   - Auth?           : True
+//
+// # HTTP request multipart form:
+//   - name            : REQUIRED. What the token is used for.
+//   - scope           : REQUIRED. "read", "upload" or "admin".
+//   - expires_in_days : OPTIONAL. Number of days the token stays valid, never expires if empty.
+//
+// # HTTP response JSON contents:
+//   - status_code     : HTTP status code.
+//   - data            : The created token, including the token itself.
+func Create(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var now time.Time = time.Now()
+	var expiryDate any = nil
+	var apiToken types.ApiToken = types.ApiToken{
+		Id:         uuid.NewString(),
+		Name:       functions.Sanitize(r.FormValue("name")),
+		Scope:      r.FormValue("scope"),
+		UploadDate: now.Format("2006-01-02 15:04:05"),
+	}
+
+	if err := requireFullAccess(r); err != nil {
+		return err
+	}
+
+	if err := validate(&apiToken); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if !access.IsScope(apiToken.Scope) {
+		return problems.New(problems.InvalidRequest, "The scope value must be one of \"read\", \"upload\" or \"admin\".")
+	}
+
+	if days := r.FormValue("expires_in_days"); days != "" {
+		number, err := strconv.Atoi(days)
+		if err != nil || number < 1 || number > 3650 {
+			return problems.New(problems.InvalidRequest, "The expires_in_days value must be a whole number from 1 to 3650.")
+		}
+
+		apiToken.ExpiryDate = now.AddDate(0, 0, number).Format("2006-01-02 15:04:05")
+		expiryDate = apiToken.ExpiryDate
+	}
+
+	token, err := secrets.Token()
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to generate token. %v", err))
+		return err
+	}
+
+	apiToken.Token = tokenPrefix + token
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			api_tokens (id, user_id, name, digest, scope, expiry_date, upload_date)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?)
+		`,
+		apiToken.Id,
+		access.User(r).Id,
+		apiToken.Name,
+		secrets.Digest(apiToken.Token),
+		apiToken.Scope,
+		expiryDate,
+		apiToken.UploadDate,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to insert API token. %v", err))
+		return err
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	responses.Status{
+		Status: 201,
+		Data:   apiToken,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks that the values of an API token are acceptable. Returns the
+// problem to send to the client if they are not.
+func validate(apiToken *types.ApiToken) error {
+	var name string = ""
+	var detail string = ""
+
+	switch {
+	case apiToken.Name == "":
+		name, detail = "name", "The name value is required."
+	case len(apiToken.Name) > 100:
+		name, detail = "name", "The name value was larger than 100 bytes. In UTF-8 encoding, English characters are 1 byte each."
+	default:
+		return nil
+	}
+
+	return problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+}
+
+// Checks that the request may manage API tokens. Returns the problem to send to
+// the client if it may not.
+func requireFullAccess(r *http.Request) error {
+	if access.FullAccess(r) {
+		return nil
+	}
+
+	return problems.New(problems.Forbidden, "API tokens can only be managed with a session or an admin scoped API token.")
+}
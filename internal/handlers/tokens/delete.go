@@ -0,0 +1,63 @@
+package tokens
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Revokes an API token of the logged in user. Requests made with it are rejected
+// from then on.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /tokens/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the token.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Delete(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+
+	if err := requireFullAccess(r); err != nil {
+		return err
+	}
+
+	result, err := database.Exec(`
+		DELETE FROM
+			api_tokens
+		WHERE
+			id = ? AND user_id = ?
+		`,
+		r.PathValue("id"),
+		access.User(r).Id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to delete API token. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No API token of yours could be found with the given id.")
+	}
+
+	log.Info(functionId, "Revoked API token")
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
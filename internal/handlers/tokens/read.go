@@ -0,0 +1,104 @@
+package tokens
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns every API token of the logged in user, newest first. The tokens
+// themselves are never returned again after their creation.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /tokens
+//   - Auth?       : True
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of tokens per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Tokens, each returning id, name, scope, last_used_date and expiry_date.
+//   - total       : Number of tokens across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var apiTokens []types.ApiToken = []types.ApiToken{}
+
+	if err := requireFullAccess(r); err != nil {
+		return err
+	}
+
+	page, err := queries.NewPage(r, 50, []queries.SortKey{{Expression: "upload_date", Descending: true}, {Expression: "id"}})
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+	}
+
+	total, err := queries.Count(database, "api_tokens", "user_id = ?", []any{access.User(r).Id})
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	seek, seekArguments := page.Condition()
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				id, name, scope, COALESCE(last_used_date, ''), COALESCE(expiry_date, ''), upload_date %s
+			FROM
+				api_tokens
+			WHERE
+				user_id = ? AND %s
+			%s
+			`,
+			page.Columns(),
+			seek,
+			page.Order(),
+		),
+		append([]any{access.User(r).Id}, seekArguments...)...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var apiToken types.ApiToken
+
+		if err := rows.Scan(append([]any{
+			&apiToken.Id,
+			&apiToken.Name,
+			&apiToken.Scope,
+			&apiToken.LastUsedDate,
+			&apiToken.ExpiryDate,
+			&apiToken.UploadDate,
+		}, page.Scan()...)...); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to scan row. %v", err))
+			return err
+		}
+
+		apiTokens = append(apiTokens, apiToken)
+	}
+
+	apiTokens = queries.Trim(page, apiTokens)
+
+	responses.Paged(r, apiTokens, total, page.Next(), page.Prev()).ToClient(w)
+	return nil
+}
@@ -0,0 +1,75 @@
+package tokens
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Renames an API token of the logged in user. The scope and expiry of a token
+// can not be changed, create a new token instead.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /tokens/{id}
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - name        : REQUIRED. What the token is used for.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Update(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var apiToken types.ApiToken = types.ApiToken{
+		Id:   r.PathValue("id"),
+		Name: functions.Sanitize(r.FormValue("name")),
+	}
+
+	if err := requireFullAccess(r); err != nil {
+		return err
+	}
+
+	if err := validate(&apiToken); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	result, err := database.Exec(`
+		UPDATE
+			api_tokens
+		SET
+			name = ?
+		WHERE
+			id = ? AND user_id = ?
+		`,
+		apiToken.Name,
+		apiToken.Id,
+		access.User(r).Id,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to update API token. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.NotFound, "No API token of yours could be found with the given id.")
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
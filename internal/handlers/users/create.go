@@ -0,0 +1,141 @@
+package users
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Creates a new user. Requires the manage_users permission.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /users
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - username    : REQUIRED. 3 to 32 letters, digits, dots, dashes or underscores.
+//   - password    : REQUIRED. 8 to 72 bytes.
+//   - role        : OPTIONAL. "admin", "uploader", "viewer" or "guest", defaults to "viewer".
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The created user.
+func Create(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var password string = r.FormValue("password")
+	var existing int = 0
+	var user types.User = types.User{
+		Id:         uuid.NewString(),
+		Username:   r.FormValue("username"),
+		Role:       r.FormValue("role"),
+		UploadDate: time.Now().Format("2006-01-02 15:04:05"),
+	}
+
+	if user.Role == "" {
+		user.Role = "viewer"
+	}
+
+	user.LastModified = user.UploadDate
+
+	if err := validate(&user, password); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			users
+		WHERE
+			username = ?
+		`,
+		user.Username,
+	).Scan(&existing); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if existing > 0 {
+		return problems.New(problems.Conflict, "A user with the given username already exists.")
+	}
+
+	hash, err := secrets.Hash(password)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to hash password. %v", err))
+		return err
+	}
+
+	user.PasswordHash = hash
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			users (id, username, password_hash, role, upload_date, last_modified)
+		VALUES
+			(?, ?, ?, ?, ?, ?)
+		`,
+		user.Id,
+		user.Username,
+		user.PasswordHash,
+		user.Role,
+		user.UploadDate,
+		user.LastModified,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to insert user. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 201,
+		Data:   user,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks that the values of a user, and the password given for it, are
+// acceptable. An empty password is skipped so that users can be updated without
+// changing it. Returns the problem to send to the client if they are not.
+func validate(user *types.User, password string) error {
+	var name string = ""
+	var detail string = ""
+	var validName bool = len(user.Username) >= 3 && len(user.Username) <= 32
+
+	for _, character := range user.Username {
+		switch {
+		case character >= 'a' && character <= 'z':
+		case character >= 'A' && character <= 'Z':
+		case character >= '0' && character <= '9':
+		case character == '.' || character == '-' || character == '_':
+		default:
+			validName = false
+		}
+	}
+
+	switch {
+	case !validName:
+		name, detail = "username", "The username must consist of 3 to 32 letters, digits, dots, dashes or underscores."
+	case password != "" && (len(password) < 8 || len(password) > 72):
+		name, detail = "password", "The password must be 8 to 72 bytes long."
+	case !access.IsRole(user.Role):
+		name, detail = "role", "The role value must be one of \"admin\", \"uploader\", \"viewer\" or \"guest\"."
+	default:
+		return nil
+	}
+
+	return problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+}
@@ -0,0 +1,120 @@
+package users
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/handlers/covers"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Deletes a user along with their profiles, ends all of their sessions and
+// revokes their API tokens. Requires the manage_users permission, and the last
+// admin can not be deleted.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /users/{id}
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the user.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func Delete(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  appDirectory *string,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	user, err := queries.User(database, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			log.Info(functionId, "No user found with provided ID")
+			return problems.New(problems.NotFound, "No user could be found with the given id.")
+		default:
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+	}
+
+	if err := keepAdmin(database, user.Role); err != nil {
+		return err
+	}
+
+	if err := deleteProfiles(database, appDirectory, user.Id); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	for _, statement := range []string{
+		"DELETE FROM sessions WHERE user_id = ?",
+		"DELETE FROM api_tokens WHERE user_id = ?",
+		"DELETE FROM user_identities WHERE user_id = ?",
+		"DELETE FROM recovery_codes WHERE user_id = ?",
+		"DELETE FROM login_challenges WHERE user_id = ?",
+		"DELETE FROM two_factor_failures WHERE user_id = ?",
+		"DELETE FROM progress WHERE viewer_id = ?",
+		"DELETE FROM list_items WHERE viewer_id = ?",
+		"DELETE FROM user_ratings WHERE viewer_id = ?",
+		"DELETE FROM users WHERE id = ?",
+	} {
+		if _, err := database.Exec(statement, user.Id); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to delete user. %v", err))
+			return err
+		}
+	}
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
+
+// Deletes every profile of the user along with their avatars and watch history.
+// Returns the problem to send to the client on failure.
+func deleteProfiles(database *sql.DB, appDirectory *string, userId string) error {
+	var ids []string
+
+	rows, err := database.Query("SELECT id FROM profiles WHERE user_id = ?", userId)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var id string
+
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+
+		ids = append(ids, id)
+	}
+
+	rows.Close()
+
+	for _, id := range ids {
+		if err := covers.Remove(database, appDirectory, id); err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to remove profile avatar.")
+		}
+
+		if err := queries.DeleteProfile(database, id); err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to delete profile.")
+		}
+	}
+
+	return nil
+}
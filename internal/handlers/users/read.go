@@ -0,0 +1,127 @@
+package users
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Gets and returns either every user or a single user. Users with the
+// manage_users permission can see every user, while other users can only see
+// themselves.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /users/{id}, /users
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : OPTIONAL. UUID of the user.
+//
+// # HTTP request query parameters:
+//   - limit       : OPTIONAL. Number of users per page, 50 by default and at most 100.
+//   - after       : OPTIONAL. Cursor of the page to return, from the next link of a page.
+//   - before      : OPTIONAL. Cursor of the page to return, from the prev link of a page.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Users, each returning id, username and role.
+//   - total       : Number of users across every page.
+//   - next, prev  : OPTIONAL. Links to the next and previous pages.
+func Read(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	if id != "" {
+		user, err := queries.User(database, id)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				log.Info(functionId, "No user found with provided ID")
+				return problems.New(problems.NotFound, "No user could be found with the given id.")
+			default:
+				log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+				return err
+			}
+		}
+
+		responses.Status{
+			Status: 200,
+			Data:   user,
+		}.ToClient(w)
+		return nil
+	}
+
+	var users []types.User = []types.User{}
+
+	page, err := queries.NewPage(r, 50, []queries.SortKey{{Expression: "username"}, {Expression: "id"}})
+	if err != nil {
+		return problems.New(problems.InvalidRequest, "The after or before cursor is malformed.")
+	}
+
+	total, err := queries.Count(database, "users", "1 = 1", nil)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	seek, seekArguments := page.Condition()
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				id, username, role, totp_enabled, upload_date, last_modified %s
+			FROM
+				users
+			WHERE
+				%s
+			%s
+			`,
+			page.Columns(),
+			seek,
+			page.Order(),
+		),
+		seekArguments...,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var user types.User
+
+		if err := rows.Scan(append([]any{
+			&user.Id,
+			&user.Username,
+			&user.Role,
+			&user.TwoFactorEnabled,
+			&user.UploadDate,
+			&user.LastModified,
+		}, page.Scan()...)...); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to scan row. %v", err))
+			return err
+		}
+
+		users = append(users, user)
+	}
+
+	users = queries.Trim(page, users)
+
+	responses.Paged(r, users, total, page.Next(), page.Prev()).ToClient(w)
+	return nil
+}
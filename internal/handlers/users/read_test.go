@@ -0,0 +1,52 @@
+package users_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+// Returns a client logged in as a new viewer, along with the id of the viewer.
+func viewer(t *testing.T, server *servertest.Server, admin *servertest.Client) (*servertest.Client, string) {
+	t.Helper()
+
+	credentials := url.Values{"username": {"viewer"}, "password": {"a viewer password"}}
+	id := admin.Form("POST", "/api/v1/users", credentials).Expect(201).String("id")
+
+	client := server.Client(t)
+	client.Form("POST", "/api/v1/auth/login", credentials).Expect(200)
+
+	return client, id
+}
+
+// Users who may not manage users are refused others before any ETag is
+// compared or sent.
+func TestReadOthers(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	adminId := admin.Send("GET", "/api/v1/auth/me").Expect(200).String("id")
+	client, id := viewer(t, server, admin)
+
+	tag := admin.Send("GET", "/api/v1/users/"+adminId).Expect(200).Header.Get("ETag")
+
+	for _, response := range []*servertest.Response{
+		client.Send("GET", "/api/v1/users/"+adminId),
+		client.Send("GET", "/api/v1/users/"+adminId, "If-None-Match", tag),
+		client.Form("PUT", "/api/v1/users/"+adminId, url.Values{"username": {"taken"}}),
+		client.Form("PUT", "/api/v1/users/"+adminId, url.Values{"username": {"taken"}}, "If-Match", `"0"`),
+	} {
+		response.Expect(403)
+
+		if response.Header.Get("ETag") != "" {
+			t.Errorf("a refused request was sent the ETag %s", response.Header.Get("ETag"))
+		}
+	}
+
+	// Users still retrieve and change themselves.
+	tag = client.Send("GET", "/api/v1/users/"+id).Expect(200).Header.Get("ETag")
+	client.Form("PUT", "/api/v1/users/"+id, url.Values{
+		"username":         {"watcher"},
+		"current_password": {"a viewer password"},
+	}, "If-Match", tag).Expect(200)
+}
@@ -0,0 +1,140 @@
+package users
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Returns every role along with the permissions it grants, from most to least
+// privileged.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /roles
+//   - Auth?       : True
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Roles, each returning name and permissions.
+func Roles(
+  w http.ResponseWriter,
+  r *http.Request,
+) error {
+	responses.Status{
+		Status: 200,
+		Data:   access.Roles(),
+	}.ToClient(w)
+	return nil
+}
+
+// Assigns a role to a user. Requires the manage_users permission. The last admin
+// can not be given another role.
+//
+// # Specifications:
+//   - Method      : PUT
+//   - Endpoint    : /users/{id}/role
+//   - Auth?       : True
+//
+// # HTTP request multipart form:
+//   - role        : REQUIRED. "admin", "uploader", "viewer" or "guest".
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The updated user.
+func UpdateRole(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var role string = r.FormValue("role")
+	var functionId string = access.RequestId(r)
+
+	if !access.IsRole(role) {
+		return problems.New(problems.InvalidRequest, "The role value must be one of \"admin\", \"uploader\", \"viewer\" or \"guest\".")
+	}
+
+	user, err := queries.User(database, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			log.Info(functionId, "No user found with provided ID")
+			return problems.New(problems.NotFound, "No user could be found with the given id.")
+		default:
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+	}
+
+	if role != "admin" {
+		if err := keepAdmin(database, user.Role); err != nil {
+			return err
+		}
+	}
+
+	user.Role = role
+	user.LastModified = time.Now().Format("2006-01-02 15:04:05")
+
+	if _, err := database.Exec(`
+		UPDATE
+			users
+		SET
+			role = ?, last_modified = ?
+		WHERE
+			id = ?
+		`,
+		user.Role,
+		user.LastModified,
+		user.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to update user. %v", err))
+		return err
+	}
+
+	log.Info(functionId, fmt.Sprintf("Assigned role %s to user %s", user.Role, user.Username))
+
+	responses.Status{
+		Status: 200,
+		Data:   user,
+	}.ToClient(w)
+	return nil
+}
+
+// Checks that a user with the given role can stop being an admin without
+// leaving the server without any admin. Returns the problem to send to the
+// client if it can not.
+func keepAdmin(database *sql.DB, role string) error {
+	var adminCount int = 0
+
+	if role != "admin" {
+		return nil
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			users
+		WHERE
+			role = 'admin'
+		`,
+	).Scan(&adminCount); err != nil {
+		return err
+	}
+
+	if adminCount > 1 {
+		return nil
+	}
+
+	return problems.New(problems.Conflict, "The server must keep at least one admin.")
+}
@@ -0,0 +1,33 @@
+package users_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestUpdateRoleConditional(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	_, id := viewer(t, server, admin)
+
+	target := "/api/v1/users/" + id
+	before := admin.Send("GET", target).Expect(200).Header.Get("ETag")
+
+	admin.Form("PUT", target+"/role", url.Values{"role": {"uploader"}}).Expect(428)
+	admin.Form("PUT", target+"/role", url.Values{"role": {"uploader"}}, "If-Match", before).Expect(200)
+
+	// The role is part of the user, so the ETag it was based on is stale.
+	admin.Form("PUT", target+"/role", url.Values{"role": {"guest"}}, "If-Match", before).Expect(412)
+	admin.Send("DELETE", target+"/2fa", "If-Match", before).Expect(412)
+
+	after := admin.Send("GET", target).Expect(200).Header.Get("ETag")
+	if after == before {
+		t.Fatal("changing the role left the ETag of the user as it was")
+	}
+
+	admin.Send("DELETE", target+"/2fa").Expect(428)
+	admin.Send("DELETE", target+"/2fa", "If-Match", after).Expect(200)
+	admin.Send("DELETE", "/api/v1/users/unknown/2fa", "If-Match", "*").Expect(404)
+}
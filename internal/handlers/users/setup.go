@@ -0,0 +1,151 @@
+package users
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Reports whether the server still needs its first admin to be created.
+//
+// # Specifications:
+//   - Method      : GET
+//   - Endpoint    : /setup
+//   - Auth?       : False
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : Object with a single "required" boolean.
+func SetupRequired(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var userCount int = 0
+
+	if err := database.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			users
+		`,
+	).Scan(&userCount); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   map[string]bool{"required": userCount == 0},
+	}.ToClient(w)
+	return nil
+}
+
+// Creates the first admin of a freshly installed server and logs them in. Once
+// any user exists, this endpoint refuses every request.
+//
+// # Specifications:
+//   - Method      : POST
+//   - Endpoint    : /setup
+//   - Auth?       : False
+//
+// # HTTP request multipart form:
+//   - username    : REQUIRED. 3 to 32 letters, digits, dots, dashes or underscores.
+//   - password    : REQUIRED. 8 to 72 bytes.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+//   - data        : The created admin.
+func Setup(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var functionId string = access.RequestId(r)
+	var password string = r.FormValue("password")
+	var user types.User = types.User{
+		Id:         uuid.NewString(),
+		Username:   r.FormValue("username"),
+		Role:       "admin",
+		UploadDate: time.Now().Format("2006-01-02 15:04:05"),
+	}
+
+	user.LastModified = user.UploadDate
+
+	if password == "" {
+		return problems.New(problems.InvalidRequest, "The password value is required.")
+	}
+
+	if err := validate(&user, password); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	hash, err := secrets.Hash(password)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to hash password. %v", err))
+		return err
+	}
+
+	user.PasswordHash = hash
+
+	// Inserting only when the table is empty keeps two concurrent setups from
+	// both creating an admin.
+	result, err := database.Exec(`
+		INSERT INTO
+			users (id, username, password_hash, role, upload_date, last_modified)
+		SELECT
+			?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS
+			(SELECT 1 FROM users)
+		`,
+		user.Id,
+		user.Username,
+		user.PasswordHash,
+		user.Role,
+		user.UploadDate,
+		user.LastModified,
+	)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to insert user. %v", err))
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return problems.New(problems.Conflict, "The server has already been set up.")
+	}
+
+	log.Info(functionId, fmt.Sprintf("Created first admin %s", user.Username))
+
+	// Profiles created before the server had accounts go to the first admin.
+	if _, err := database.Exec("UPDATE profiles SET user_id = ? WHERE user_id IS NULL", user.Id); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to adopt existing profiles. %v", err))
+	}
+
+	token, expiry, err := queries.CreateSession(database, user.Id)
+	if err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to create session. %v", err))
+		return err
+	}
+
+	access.SetSessionCookie(w, r, token, expiry)
+
+	responses.Status{
+		Status: 201,
+		Data:   user,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,65 @@
+package users
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Turns two-factor authentication off for a user who lost both their device and
+// their recovery codes, and ends their sessions. Requires the manage_users
+// permission.
+//
+// # Specifications:
+//   - Method      : DELETE
+//   - Endpoint    : /users/{id}/2fa
+//   - Auth?       : True
+//
+// # HTTP request path parameters:
+//   - id          : REQUIRED. UUID of the user.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
+func ResetTwoFactor(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+
+	user, err := queries.User(database, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Info(functionId, "No user found with provided ID")
+			return problems.New(problems.NotFound, "No user could be found with the given id.")
+		}
+
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if err := queries.ResetTwoFactor(database, user.Id); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to reset two-factor authentication. %v", err))
+		return err
+	}
+
+	if _, err := database.Exec("DELETE FROM sessions WHERE user_id = ?", user.Id); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to end sessions. %v", err))
+	}
+
+	log.Info(functionId, fmt.Sprintf("Reset two-factor authentication of user %s", user.Username))
+
+	responses.Status{
+		Status: 200,
+	}.ToClient(w)
+	return nil
+}
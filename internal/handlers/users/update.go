@@ -0,0 +1,160 @@
+package users
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+)
+
+// Updates the username or password of a user. Users can change their own, which
+// requires their current password. Users with the manage_users permission can
+// change those of every user without it. Changing the password ends every other
+// session of the user and revokes their API tokens, except for the one the
+// change was made with. Roles are changed through /users/{id}/role.
+//
+// # Specifications:
+//   - Method           : PUT
+//   - Endpoint         : /users/{id}
+//   - Auth?            : True
+//
+// # HTTP request multipart form:
+//   - username         : OPTIONAL. New username.
+//   - password         : OPTIONAL. New password.
+//   - current_password : OPTIONAL. Current password, required when users update themselves.
+//
+// # HTTP response JSON contents:
+//   - status_code      : HTTP status code.
+//   - data             : The updated user.
+func Update(
+  w http.ResponseWriter,
+  r *http.Request,
+  database *sql.DB,
+  log *logger.Logger,
+) error {
+	var id string = r.PathValue("id")
+	var functionId string = access.RequestId(r)
+	var password string = r.FormValue("password")
+	var isManager bool = access.Allowed(r, access.ManageUsers)
+	var existing int = 0
+
+	if !access.FullAccess(r) {
+		return problems.New(problems.Forbidden, "Users can only be updated with a session or an admin scoped API token.")
+	}
+
+	user, err := queries.User(database, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			log.Info(functionId, "No user found with provided ID")
+			return problems.New(problems.NotFound, "No user could be found with the given id.")
+		default:
+			log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+			return err
+		}
+	}
+
+	// Users provisioned through single sign-on have no password to confirm.
+	if !isManager && user.PasswordHash != "" && !secrets.Verify(user.PasswordHash, r.FormValue("current_password")) {
+		log.Info(functionId, "Rejected user change with a wrong password")
+		return problems.New(problems.Forbidden, "The current_password value is missing or wrong.")
+	}
+
+	if username := r.FormValue("username"); username != "" {
+		user.Username = username
+	}
+
+	if err := validate(user, password); err != nil {
+		log.Error(functionId, err.Error())
+		return err
+	}
+
+	if err := database.QueryRow(`
+		SELECT
+			COUNT(*)
+		FROM
+			users
+		WHERE
+			username = ? AND id != ?
+		`,
+		user.Username,
+		user.Id,
+	).Scan(&existing); err != nil {
+		log.Error(functionId, fmt.Sprintf("An unknown error occurred. %v", err))
+		return err
+	}
+
+	if existing > 0 {
+		return problems.New(problems.Conflict, "A user with the given username already exists.")
+	}
+
+	if password != "" {
+		hash, err := secrets.Hash(password)
+		if err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to hash password. %v", err))
+			return err
+		}
+
+		user.PasswordHash = hash
+
+		if _, err := database.Exec(`
+			DELETE FROM
+				sessions
+			WHERE
+				user_id = ? AND id != ?
+			`,
+			user.Id,
+			secrets.Digest(access.SessionToken(r)),
+		); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to end sessions. %v", err))
+			return err
+		}
+
+		if _, err := database.Exec(`
+			DELETE FROM
+				api_tokens
+			WHERE
+				user_id = ? AND digest != ?
+			`,
+			user.Id,
+			secrets.Digest(access.BearerToken(r)),
+		); err != nil {
+			log.Error(functionId, fmt.Sprintf("Failed to revoke API tokens. %v", err))
+			return err
+		}
+	}
+
+	user.LastModified = time.Now().Format("2006-01-02 15:04:05")
+
+	if _, err := database.Exec(`
+		UPDATE
+			users
+		SET
+			username = ?, password_hash = ?, role = ?, last_modified = ?
+		WHERE
+			id = ?
+		`,
+		user.Username,
+		user.PasswordHash,
+		user.Role,
+		user.LastModified,
+		user.Id,
+	); err != nil {
+		log.Error(functionId, fmt.Sprintf("Failed to update user. %v", err))
+		return err
+	}
+
+	responses.Status{
+		Status: 200,
+		Data:   user,
+	}.ToClient(w)
+	return nil
+}
@@ -0,0 +1,35 @@
+package users_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestUpdatePasswordRevokesCredentials(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	id := admin.Send("GET", "/api/v1/auth/me").Expect(200).String("id")
+
+	clients := map[string]*servertest.Client{}
+	for _, name := range []string{"kept", "revoked"} {
+		token := admin.Form("POST", "/api/v1/tokens", url.Values{"name": {name}, "scope": {"admin"}}).Expect(201).String("token")
+
+		clients[name] = server.Client(t)
+		clients[name].Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// Changing a username leaves the credentials alone.
+	admin.Form("PUT", "/api/v1/users/"+id, url.Values{"username": {"root"}}, "If-Match", "*").Expect(200)
+	clients["revoked"].Send("GET", "/api/v1/auth/me").Expect(200)
+
+	clients["kept"].Form("PUT", "/api/v1/users/"+id, url.Values{
+		"password":         {"a new password"},
+		"current_password": {servertest.AdminPassword},
+	}, "If-Match", "*").Expect(200)
+
+	clients["kept"].Send("GET", "/api/v1/auth/me").Expect(200)
+	clients["revoked"].Send("GET", "/api/v1/auth/me").Expect(401)
+	admin.Send("GET", "/api/v1/auth/me").Expect(401)
+}
@@ -4,61 +4,56 @@ import (
 	"database/sql"
 	"net/http"
 	"path"
+	"strconv"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
 	"github.com/andrewdotjs/watchify-server/internal/types"
 	"github.com/andrewdotjs/watchify-server/internal/upload"
-	"github.com/google/uuid"
 )
 
 // Allows the client to upload a video to the file system and store its information to
 // the database.
 //
 // # Specifications:
-//   - Method      : POST
-//   - Endpoint    : /videos
-//   - Auth?       : False
+//   - Method        : POST
+//   - Endpoint      : /videos
+//   - Auth?         : True
 //
-// # HTTP form data:
-//   - series-id   : REQUIRED. Series id.
-//   - title       : REQUIRED. Video title.
+// # HTTP request multipart form:
+//   - video         : REQUIRED. Uploaded video file.
+//   - show-id       : REQUIRED. UUID of the series.
+//   - season_number : OPTIONAL. Season of the episode, 1 by default.
 //
 // # HTTP response JSON contents:
-//   - status_code : HTTP status code.
-//   - message     : If error, message detailing the error.
+//   - status_code   : HTTP status code.
 func Create(
   w http.ResponseWriter,
   r *http.Request,
   database *sql.DB,
   appDirectory *string,
   log *logger.Logger,
-) {
+) error {
 	var video types.Episode
-	var functionId string = uuid.NewString()
+	var functionId string = access.RequestId(r)
 
 	// Error handling if form data exceeds 1GB
 	if err := r.ParseMultipartForm(1 << 30); err != nil {
-		responses.Status{
-			Status:  400,
-			Message: "Did the file exceed 1GB?",
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "Did the file exceed 1GB?")
 	}
 
 	// Get the file from the request
 	file, handler, err := r.FormFile("video")
 	if err != nil {
-		responses.Status{
-			Status:  400,
-			Message: "Unable to get file from form. Was fileName set to video?",
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "Unable to get file from form. Was fileName set to video?")
 	}
 
 	defer file.Close()
 
 	video.ParentId = r.FormValue("show-id")
+	video.SeasonNumber, _ = strconv.Atoi(r.FormValue("season_number"))
 
 	uploadDirectory := path.Join(*appDirectory, "storage", "videos")
 	upload.Episode(handler, &video, database, &uploadDirectory, log, &functionId)
@@ -66,4 +61,5 @@ func Create(
 	responses.Status{
 		Status: 201,
 	}.ToClient(w)
+	return nil
 }
@@ -8,60 +8,81 @@ import (
 	"os"
 	"path"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
-	"github.com/google/uuid"
 )
 
-// Deletes a video from the database and file system.
+// Deletes an episode from the database along with its credits, the progress,
+// history and ratings of every viewer, and its video file.
 //
 // # Specifications:
-//   - Method   : DELETE
-//   - Endpoint : /videos/{id}
-//   - Auth?    : False
+//   - Method      : DELETE
+//   - Endpoint    : /videos/{id}
+//   - Auth?       : True
 //
 // # HTTP request path parameters:
-//   - id : REQUIRED. UUID of the video.
+//   - id          : REQUIRED. UUID of the episode.
+//
+// # HTTP response JSON contents:
+//   - status_code : HTTP status code.
 func Delete(
   w http.ResponseWriter,
   r *http.Request,
   database *sql.DB,
   appDirectory *string,
   log *logger.Logger,
-) {
-	var fileName string = ""
+) error {
+	var fileName string
 	var id string = r.PathValue("id")
-	var functionId string = uuid.NewString()
+	var functionId string = access.RequestId(r)
+
+	if err := database.QueryRow("SELECT file_name FROM episodes WHERE id=?", id).Scan(&fileName); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return problems.New(problems.NotFound, "No video could be found with the given id.")
+		}
+
+		return problems.Wrap(problems.Internal, err, "Error reading video information from the database.")
+	}
+
+	transaction, err := database.Begin()
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Error deleting video information from the database.")
+	}
+
+	defer transaction.Rollback()
+
+	// Its credits, and how far every viewer got into it and what they rated it,
+	// go along with the episode.
+	for _, statement := range []string{
+		"DELETE FROM credits WHERE parent_id=?",
+		"DELETE FROM progress WHERE media_id=?",
+		"DELETE FROM watch_history WHERE media_id=?",
+		"DELETE FROM user_ratings WHERE media_id=?",
+		"DELETE FROM episodes WHERE id=?",
+	} {
+		if _, err := transaction.Exec(statement, id); err != nil {
+			return problems.Wrap(problems.Internal, err, "Error deleting video information from the database.")
+		}
+	}
 
-	if _, err := database.Exec(
-	  `
-  	  DELETE FROM
-  			videos
-  		WHERE
-  		  id=?;
-	  `,
-		id); err != nil {
-		responses.Status{
-			Status:  500,
-			Message: "Error deleting video information from the database.",
-		}.ToClient(w)
-		return
+	if err := transaction.Commit(); err != nil {
+		return problems.Wrap(problems.Internal, err, "Error deleting video information from the database.")
 	}
 
+	// The episode is gone either way, so a file that is already missing is only
+	// worth logging.
 	if err := os.Remove(path.Join(*appDirectory, "storage", "videos", fileName)); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
-			defer database.Close()
-			log.Error(functionId, fmt.Sprintf("%v", err))
+			return problems.Wrap(problems.Internal, err, "Error removing video file.")
 		}
 
-		responses.Status{
-			Status:  500,
-			Message: "Error removing video file.",
-		}.ToClient(w)
-		return
+		log.Error(functionId, fmt.Sprintf("The video file %s of the deleted episode was already missing.", fileName))
 	}
 
 	responses.Status{
 		Status: 200,
 	}.ToClient(w)
+	return nil
 }
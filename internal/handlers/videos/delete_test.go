@@ -0,0 +1,50 @@
+package videos_test
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestDelete(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	show := admin.CreateShow("Andor")
+
+	var id, fileName string
+	if err := server.DB.QueryRow("SELECT id, file_name FROM episodes WHERE parent_id = ?", show).Scan(&id, &fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	admin.Form("PUT", "/api/v1/progress/"+id, url.Values{"position": {"60"}, "duration": {"600"}}).Expect(200)
+
+	admin.Send("DELETE", "/api/v1/videos/"+id).Expect(428)
+	admin.Send("DELETE", "/api/v1/videos/"+id, "If-Match", "*").Expect(200)
+
+	if _, err := os.Stat(path.Join(server.AppDirectory, "storage", "videos", fileName)); !os.IsNotExist(err) {
+		t.Errorf("the video file is still stored: %v", err)
+	}
+
+	for _, table := range []string{"episodes WHERE id = ?", "progress WHERE media_id = ?"} {
+		var count int
+		if err := server.DB.QueryRow("SELECT COUNT(*) FROM "+table, id).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+
+		if count != 0 {
+			t.Errorf("%d rows of %s are left", count, table)
+		}
+	}
+
+	if episode := admin.Send("GET", "/api/v1/videos/"+id).Expect(200).Data(); episode != nil {
+		t.Errorf("the deleted episode is still returned: %v", episode)
+	}
+
+	admin.Send("DELETE", "/api/v1/videos/"+id, "If-Match", "*").Expect(404)
+
+	// The show is left alone.
+	admin.Send("GET", "/api/v1/shows/"+show).Expect(200)
+}
@@ -3,9 +3,12 @@ package videos
 import (
 	"database/sql"
 	"errors"
-	"log"
+	"fmt"
 	"net/http"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
 	"github.com/andrewdotjs/watchify-server/internal/responses"
 	"github.com/andrewdotjs/watchify-server/internal/types"
 )
@@ -15,111 +18,98 @@ import (
 // # Specifications:
 //   - Method      : GET
 //   - Endpoint    : /videos/{id}
-//   - Auth?       : False
+//   - Auth?       : True
 //
 // # HTTP request path parameters:
 //   - id          : REQUIRED. UUID of the video.
 //
 // # HTTP response JSON contents:
 //   - status_code : HTTP status code.
-//   - message     : If error, message detailing the error.
-//   - data        : id, series_id, title (if empty, json data is empty)
+//   - data        : id, series_id, season_number, episode_number, progress (if empty, json data is empty)
 func Read(
   w http.ResponseWriter,
   r *http.Request,
   database *sql.DB,
-) {
+) error {
 	var id string = r.PathValue("id")
 	var video types.Episode = types.Episode{Id: id}
 
 	if id == "" {
-		responses.Error{
-			Type:     "null",
-			Title:    "Invalid API request",
-			Status:   400,
-			Detail:   "No id was provided in the url params.",
-			Instance: r.URL.Path,
-		}.ToClient(w)
-		return
+		return problems.New(problems.InvalidRequest, "No id was provided in the url params.")
 	}
 
-	if err := database.QueryRow(`
-  	SELECT
-			parent_id, episode_number
-  	FROM
-			episodes
-  	WHERE
-			id=?
-  	`,
-		video.Id,
+	condition, conditionArguments := access.EpisodeCondition(r)
+
+	if err := database.QueryRow(
+		fmt.Sprintf(`
+	  	SELECT
+				parent_id, season_number, episode_number, COALESCE(%s, '')
+	  	FROM
+				episodes
+	  	WHERE
+				id=? AND %s
+	  	`,
+			access.EpisodeRating,
+			condition,
+		),
+		append([]any{video.Id}, conditionArguments...)...,
 	).Scan(
 		&video.ParentId,
+		&video.SeasonNumber,
 		&video.EpisodeNumber,
+		&video.ContentRatingId,
 	); err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
-			defer database.Close()
-			log.Fatalf("ERR : %v", err)
+			return problems.Wrap(problems.Internal, err, "Failed to retrieve the video.")
 		}
 
 		responses.Status{
 			Status: 200,
 			Data:   nil,
 		}.ToClient(w)
-		return
+		return nil
 	}
 
-	if video.ParentId != "" {
-		rows, err := database.Query(`
-	  SELECT
-			id, episode_number
-		FROM
-			episodes
-		WHERE
-			parent_id=?
-		AND
-			(episode_number=? OR episode_number=?)
-		`,
-			video.ParentId,
-			video.EpisodeNumber-1,
-			video.EpisodeNumber+1,
-		)
-
-		if err != nil {
-			if !errors.Is(err, sql.ErrNoRows) {
-				defer database.Close()
-				log.Fatalf("ERR : %v", err)
-			}
+	if ratings, err := queries.ContentRatings(database); err != nil {
+		return err
+	} else {
+		video.ContentRating = ratings[video.ContentRatingId]
+	}
 
-			responses.Status{
-				Status: 200,
-				Data:   video,
-			}.ToClient(w)
-			return
-		}
+	if credits, err := queries.CreditsFor(database, []string{video.Id}); err != nil {
+		return err
+	} else {
+		video.Credits = credits[video.Id]
+	}
 
-		for rows.Next() {
-			var id string
-			var episodeNumber int
+	if progress, err := queries.ProgressFor(database, access.Viewer(r), []string{video.Id}); err != nil {
+		return err
+	} else {
+		video.Progress = progress[video.Id]
+	}
 
-			if err := rows.Scan(
-				&id,
-				&episodeNumber,
-			); err != nil {
-				defer database.Close()
-				log.Fatalf("ERR : %v", err)
-			}
+	if video.ParentId != "" {
+		next, err := queries.NextEpisode(database, video.ParentId, video.SeasonNumber, video.EpisodeNumber, condition, conditionArguments)
+		if err != nil {
+			return err
+		}
 
-			videoAdjacent := map[string]string{
-				"id":  id,
-				"url": "/videos/" + id,
-			}
+		previous, err := queries.PreviousEpisode(database, video.ParentId, video.SeasonNumber, video.EpisodeNumber, condition, conditionArguments)
+		if err != nil {
+			return err
+		}
 
-			if episodeNumber == video.EpisodeNumber+1 {
-				video.NextEpisode = videoAdjacent
+		if next != "" {
+			video.NextEpisode = map[string]string{
+				"id":  next,
+				"url": "/videos/" + next,
 			}
+		}
 
-			if episodeNumber == video.EpisodeNumber-1 {
-				video.PreviousEpisode = videoAdjacent
+		if previous != "" {
+			video.PreviousEpisode = map[string]string{
+				"id":  previous,
+				"url": "/videos/" + previous,
 			}
 		}
 	}
@@ -128,4 +118,5 @@ func Read(
 		Status: 200,
 		Data:   video,
 	}.ToClient(w)
+	return nil
 }
@@ -11,17 +11,26 @@ func (thisLogger *Logger) Rename() {
  	var currentDate string = time.Now().Format("2006-01-02 150405")
 	var newPath string = path.Join(thisLogger.path, "..", currentDate+".log")
 
+	// Loggers that were never initialized only log to the console.
+	if thisLogger.path == "" {
+		return
+	}
+
 	if _, err := os.Stat(newPath); !os.IsNotExist(err) {
 	  return
 	}
 
+	// Renaming happens while requests are logged, so failing to rename keeps
+	// logging to the current file rather than stopping the server.
 	if err := os.Rename(thisLogger.path, newPath); err != nil {
-		log.Fatalf("ERR : %v", err)
+		log.Printf("ERR : %v", err)
+		return
 	}
 
 	file, err := os.OpenFile(newPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
 	if err != nil {
-		log.Fatalf("error opening file: %v", err)
+		log.Printf("error opening file: %v", err)
+		return
 	}
 
 	thisLogger.path = newPath
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Routes that can be used without logging in, in the same format as the
+// patterns registered on the mux.
+var publicRoutes = map[string]bool{
+	"GET /api/v1/setup":              true,
+	"POST /api/v1/setup":             true,
+	"POST /api/v1/auth/login":        true,
+	"POST /api/v1/auth/login/2fa":    true,
+	"GET /api/v1/auth/oidc/login":    true,
+	"GET /api/v1/auth/oidc/callback": true,
+	"GET /api/openapi.json":          true,
+}
+
+// Middleware that rejects every request that does not carry a valid API token
+// in the Authorization header or a valid session cookie, except for those made
+// to public routes. The authenticated user is stored in the request context,
+// along with the scope of the token if one was used.
+func Authenticate(next http.Handler, db *sql.DB, log *logger.Logger) http.Handler {
+	return Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		var token string = access.SessionToken(r)
+
+		if publicRoutes[r.Method+" "+r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return nil
+		}
+
+		if bearer := access.BearerToken(r); bearer != "" {
+			user, scope, err := queries.TokenUser(db, bearer)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+					return problems.New(problems.Unauthorized, "The API token is unknown, revoked or expired.")
+				}
+
+				return problems.Wrap(problems.Internal, err, "Failed to retrieve API token.")
+			}
+
+			if err := requireTwoFactor(db, user, r); err != nil {
+				return err
+			}
+
+			ctx := access.WithScope(access.WithUser(r.Context(), user), scope)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return nil
+		}
+
+		if token == "" {
+			return problems.New(problems.Unauthorized, "You must be logged in or send an API token to use this endpoint.")
+		}
+
+		user, err := queries.SessionUser(db, token)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				access.ClearSessionCookie(w, r)
+				return problems.New(problems.Unauthorized, "The session has expired or was ended. Log in again.")
+			}
+
+			return problems.Wrap(problems.Internal, err, "Failed to retrieve session.")
+		}
+
+		if err := requireTwoFactor(db, user, r); err != nil {
+			return err
+		}
+
+		next.ServeHTTP(w, r.WithContext(access.WithUser(r.Context(), user)))
+		return nil
+	})
+}
+
+// Checks the user against the require_two_factor setting. Users it applies to
+// that have not enabled two-factor authentication yet can only reach the auth
+// routes, which let them enroll. Returns the problem to send to the client if
+// the request is rejected.
+func requireTwoFactor(db *sql.DB, user *types.User, r *http.Request) error {
+	if user.TwoFactorEnabled || strings.HasPrefix(r.URL.Path, "/api/v1/auth/") {
+		return nil
+	}
+
+	required, err := queries.TwoFactorRequired(db, user.Role)
+	if err != nil {
+		return problems.Wrap(problems.Internal, err, "Failed to check whether two-factor authentication is required.")
+	}
+
+	if !required {
+		return nil
+	}
+
+	return problems.New(problems.TwoFactorRequired, "Two-factor authentication is required for your role. Enable it through /api/v1/auth/2fa first.")
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Middleware that only lets requests through when the role of the
+// authenticated user, and the scope of their API token if they used one, grant
+// the permission. Meant to wrap single routes.
+func Authorize(permission access.Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !access.Allowed(r, permission) {
+			responses.WriteProblem(w, r, problems.New(problems.Forbidden, fmt.Sprintf("Your role or API token does not grant the %s permission.", permission)))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Middleware for the routes of a single user, identified by the id path value,
+// that only lets requests through when they were made by that user, or when
+// the permission is granted as Authorize checks it. Meant to wrap Versioned, so
+// that others are refused before any ETag is compared or sent.
+func AuthorizeSelf(permission access.Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := access.User(r)
+
+		if (user == nil || user.Id != r.PathValue("id")) && !access.Allowed(r, permission) {
+			responses.WriteProblem(w, r, problems.New(problems.Forbidden, fmt.Sprintf("Only the user itself or users granted the %s permission can use this endpoint.", permission)))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
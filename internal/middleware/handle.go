@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Adapts a handler that returns the error it failed with, rather than sending
+// it, into an http.Handler. Errors are sent to the client as problems, and the
+// causes of internal ones, which the client never sees, are logged under the
+// request id.
+func Handle(log *logger.Logger, handler func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := handler(w, r)
+		if err == nil {
+			return
+		}
+
+		var problem *problems.Problem
+		if !errors.As(err, &problem) {
+			log.Error(access.RequestId(r), fmt.Sprintf("Unexpected error. %v", err))
+		} else if problem.Type.Status >= 500 && problem.Err != nil {
+			log.Error(access.RequestId(r), fmt.Sprintf("%s %v", problem.Detail, problem.Err))
+		}
+
+		responses.WriteProblem(w, r, err)
+	})
+}
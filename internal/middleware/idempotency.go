@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+)
+
+// Longest Idempotency-Key accepted.
+const maximumIdempotencyKey int = 255
+
+// Largest response kept to be replayed.
+const maximumReplayedBody int = 1 << 20
+
+// Headers of responses that are replayed along with their status and body.
+// Others, such as Set-Cookie, are only ever sent with the original response.
+var replayedHeaders = []string{"Location", "ETag", "Last-Modified", "Content-Language"}
+
+// Middleware that makes POST requests sent with an Idempotency-Key header safe
+// to retry. The first request with a key of a user claims it, and its response
+// is kept for a day, during which retries get the same response again, marked
+// with the Idempotent-Replayed header, without running the handler.
+//
+// Reusing a key for a different request, or while the request that claimed it
+// is still being processed, is a conflict. Server errors release the key, so
+// that the request can be retried. Responses larger than maximumReplayedBody,
+// or marked with Cache-Control: no-store, as those carrying credentials are,
+// are not kept, so retries of their requests are a conflict as well. Requests
+// without a user are left alone, as there is no one to keep their keys apart
+// from those of others.
+func Idempotency(next http.Handler, db *sql.DB, log *logger.Logger) http.Handler {
+	return Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		var key string = r.Header.Get("Idempotency-Key")
+		var user = access.User(r)
+
+		if r.Method != http.MethodPost || key == "" || user == nil {
+			next.ServeHTTP(w, r)
+			return nil
+		}
+
+		if len(key) > maximumIdempotencyKey {
+			detail := fmt.Sprintf("The Idempotency-Key was longer than %d bytes.", maximumIdempotencyKey)
+			return problems.New(problems.InvalidRequest, detail, problems.Param("Idempotency-Key", detail))
+		}
+
+		// The body is kept in a temporary file, which the handler reads in its
+		// stead, so that it can be fingerprinted first.
+		body, err := os.CreateTemp("", "watchify-idempotency-*")
+		if err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to store the request body.")
+		}
+
+		defer os.Remove(body.Name())
+		defer body.Close()
+
+		if _, err := io.Copy(body, r.Body); err != nil {
+			return problems.Wrap(problems.InvalidRequest, err, "The request body could not be read.")
+		}
+
+		fingerprint, err := requestFingerprint(r, body)
+		if err != nil {
+			return problems.New(problems.InvalidRequest, fmt.Sprintf("The request body could not be read. %v", err))
+		}
+
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to store the request body.")
+		}
+		r.Body = body
+
+		claimed, err := queries.ClaimIdempotencyKey(db, user.Id, key, fingerprint)
+		if err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to claim the Idempotency-Key.")
+		}
+
+		switch {
+		case claimed == nil:
+		case claimed.Fingerprint != fingerprint:
+			return problems.New(problems.Conflict, "The Idempotency-Key was used for a different request already.")
+		case claimed.Status == 0:
+			return problems.New(problems.Conflict, "A request with this Idempotency-Key is still being processed. Retry it later.")
+		case !claimed.Replayable:
+			return problems.New(problems.Conflict, fmt.Sprintf(
+				"A request with this Idempotency-Key was answered with status %d already, but its response can't be replayed.",
+				claimed.Status,
+			))
+		default:
+			log.Info(access.RequestId(r), fmt.Sprintf("Replaying the response to Idempotency-Key %s", key))
+			for _, name := range replayedHeaders {
+				if value := claimed.Header.Get(name); value != "" {
+					w.Header().Set(name, value)
+				}
+			}
+			w.Header().Set("Content-Type", claimed.ContentType)
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(claimed.Status)
+			w.Write(claimed.Body)
+			return nil
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+
+		// A panic of the handler releases the key too.
+		defer func() {
+			if !completed {
+				if err := queries.ReleaseIdempotencyKey(db, user.Id, key); err != nil {
+					log.Error(access.RequestId(r), fmt.Sprintf("Failed to release the Idempotency-Key. %v", err))
+				}
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status >= 500 {
+			return nil
+		}
+
+		response := queries.IdempotentRequest{
+			Status:      recorder.status,
+			Replayable:  recorder.body.Len() <= maximumReplayedBody && !noStore(w.Header()),
+			ContentType: w.Header().Get("Content-Type"),
+			Header:      http.Header{},
+			Body:        append([]byte{}, recorder.body.Bytes()...),
+		}
+
+		for _, name := range replayedHeaders {
+			if value := w.Header().Get(name); value != "" {
+				response.Header.Set(name, value)
+			}
+		}
+
+		if err := queries.CompleteIdempotencyKey(db, user.Id, key, response); err != nil {
+			log.Error(access.RequestId(r), fmt.Sprintf("Failed to keep the response to the Idempotency-Key. %v", err))
+			return nil
+		}
+
+		completed = true
+		return nil
+	})
+}
+
+// Returns a digest of the method, path, query and body of the request. Parts of
+// multipart bodies are digested rather than the body itself, since clients pick
+// a new boundary every time they send one.
+func requestFingerprint(r *http.Request, body io.ReadSeeker) (string, error) {
+	var digest hash.Hash = sha256.New()
+
+	fmt.Fprintf(digest, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	mediaType, parameters, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		fmt.Fprintf(digest, "%s\n", mediaType)
+		_, err := io.Copy(digest, body)
+		return hex.EncodeToString(digest.Sum(nil)), err
+	}
+
+	reader := multipart.NewReader(body, parameters["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		partDigest := sha256.New()
+		if _, err := io.Copy(partDigest, part); err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(digest, "%q %q %x\n", part.FormName(), part.FileName(), partDigest.Sum(nil))
+	}
+
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// Reports whether the response may not be stored, according to its
+// Cache-Control header.
+func noStore(header http.Header) bool {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Passes a response through, keeping its status and body to be replayed.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (recorder *idempotencyRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *idempotencyRecorder) Write(data []byte) (int, error) {
+	if recorder.body.Len() <= maximumReplayedBody {
+		recorder.body.Write(data)
+	}
+
+	return recorder.ResponseWriter.Write(data)
+}
@@ -0,0 +1,219 @@
+package middleware_test
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/middleware"
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+	"github.com/andrewdotjs/watchify-server/internal/totp"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Serves handler behind Idempotency for a user, and returns a function sending
+// it a POST request with the given Idempotency-Key.
+func idempotent(db *sql.DB, handler http.HandlerFunc) func(key string) *httptest.ResponseRecorder {
+	var log logger.Logger
+	var user *types.User = &types.User{Id: "idempotent-user"}
+
+	served := middleware.Idempotency(handler, db, &log)
+
+	return func(key string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest("POST", "/api/v1/tags", strings.NewReader("name=Drama"))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Header.Set("Idempotency-Key", key)
+		request = request.WithContext(access.WithUser(request.Context(), user))
+
+		recorder := httptest.NewRecorder()
+		served.ServeHTTP(recorder, request)
+		return recorder
+	}
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+
+	first := admin.Form("POST", "/api/v1/tags", url.Values{"name": {"Drama"}}, "Idempotency-Key", "drama").Expect(201)
+	retry := admin.Form("POST", "/api/v1/tags", url.Values{"name": {"Drama"}}, "Idempotency-Key", "drama").Expect(201)
+
+	if retry.Header.Get("Idempotent-Replayed") != "true" {
+		t.Error("the retry was not replayed")
+	}
+
+	if string(retry.Body) != string(first.Body) {
+		t.Errorf("replayed %s, want %s", retry.Body, first.Body)
+	}
+
+	var tags int
+	if err := server.DB.QueryRow("SELECT COUNT(*) FROM tags").Scan(&tags); err != nil {
+		t.Fatal(err)
+	}
+
+	if tags != 1 {
+		t.Errorf("%d tags were created, want 1", tags)
+	}
+
+	admin.Form("POST", "/api/v1/tags", url.Values{"name": {"Comedy"}}, "Idempotency-Key", "drama").Expect(409)
+}
+
+func TestIdempotencyReplayedHeaders(t *testing.T) {
+	server := servertest.New(t)
+	calls := 0
+
+	send := idempotent(server.DB, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/v1/tags/drama")
+		w.Header().Set("ETag", `"1"`)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+		w.WriteHeader(201)
+		io.WriteString(w, `{"status": 201}`)
+	})
+
+	send("drama")
+	retry := send("drama")
+
+	if calls != 1 {
+		t.Fatalf("the handler ran %d times, want 1", calls)
+	}
+
+	for name, want := range map[string]string{
+		"Content-Type":        "application/json",
+		"Location":            "/api/v1/tags/drama",
+		"ETag":                `"1"`,
+		"Set-Cookie":          "",
+		"Idempotent-Replayed": "true",
+	} {
+		if got := retry.Header().Get(name); got != want {
+			t.Errorf("replayed %s %q, want %q", name, got, want)
+		}
+	}
+
+	if retry.Code != 201 || retry.Body.String() != `{"status": 201}` {
+		t.Errorf("replayed %d %s", retry.Code, retry.Body)
+	}
+}
+
+func TestIdempotencyUnreplayable(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		respond http.HandlerFunc
+	}{
+		{"no-store", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "private, no-store")
+			w.WriteHeader(201)
+			io.WriteString(w, "secret")
+		}},
+		{"oversized", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(201)
+			io.WriteString(w, strings.Repeat("secret", 1<<18))
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			server := servertest.New(t)
+			calls := 0
+
+			send := idempotent(server.DB, func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				test.respond(w, r)
+			})
+
+			if status := send("key").Code; status != 201 {
+				t.Fatalf("the request was answered with %d", status)
+			}
+
+			// The key stays claimed, so that retries are not run again.
+			if retry := send("key"); retry.Code != 409 {
+				t.Errorf("the retry was answered with %d, want 409", retry.Code)
+			}
+
+			if calls != 1 {
+				t.Errorf("the handler ran %d times, want 1", calls)
+			}
+
+			var kept int
+			if err := server.DB.QueryRow("SELECT length(body) FROM idempotency_keys").Scan(&kept); err != nil {
+				t.Fatal(err)
+			}
+
+			if kept != 0 {
+				t.Errorf("%d bytes of the response were kept", kept)
+			}
+		})
+	}
+}
+
+// Credentials are sent once, so none of them may end up among the responses
+// kept for retries.
+func TestIdempotencyKeepsNoCredentials(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+
+	token := admin.Form("POST", "/api/v1/tokens", url.Values{
+		"name":  {"Backups"},
+		"scope": {"read"},
+	}, "Idempotency-Key", "token").Expect(201).String("token")
+
+	secret := admin.Send("POST", "/api/v1/auth/2fa", "Idempotency-Key", "enroll").Expect(200).String("secret")
+
+	code, err := totp.Code(secret, totp.Counter(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confirmed := admin.Form("POST", "/api/v1/auth/2fa/confirm", url.Values{"code": {code}}, "Idempotency-Key", "confirm").Expect(200)
+	recoveryCodes := confirmed.JSON()["data"].([]any)
+
+	regenerated := admin.Form("POST", "/api/v1/auth/2fa/recovery-codes", url.Values{
+		"code": {recoveryCodes[0].(string)},
+	}, "Idempotency-Key", "regenerate").Expect(200)
+
+	credentials := []string{token, secret}
+	for _, codes := range [][]any{recoveryCodes, regenerated.JSON()["data"].([]any)} {
+		for _, code := range codes {
+			credentials = append(credentials, code.(string))
+		}
+	}
+
+	rows, err := server.DB.Query("SELECT key, content_type, header, body FROM idempotency_keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	keys := 0
+	for rows.Next() {
+		var key, contentType, header string
+		var body []byte
+
+		if err := rows.Scan(&key, &contentType, &header, &body); err != nil {
+			t.Fatal(err)
+		}
+
+		keys++
+		for _, credential := range credentials {
+			if strings.Contains(contentType+header+string(body), credential) {
+				t.Errorf("the response to %s was kept with a credential", key)
+			}
+		}
+	}
+
+	if keys != 4 {
+		t.Errorf("%d keys were claimed, want 4", keys)
+	}
+
+	admin.Form("POST", "/api/v1/tokens", url.Values{
+		"name":  {"Backups"},
+		"scope": {"read"},
+	}, "Idempotency-Key", "token").Expect(409)
+	admin.Send("POST", "/api/v1/auth/2fa", "Idempotency-Key", "enroll").Expect(409)
+}
@@ -4,14 +4,15 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/andrewdotjs/watchify-server/internal/access"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
 )
 
 // Middleware to log the activity of the API's endpoints.
-func LogEndpoint(next http.Handler, log *logger.Logger, transactionId *string) http.Handler {
+func LogEndpoint(next http.Handler, log *logger.Logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 	  var message string = fmt.Sprintf("%-6v %-30v", r.Method, r.URL.Path)
-		log.Info(*transactionId, message)
+		log.Info(access.RequestId(r), message)
 		next.ServeHTTP(w, r)
 	})
 }
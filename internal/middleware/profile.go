@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Middleware that scopes requests to the profile selected on the session or API
+// token they were authenticated with, so that handlers can enforce the profile's
+// content rating limit and keep its watch history. Must run after Authenticate.
+func Profile(next http.Handler, db *sql.DB, log *logger.Logger) http.Handler {
+	return Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		var user *types.User = access.User(r)
+
+		if user == nil {
+			next.ServeHTTP(w, r)
+			return nil
+		}
+
+		table, column, digest := access.Credential(r)
+
+		profile, err := queries.SelectedProfile(db, table, column, digest)
+		if err != nil {
+			return problems.Wrap(problems.Internal, err, "Failed to retrieve selected profile.")
+		}
+
+		// Profiles only ever scope requests of the user they belong to.
+		if profile == nil || profile.UserId != user.Id {
+			next.ServeHTTP(w, r)
+			return nil
+		}
+
+		profile.Selected = true
+		next.ServeHTTP(w, r.WithContext(access.WithProfile(r.Context(), profile)))
+		return nil
+	})
+}
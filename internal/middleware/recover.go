@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Middleware that turns a panic while serving a request into an internal
+// problem, logging the stack trace under the request id, so that one bad
+// request cannot take down the server. Meant to sit right inside RequestId.
+func Recover(next http.Handler, log *logger.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			// Aborting a response is how handlers hang up on the client on purpose.
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			log.Error(access.RequestId(r), fmt.Sprintf("Recovered from a panic. %v\n%s", recovered, debug.Stack()))
+			responses.WriteProblem(w, r, problems.New(problems.Internal, "An unexpected error occurred. Mention the request id when reporting it."))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
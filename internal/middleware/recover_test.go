@@ -0,0 +1,68 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/middleware"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+func TestRecoverPanic(t *testing.T) {
+	var log logger.Logger
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		var shows map[string]int
+		shows["andor"] = 1 // assignment to a nil map
+	})
+	mux.HandleFunc("GET /ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(middleware.RequestId(middleware.Recover(mux, &log)))
+	defer server.Close()
+
+	for range 2 {
+		request, _ := http.NewRequest("GET", server.URL+"/panic", nil)
+		if status, kind := send(t, request); status != 500 || kind != problems.Internal.URI {
+			t.Errorf("a panicking handler was answered by a %d %s", status, kind)
+		}
+
+		request, _ = http.NewRequest("GET", server.URL+"/ok", nil)
+		if status, _ := send(t, request); status != 200 {
+			t.Errorf("a request after a panic was answered by a %d", status)
+		}
+	}
+}
+
+func TestRecoverScanError(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	show := admin.CreateShow("Andor")
+	admin.CreateMovie("Arrival")
+
+	// A value of the wrong type can't be scanned into the show.
+	if _, err := server.DB.Exec("UPDATE shows SET episode_count = 'many' WHERE id = ?", show); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{"/api/v1/shows", "/api/v1/shows/" + show} {
+		if kind := admin.Send("GET", target).Expect(500).Problem(); kind != problems.Internal.URI {
+			t.Errorf("%s failed with %s", target, kind)
+		}
+	}
+
+	// Other requests are still served, and so is the show once it is repaired.
+	admin.Send("GET", "/api/v1/movies").Expect(200)
+	admin.Send("GET", "/api/v1/auth/me").Expect(200)
+
+	if _, err := server.DB.Exec("UPDATE shows SET episode_count = 1 WHERE id = ?", show); err != nil {
+		t.Fatal(err)
+	}
+
+	admin.Send("GET", "/api/v1/shows/"+show).Expect(200)
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/google/uuid"
+)
+
+// Middleware that gives every request an id, which is sent back in the
+// X-Request-Id header and with every problem, and which everything the request
+// logs is logged under. Meant to wrap every other middleware.
+func RequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var id string = uuid.NewString()
+
+		w.Header().Set("X-Request-Id", id)
+		next.ServeHTTP(w, r.WithContext(access.WithRequestId(r.Context(), id)))
+	})
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/openapi"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Matches the parameters within route patterns, such as "{id}".
+var patternParameter = regexp.MustCompile(`\{[^}]+\}`)
+
+// Middleware that checks every request and response of the routes of mux
+// against the OpenAPI document, so that handlers and the document can't drift
+// apart unnoticed. Divergences are logged, and in strict mode divergent
+// requests are rejected with a 400 and divergent responses replaced by a 500,
+// so that clients exercising the API fail loudly. Meant to wrap the mux
+// directly, since JSON responses are held back until they are checked.
+func Validate(mux *http.ServeMux, document *openapi.Document, strict bool, log *logger.Logger, transactionId *string) http.Handler {
+	for _, pattern := range document.Patterns() {
+		method, path, _ := strings.Cut(pattern, " ")
+		request, _ := http.NewRequest(method, patternParameter.ReplaceAllString(path, "x"), nil)
+
+		if _, registered := mux.Handler(request); registered != pattern {
+			log.Error(*transactionId, fmt.Sprintf("OpenAPI document describes %s, which is not registered", pattern))
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		if pattern == "" { // not found or method not allowed, answered by the mux
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		operation := document.Operation(pattern)
+		if operation == nil {
+			divergence(w, r, strict, problems.ResponseDivergence, fmt.Sprintf("%s is not described", pattern), log)
+			if !strict {
+				mux.ServeHTTP(w, r)
+			}
+			return
+		}
+
+		if reasons := document.CheckRequest(operation, r); len(reasons) > 0 {
+			divergence(w, r, strict, problems.RequestDivergence, fmt.Sprintf("Request to %s diverges from the OpenAPI document: %s", pattern, strings.Join(reasons, "; ")), log)
+			if strict {
+				return
+			}
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		mux.ServeHTTP(recorder, r)
+
+		if !recorder.wroteHeader {
+			recorder.WriteHeader(http.StatusOK)
+		}
+
+		reasons := document.CheckResponse(operation, recorder.status, w.Header(), recorder.body.Bytes())
+		if len(reasons) > 0 {
+			message := fmt.Sprintf("Response of %s diverges from the OpenAPI document: %s", pattern, strings.Join(reasons, "; "))
+
+			if strict && recorder.held {
+				divergence(w, r, strict, problems.ResponseDivergence, message, log)
+				return
+			}
+
+			log.Error(access.RequestId(r), message)
+		}
+
+		if recorder.held {
+			w.WriteHeader(recorder.status)
+			w.Write(recorder.body.Bytes())
+		}
+	})
+}
+
+// Logs a divergence from the OpenAPI document, and in strict mode sends it to
+// the client as well.
+func divergence(w http.ResponseWriter, r *http.Request, strict bool, kind *problems.Type, message string, log *logger.Logger) {
+	log.Error(access.RequestId(r), message)
+
+	if !strict {
+		return
+	}
+
+	responses.WriteProblem(w, r, problems.New(kind, message))
+}
+
+// Holds back JSON responses until they are checked, and lets every other
+// response, such as streamed videos, through as it is written.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	held        bool
+	body        bytes.Buffer
+}
+
+func (recorder *responseRecorder) WriteHeader(status int) {
+	if recorder.wroteHeader {
+		return
+	}
+
+	recorder.status = status
+	recorder.wroteHeader = true
+
+	mediaType, _, _ := mime.ParseMediaType(recorder.Header().Get("Content-Type"))
+	if openapi.IsJSON(mediaType) {
+		recorder.held = true
+		return
+	}
+
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	if !recorder.wroteHeader {
+		recorder.WriteHeader(http.StatusOK)
+	}
+
+	if recorder.held {
+		return recorder.body.Write(data)
+	}
+
+	return recorder.ResponseWriter.Write(data)
+}
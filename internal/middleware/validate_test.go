@@ -0,0 +1,171 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/middleware"
+	"github.com/andrewdotjs/watchify-server/internal/openapi"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+// Serves a handler that answers with status and body at every given pattern,
+// behind Validate, and records whether a handler ran.
+func validated(t *testing.T, strict bool, status int, body string, patterns ...string) (*httptest.Server, *bool) {
+	var log logger.Logger
+	var transactionId string
+	var called bool
+
+	mux := http.NewServeMux()
+	for _, pattern := range patterns {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			io.WriteString(w, body)
+		})
+	}
+
+	server := httptest.NewServer(middleware.Validate(mux, openapi.New(), strict, &log, &transactionId))
+	t.Cleanup(server.Close)
+
+	return server, &called
+}
+
+// Returns the status of the response and the type of the problem it carries,
+// if any.
+func send(t *testing.T, request *http.Request) (int, string) {
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	var problem struct {
+		Type string `json:"type"`
+	}
+
+	json.NewDecoder(response.Body).Decode(&problem)
+	return response.StatusCode, problem.Type
+}
+
+func TestValidateResponses(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		status int
+		body   string
+		passes bool
+	}{
+		{"described", 200, `{"status": 200, "data": {"required": true}}`, true},
+		{"undescribed status", 418, `{"status": 418, "data": {}}`, false},
+		{"wrong type", 200, `{"status": 200, "data": {"required": "yes"}}`, false},
+		{"not JSON", 200, `{"status": `, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for _, strict := range []bool{true, false} {
+				server, _ := validated(t, strict, test.status, test.body, "GET /api/v1/setup")
+				request, _ := http.NewRequest("GET", server.URL+"/api/v1/setup", nil)
+
+				status, kind := send(t, request)
+
+				switch {
+				case test.passes || !strict:
+					if status != test.status {
+						t.Errorf("strict %v: the response was replaced by a %d %s", strict, status, kind)
+					}
+				case status != 500 || kind != problems.ResponseDivergence.URI:
+					t.Errorf("a divergent response was let through as a %d %s", status, kind)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		values url.Values
+		passes bool
+	}{
+		{"described", url.Values{"username": {"admin"}, "password": {"password123"}}, true},
+		{"missing field", url.Values{"username": {"admin"}}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for _, strict := range []bool{true, false} {
+				server, called := validated(t, strict, 200, `{"status": 200, "data": {}}`, "POST /api/v1/auth/login")
+				request, _ := http.NewRequest("POST", server.URL+"/api/v1/auth/login", strings.NewReader(test.values.Encode()))
+				request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+				status, kind := send(t, request)
+
+				switch {
+				case test.passes || !strict:
+					if !*called {
+						t.Errorf("strict %v: the request was rejected with a %d %s", strict, status, kind)
+					}
+				case *called || status != 400 || kind != problems.RequestDivergence.URI:
+					t.Errorf("a divergent request was let through, answered by a %d %s", status, kind)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateUndescribedRoute(t *testing.T) {
+	server, called := validated(t, true, 200, `{}`, "GET /api/v1/undescribed")
+	request, _ := http.NewRequest("GET", server.URL+"/api/v1/undescribed", nil)
+
+	if status, kind := send(t, request); *called || status != 500 || kind != problems.ResponseDivergence.URI {
+		t.Errorf("a route missing from the document was answered by a %d %s", status, kind)
+	}
+}
+
+// Sends a request without a body to every route of the document, through the
+// whole server in strict mode. Requests may be rejected for what they lack,
+// but every answer must be described by the document.
+func TestValidateRoutes(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	movie := admin.CreateMovie("Arrival")
+	show := admin.CreateShow("Andor")
+
+	patterns := openapi.New().Patterns()
+	slices.Sort(patterns)
+
+	for _, pattern := range patterns {
+		// Logging out would end the session of the remaining requests.
+		if pattern == "POST /api/v1/auth/logout" {
+			continue
+		}
+
+		method, path, _ := strings.Cut(pattern, " ")
+
+		var id string = "unknown"
+		switch {
+		case strings.HasPrefix(path, "/api/v1/movies/"):
+			id = movie
+		case strings.HasPrefix(path, "/api/v1/shows/"):
+			id = show
+		}
+
+		target := strings.NewReplacer("{id}", id, "{mediaId}", movie, "{tagId}", "unknown").Replace(path)
+		response := admin.Send(method, target)
+
+		if response.Status == 500 {
+			t.Errorf("%s answered %s", pattern, response.Body)
+		}
+
+		// The mux answers routes that aren't registered in plain text.
+		if (response.Status == 404 || response.Status == 405) && !strings.Contains(response.Header.Get("Content-Type"), "json") {
+			t.Errorf("%s is not registered", pattern)
+		}
+	}
+}
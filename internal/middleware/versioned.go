@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+)
+
+// Locks held while a request changes a resource, so that two requests
+// conditioned on the same ETag can't both pass. Resources are spread over a
+// fixed number of locks by a hash of their table and id, which keeps memory
+// bounded at the cost of unrelated resources sharing a lock now and then.
+var resourceLocks [64]sync.Mutex
+
+// Middleware for the routes of a single resource, identified by the id path
+// value, stored in a versioned table. Routes changing a part of a resource,
+// such as its cover or tags, use the table of the resource, whose version is
+// incremented along with any change to its parts.
+//
+// Successful JSON responses carry a strong ETag made of the version of the row
+// and a digest of the body, as parts of representations depend on the viewing
+// profile. GET requests with a matching If-None-Match get 304 Not Modified.
+//
+// Requests changing the resource must send If-Match. Only the version of the
+// ETags in it is compared, since the parts that depend on the viewing profile
+// can't be changed through the resource anyway.
+func Versioned(table string, db *sql.DB, log *logger.Logger, next http.Handler) http.Handler {
+	return Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		var id string = r.PathValue("id")
+		var changes bool = r.Method != http.MethodGet && r.Method != http.MethodHead
+
+		if changes {
+			ifMatch := r.Header.Get("If-Match")
+			if ifMatch == "" {
+				return problems.New(problems.PreconditionRequired, "Send the ETag of the resource in an If-Match header to change it.")
+			}
+
+			lock := resourceLock(table, id)
+			lock.Lock()
+			defer lock.Unlock()
+
+			version, err := rowVersion(db, table, id)
+			if errors.Is(err, sql.ErrNoRows) {
+				next.ServeHTTP(w, r) // the handler tells the client it does not exist
+				return nil
+			}
+
+			if err != nil {
+				return problems.Wrap(problems.Internal, err, "Failed to retrieve the version of the resource.")
+			}
+
+			if !VersionMatches(ifMatch, version) {
+				return problems.New(problems.PreconditionFailed, "The resource changed since it was retrieved. Retrieve it again and reapply the changes.")
+			}
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		if !recorder.wroteHeader {
+			recorder.WriteHeader(http.StatusOK)
+		}
+
+		if !recorder.held {
+			return nil
+		}
+
+		if recorder.status == http.StatusOK {
+			if version, err := rowVersion(db, table, id); err == nil {
+				digest := sha256.Sum256(recorder.body.Bytes())
+				etag := fmt.Sprintf(`"%d.%x"`, version, digest[:8])
+				w.Header().Set("ETag", etag)
+
+				if !changes && noneMatch(r.Header.Get("If-None-Match"), etag) {
+					w.Header().Del("Content-Type")
+					w.Header().Del("Content-Language")
+					w.WriteHeader(http.StatusNotModified)
+					return nil
+				}
+			}
+		}
+
+		w.WriteHeader(recorder.status)
+		w.Write(recorder.body.Bytes())
+		return nil
+	})
+}
+
+// Returns the lock of the row of table with the given id.
+func resourceLock(table string, id string) *sync.Mutex {
+	return &resourceLocks[lockIndex(table, id)]
+}
+
+func lockIndex(table string, id string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(table + "/" + id))
+
+	return int(hash.Sum32() % uint32(len(resourceLocks)))
+}
+
+// A row of a versioned table.
+type Resource struct {
+	Table string
+	Id    string
+}
+
+// Takes the locks Versioned takes while changing each of the resources, for
+// changes to several resources at once. Resources sharing a lock take it once,
+// and locks are taken in a fixed order, so that callers can't deadlock each
+// other. Returns the function releasing them.
+func LockResources(resources []Resource) func() {
+	var indexes []int
+
+	for _, resource := range resources {
+		indexes = append(indexes, lockIndex(resource.Table, resource.Id))
+	}
+
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+
+	for _, index := range indexes {
+		resourceLocks[index].Lock()
+	}
+
+	return func() {
+		for _, index := range indexes {
+			resourceLocks[index].Unlock()
+		}
+	}
+}
+
+// Returns the version of the row of table with the given id.
+func rowVersion(db *sql.DB, table string, id string) (int64, error) {
+	var version int64
+
+	err := db.QueryRow(fmt.Sprintf("SELECT version FROM %s WHERE id = ?", table), id).Scan(&version)
+	return version, err
+}
+
+// Reports whether an If-Match header, or the if_match of a batch operation,
+// holds "*" or a strong ETag of the given version. ETags made of only a
+// version are accepted as well.
+func VersionMatches(header string, version int64) bool {
+	for _, etag := range strings.Split(header, ",") {
+		etag = strings.TrimSpace(etag)
+
+		if etag == "*" {
+			return true
+		}
+
+		if !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) || len(etag) < 2 {
+			continue // weak ETags never match If-Match
+		}
+
+		tagged, _, _ := strings.Cut(strings.Trim(etag, `"`), ".")
+		if tagged == strconv.FormatInt(version, 10) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reports whether an If-None-Match header holds "*" or etag, compared weakly as
+// RFC 9110 asks for.
+func noneMatch(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
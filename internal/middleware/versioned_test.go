@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/servertest"
+)
+
+// Returns the ETag of the resource at target.
+func etag(t *testing.T, client *servertest.Client, target string) string {
+	t.Helper()
+
+	tag := client.Send("GET", target).Expect(200).Header.Get("ETag")
+	if tag == "" {
+		t.Fatalf("%s carries no ETag", target)
+	}
+
+	return tag
+}
+
+func TestVersionedParts(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	show := admin.CreateShow("Andor")
+	tag := admin.Form("POST", "/api/v1/tags", url.Values{"name": {"Drama"}}).Expect(201).String("id")
+
+	target := "/api/v1/shows/" + show + "/tags/" + tag
+	before := etag(t, admin, "/api/v1/shows/"+show)
+
+	admin.Send("PUT", target).Expect(428)
+	admin.Send("PUT", target, "If-Match", before).Expect(200)
+
+	// Attaching the tag changed the show, so the ETag it was based on is stale.
+	after := etag(t, admin, "/api/v1/shows/"+show)
+	if after == before {
+		t.Fatal("attaching a tag left the ETag of the show as it was")
+	}
+
+	admin.Send("DELETE", target, "If-Match", before).Expect(412)
+	admin.Send("DELETE", "/api/v1/shows/"+show+"/cover", "If-Match", before).Expect(412)
+	admin.Send("DELETE", target, "If-Match", after).Expect(200)
+
+	if etag(t, admin, "/api/v1/shows/"+show) == after {
+		t.Error("detaching a tag left the ETag of the show as it was")
+	}
+}
+
+func TestVersionedCredits(t *testing.T) {
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	movie := admin.CreateMovie("Arrival")
+	person := admin.Upload("POST", "/api/v1/people", url.Values{"name": {"Amy Adams"}}, nil).Expect(201).String("id")
+
+	credit := admin.Form("POST", "/api/v1/credits", url.Values{
+		"person_id":   {person},
+		"parent_id":   {movie},
+		"parent_type": {"movie"},
+		"role":        {"actor"},
+		"character":   {"Louise Banks"},
+	}).Expect(201).Data()
+
+	id, _ := credit["id"].(string)
+	if credit["version"] != float64(1) {
+		t.Fatalf("a new credit is at version %v", credit["version"])
+	}
+
+	before := etag(t, admin, "/api/v1/movies/"+movie)
+
+	admin.Form("PUT", "/api/v1/credits/"+id, url.Values{"position": {"1"}}).Expect(428)
+	if version := admin.Form("PUT", "/api/v1/credits/"+id, url.Values{"position": {"1"}}, "If-Match", `"1"`).Expect(200).Data()["version"]; version != float64(2) {
+		t.Errorf("the updated credit is at version %v", version)
+	}
+
+	admin.Send("DELETE", "/api/v1/credits/"+id, "If-Match", `"1"`).Expect(412)
+
+	// Credits are part of the movie, so changing them changes its ETag.
+	if etag(t, admin, "/api/v1/movies/"+movie) == before {
+		t.Error("updating a credit left the ETag of the movie as it was")
+	}
+
+	admin.Send("DELETE", "/api/v1/credits/"+id, "If-Match", `"2"`).Expect(200)
+}
+
+func TestVersionedConcurrentChanges(t *testing.T) {
+	var waitGroup sync.WaitGroup
+	var mutex sync.Mutex
+	var statuses map[int]int = map[int]int{}
+
+	server := servertest.New(t)
+	admin := server.Admin(t)
+	movie := admin.CreateMovie("Arrival")
+	before := etag(t, admin, "/api/v1/movies/"+movie)
+
+	// Requests conditioned on the same ETag are served one at a time, so only
+	// the first one finds it current.
+	for i := range 8 {
+		tag := admin.Form("POST", "/api/v1/tags", url.Values{"name": {string(rune('A' + i))}}).Expect(201).String("id")
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			status := admin.Send("PUT", "/api/v1/movies/"+movie+"/tags/"+tag, "If-Match", before).Status
+
+			mutex.Lock()
+			statuses[status]++
+			mutex.Unlock()
+		}()
+	}
+
+	waitGroup.Wait()
+
+	if statuses[200] != 1 || statuses[412] != 7 {
+		t.Errorf("concurrent changes were answered with %v", statuses)
+	}
+}
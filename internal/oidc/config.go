@@ -0,0 +1,68 @@
+package oidc
+
+import (
+	"os"
+	"strings"
+
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+)
+
+// Settings of the OpenID Connect provider that users can log in with.
+type Config struct {
+	Issuer       string            // issuer URL, used for discovery
+	ClientId     string            // client id registered with the provider
+	ClientSecret string            // client secret, empty for public clients
+	RedirectUrl  string            // absolute URL of the callback endpoint
+	Scopes       []string          // scopes requested on top of "openid"
+	RoleClaim    string            // claim holding the groups or roles of the user
+	RoleMapping  map[string]string // claim values mapped to roles of the server
+	DefaultRole  string            // role of provisioned users without a mapped claim value
+	LandingUrl   string            // where the browser is sent after logging in
+}
+
+// Reads the configuration from the environment. Returns nil when no issuer is
+// configured, which disables single sign-on.
+//
+//   - WATCHIFY_OIDC_ISSUER        : REQUIRED. Issuer URL of the provider.
+//   - WATCHIFY_OIDC_CLIENT_ID     : REQUIRED. Client id.
+//   - WATCHIFY_OIDC_CLIENT_SECRET : OPTIONAL. Client secret.
+//   - WATCHIFY_OIDC_REDIRECT_URL  : REQUIRED. URL of /api/v1/auth/oidc/callback.
+//   - WATCHIFY_OIDC_SCOPES        : OPTIONAL. Comma separated, defaults to "profile,email".
+//   - WATCHIFY_OIDC_ROLE_CLAIM    : OPTIONAL. Defaults to "groups".
+//   - WATCHIFY_OIDC_ROLE_MAPPING  : OPTIONAL. Comma separated value=role pairs, such as "media-admins=admin".
+//   - WATCHIFY_OIDC_DEFAULT_ROLE  : OPTIONAL. Defaults to "viewer".
+//   - WATCHIFY_OIDC_LANDING_URL   : OPTIONAL. Defaults to "/".
+func FromEnvironment() *Config {
+	var config Config = Config{
+		Issuer:       strings.TrimSuffix(os.Getenv("WATCHIFY_OIDC_ISSUER"), "/"),
+		ClientId:     os.Getenv("WATCHIFY_OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("WATCHIFY_OIDC_CLIENT_SECRET"),
+		RedirectUrl:  os.Getenv("WATCHIFY_OIDC_REDIRECT_URL"),
+		Scopes:       functions.SplitList(environment("WATCHIFY_OIDC_SCOPES", "profile,email")),
+		RoleClaim:    environment("WATCHIFY_OIDC_ROLE_CLAIM", "groups"),
+		RoleMapping:  map[string]string{},
+		DefaultRole:  environment("WATCHIFY_OIDC_DEFAULT_ROLE", "viewer"),
+		LandingUrl:   environment("WATCHIFY_OIDC_LANDING_URL", "/"),
+	}
+
+	if config.Issuer == "" {
+		return nil
+	}
+
+	for _, pair := range functions.SplitList(os.Getenv("WATCHIFY_OIDC_ROLE_MAPPING")) {
+		if value, role, found := strings.Cut(pair, "="); found {
+			config.RoleMapping[strings.TrimSpace(value)] = strings.TrimSpace(role)
+		}
+	}
+
+	return &config
+}
+
+// Returns the value of the environment variable, or fallback if it is not set.
+func environment(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
@@ -0,0 +1,195 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// A single key of a JSON Web Key Set, as described in RFC 7517.
+type jsonWebKey struct {
+	KeyId     string `json:"kid"`
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+// Signature algorithms that ID tokens are accepted with, along with the hash
+// each of them signs.
+var algorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// Splits a compact JWS into its header, payload and signature, and decodes
+// them. The payload is returned as is, verify the signature before using it.
+func parseToken(token string) (map[string]any, []byte, []byte, string, error) {
+	var header map[string]any
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, nil, "", errors.New("token is not a compact JWS")
+	}
+
+	headerJson, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, nil, "", fmt.Errorf("token header is malformed. %w", err)
+	}
+
+	if err := json.Unmarshal(headerJson, &header); err != nil {
+		return nil, nil, nil, "", fmt.Errorf("token header is malformed. %w", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, nil, "", fmt.Errorf("token payload is malformed. %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, nil, "", fmt.Errorf("token signature is malformed. %w", err)
+	}
+
+	return header, payload, signature, parts[0] + "." + parts[1], nil
+}
+
+// Checks the signature over signingInput with the key, using the algorithm
+// named in the token header.
+func verifySignature(algorithm string, key *jsonWebKey, signingInput string, signature []byte) error {
+	hashType, supported := algorithms[algorithm]
+	if !supported {
+		return fmt.Errorf("signature algorithm %q is not supported", algorithm)
+	}
+
+	if key.Algorithm != "" && key.Algorithm != algorithm {
+		return fmt.Errorf("key %q is meant for %s, not %s", key.KeyId, key.Algorithm, algorithm)
+	}
+
+	digest := hashOf(hashType, []byte(signingInput))
+
+	switch algorithm[:2] {
+	case "RS":
+		publicKey, err := key.rsaPublicKey()
+		if err != nil {
+			return err
+		}
+
+		return rsa.VerifyPKCS1v15(publicKey, hashType, digest, signature)
+	default:
+		publicKey, err := key.ecdsaPublicKey()
+		if err != nil {
+			return err
+		}
+
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("signature has the wrong length")
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+
+		if !ecdsa.Verify(publicKey, digest, r, s) {
+			return errors.New("signature does not match")
+		}
+
+		return nil
+	}
+}
+
+// Returns the digest of data using the given hash.
+func hashOf(hashType crypto.Hash, data []byte) []byte {
+	switch hashType {
+	case crypto.SHA384:
+		sum := sha512.Sum384(data)
+		return sum[:]
+	case crypto.SHA512:
+		sum := sha512.Sum512(data)
+		return sum[:]
+	default:
+		sum := sha256.Sum256(data)
+		return sum[:]
+	}
+}
+
+// Builds the RSA public key described by the JWK.
+func (key *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	if key.KeyType != "RSA" {
+		return nil, fmt.Errorf("key %q is not an RSA key", key.KeyId)
+	}
+
+	modulus, err := base64.RawURLEncoding.DecodeString(key.Modulus)
+	if err != nil {
+		return nil, fmt.Errorf("key %q has a malformed modulus. %w", key.KeyId, err)
+	}
+
+	exponent, err := base64.RawURLEncoding.DecodeString(key.Exponent)
+	if err != nil || len(exponent) == 0 || len(exponent) > 4 {
+		return nil, fmt.Errorf("key %q has a malformed exponent", key.KeyId)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
+
+// Builds the ECDSA public key described by the JWK.
+func (key *jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+
+	if key.KeyType != "EC" {
+		return nil, fmt.Errorf("key %q is not an EC key", key.KeyId)
+	}
+
+	switch key.Curve {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("key %q uses the unsupported curve %q", key.KeyId, key.Curve)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil {
+		return nil, fmt.Errorf("key %q has a malformed x coordinate. %w", key.KeyId, err)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(key.Y)
+	if err != nil {
+		return nil, fmt.Errorf("key %q has a malformed y coordinate. %w", key.KeyId, err)
+	}
+
+	publicKey := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+
+	if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+		return nil, fmt.Errorf("key %q is not a point on its curve", key.KeyId)
+	}
+
+	return publicKey, nil
+}
@@ -0,0 +1,13 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// Returns the S256 PKCE code challenge of a code verifier, as described in
+// RFC 7636 section 4.2.
+func Challenge(verifier string) string {
+	var sum [32]byte = sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,12 @@
+package oidc
+
+import "testing"
+
+func TestChallenge(t *testing.T) {
+	// Example of RFC 7636 appendix B.
+	var verifier string = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	if challenge := Challenge(verifier); challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("the challenge of the example verifier is %s", challenge)
+	}
+}
@@ -0,0 +1,359 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How far the clocks of the server and the provider may drift apart.
+const clockLeeway time.Duration = time.Minute
+
+// How often the key set may be refetched when a token is signed by an unknown
+// key, which is how providers rotate keys.
+const keyRefreshInterval time.Duration = time.Minute
+
+// The parts of the provider metadata, as described in OpenID Connect Discovery
+// 1.0 section 3, that the server uses.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// Claims of a verified ID token.
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	Name              string
+	PreferredUsername string
+	Raw               map[string]any // every claim of the token
+}
+
+// An OpenID Connect provider. Discovery happens on first use, so that the
+// server can start while the provider is unreachable.
+type Provider struct {
+	Config *Config
+
+	client    *http.Client
+	mutex     sync.Mutex
+	metadata  *metadata
+	keys      []jsonWebKey
+	keysFetch time.Time
+}
+
+// Returns a provider for the configuration, or nil if the configuration is nil.
+func New(config *Config) *Provider {
+	if config == nil {
+		return nil
+	}
+
+	return &Provider{
+		Config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Returns the URL of the provider's authorization endpoint that starts an
+// authorization code flow with PKCE.
+func (provider *Provider) AuthorizationUrl(ctx context.Context, state string, nonce string, verifier string) (string, error) {
+	discovered, err := provider.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authorizationUrl, err := url.Parse(discovered.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("authorization endpoint is malformed. %w", err)
+	}
+
+	query := authorizationUrl.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.Config.ClientId)
+	query.Set("redirect_uri", provider.Config.RedirectUrl)
+	query.Set("scope", strings.Join(append([]string{"openid"}, provider.Config.Scopes...), " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", Challenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authorizationUrl.RawQuery = query.Encode()
+
+	return authorizationUrl.String(), nil
+}
+
+// Redeems an authorization code at the token endpoint and returns the claims of
+// the ID token that comes back, after verifying its signature, issuer,
+// audience, expiry and nonce.
+func (provider *Provider) Exchange(ctx context.Context, code string, verifier string, nonce string) (*Claims, error) {
+	var tokenResponse struct {
+		IdToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+
+	discovered, err := provider.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.Config.RedirectUrl)
+	form.Set("code_verifier", verifier)
+
+	if provider.Config.ClientSecret == "" {
+		form.Set("client_id", provider.Config.ClientId)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", discovered.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	if provider.Config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(provider.Config.ClientId), url.QueryEscape(provider.Config.ClientSecret))
+	}
+
+	if err := provider.fetchJson(request, &tokenResponse); err != nil && tokenResponse.Error == "" {
+		return nil, fmt.Errorf("token request failed. %w", err)
+	}
+
+	if tokenResponse.Error != "" {
+		return nil, fmt.Errorf("token request was rejected with %q", tokenResponse.Error)
+	}
+
+	if tokenResponse.IdToken == "" {
+		return nil, errors.New("token response did not contain an ID token")
+	}
+
+	return provider.verify(ctx, tokenResponse.IdToken, nonce)
+}
+
+// Verifies an ID token and returns its claims.
+func (provider *Provider) verify(ctx context.Context, idToken string, nonce string) (*Claims, error) {
+	var raw map[string]any
+	var now time.Time = time.Now()
+
+	header, payload, signature, signingInput, err := parseToken(idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithm, _ := header["alg"].(string)
+	keyId, _ := header["kid"].(string)
+
+	key, err := provider.key(ctx, keyId, algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(algorithm, key, signingInput, signature); err != nil {
+		return nil, fmt.Errorf("ID token signature is invalid. %w", err)
+	}
+
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("ID token payload is malformed. %w", err)
+	}
+
+	claims := &Claims{Raw: raw}
+	claims.Issuer, _ = raw["iss"].(string)
+	claims.Subject, _ = raw["sub"].(string)
+	claims.Email, _ = raw["email"].(string)
+	claims.Name, _ = raw["name"].(string)
+	claims.PreferredUsername, _ = raw["preferred_username"].(string)
+
+	if claims.Issuer != provider.metadata.Issuer {
+		return nil, fmt.Errorf("ID token was issued by %q instead of %q", claims.Issuer, provider.metadata.Issuer)
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("ID token has no subject")
+	}
+
+	audiences := Strings(raw["aud"])
+	if !contains(audiences, provider.Config.ClientId) {
+		return nil, errors.New("ID token was not issued to this client")
+	}
+
+	if authorizedParty, _ := raw["azp"].(string); len(audiences) > 1 && authorizedParty != provider.Config.ClientId {
+		return nil, errors.New("ID token was issued to another authorized party")
+	}
+
+	expiry, hasExpiry := raw["exp"].(float64)
+	if !hasExpiry || now.Add(-clockLeeway).Unix() >= int64(expiry) {
+		return nil, errors.New("ID token has expired")
+	}
+
+	if issuedAt, ok := raw["iat"].(float64); ok && int64(issuedAt) > now.Add(clockLeeway).Unix() {
+		return nil, errors.New("ID token was issued in the future")
+	}
+
+	if notBefore, ok := raw["nbf"].(float64); ok && int64(notBefore) > now.Add(clockLeeway).Unix() {
+		return nil, errors.New("ID token is not valid yet")
+	}
+
+	if tokenNonce, _ := raw["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("ID token nonce does not match")
+	}
+
+	return claims, nil
+}
+
+// Returns the provider metadata, fetching it on first use.
+func (provider *Provider) discover(ctx context.Context) (*metadata, error) {
+	var discovered metadata
+
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if provider.metadata != nil {
+		return provider.metadata, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", provider.Config.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := provider.fetchJson(request, &discovered); err != nil {
+		return nil, fmt.Errorf("discovery failed. %w", err)
+	}
+
+	if strings.TrimSuffix(discovered.Issuer, "/") != provider.Config.Issuer {
+		return nil, fmt.Errorf("discovery returned the issuer %q instead of %q", discovered.Issuer, provider.Config.Issuer)
+	}
+
+	if discovered.AuthorizationEndpoint == "" || discovered.TokenEndpoint == "" || discovered.JwksUri == "" {
+		return nil, errors.New("discovery did not return every required endpoint")
+	}
+
+	provider.metadata = &discovered
+	return provider.metadata, nil
+}
+
+// Returns the signing key with the given id. The key set is refetched when the
+// key is unknown, at most once per keyRefreshInterval.
+func (provider *Provider) key(ctx context.Context, keyId string, algorithm string) (*jsonWebKey, error) {
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if found := findKey(provider.keys, keyId, algorithm); found != nil {
+		return found, nil
+	}
+
+	if time.Since(provider.keysFetch) < keyRefreshInterval {
+		return nil, fmt.Errorf("no signing key found with id %q", keyId)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "GET", provider.metadata.JwksUri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.keysFetch = time.Now()
+
+	if err := provider.fetchJson(request, &keySet); err != nil {
+		return nil, fmt.Errorf("key set request failed. %w", err)
+	}
+
+	provider.keys = keySet.Keys
+
+	if found := findKey(provider.keys, keyId, algorithm); found != nil {
+		return found, nil
+	}
+
+	return nil, fmt.Errorf("no signing key found with id %q", keyId)
+}
+
+// Returns the signing key with the given id from the key set. Tokens without a
+// key id match the first signing key of the right type.
+func findKey(keys []jsonWebKey, keyId string, algorithm string) *jsonWebKey {
+	for index := range keys {
+		key := &keys[index]
+
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		if keyId != "" && key.KeyId == keyId {
+			return key
+		}
+
+		if keyId == "" && len(algorithm) > 1 && strings.HasPrefix(key.KeyType, algorithm[:1]) {
+			return key
+		}
+	}
+
+	return nil
+}
+
+// Sends the request and decodes the JSON response body into target. Responses
+// with a status other than 200 are decoded as well, so that OAuth error
+// responses can be read, but still return an error.
+func (provider *Provider) fetchJson(request *http.Request, target any) error {
+	response, err := provider.client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	decodeErr := json.Unmarshal(body, target)
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with status %d", request.URL.Host, response.StatusCode)
+	}
+
+	return decodeErr
+}
+
+// Returns a claim that may either be a single string or an array of strings as
+// a slice of strings.
+func Strings(claim any) []string {
+	var values []string = []string{}
+
+	switch typed := claim.(type) {
+	case string:
+		values = append(values, typed)
+	case []any:
+		for _, value := range typed {
+			if text, ok := value.(string); ok {
+				values = append(values, text)
+			}
+		}
+	}
+
+	return values
+}
+
+// Reports whether the slice contains the value.
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,214 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Largest part of multipart forms kept in memory while checking them, the rest
+// is stored in temporary files the way handlers parse forms too.
+const maximumFormMemory int64 = 32 << 20
+
+// Returns the reasons a request does not match operation: missing or invalid
+// query parameters, parameters that are not described, and bodies of the wrong
+// type or with missing fields. Forms are parsed, and JSON bodies read and put
+// back, so that the handler can still use them.
+func (document *Document) CheckRequest(operation *Operation, r *http.Request) []string {
+	var problems []string
+	var query = r.URL.Query()
+	var described map[string]bool = map[string]bool{}
+
+	for _, parameter := range operation.Parameters {
+		if parameter.In != "query" {
+			continue
+		}
+
+		described[parameter.Name] = true
+
+		if !query.Has(parameter.Name) {
+			if parameter.Required {
+				problems = append(problems, fmt.Sprintf("query parameter %s is missing", parameter.Name))
+			}
+			continue
+		}
+
+		problems = append(problems, document.Validate(parameter.Schema, parameterValue(parameter.Schema, query.Get(parameter.Name)), "query parameter "+parameter.Name)...)
+	}
+
+	for _, name := range sortedKeys(query) {
+		if !described[name] {
+			problems = append(problems, fmt.Sprintf("query parameter %s is not described", name))
+		}
+	}
+
+	var hasBody bool = r.ContentLength > 0 || len(r.TransferEncoding) > 0
+
+	if operation.RequestBody == nil {
+		if hasBody {
+			problems = append(problems, "the request has a body, but none is described")
+		}
+		return problems
+	}
+
+	if !hasBody {
+		if operation.RequestBody.Required {
+			problems = append(problems, "the request body is missing")
+		}
+		return problems
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	content := operation.RequestBody.Content[mediaType]
+	if content == nil {
+		return append(problems, fmt.Sprintf("the request body is %q, which is not one of %v", mediaType, sortedKeys(operation.RequestBody.Content)))
+	}
+
+	if content.Schema == nil {
+		return problems
+	}
+
+	switch {
+	case mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded":
+		var err error
+		if mediaType == "multipart/form-data" {
+			err = r.ParseMultipartForm(maximumFormMemory)
+		} else {
+			err = r.ParseForm()
+		}
+
+		if err != nil {
+			return append(problems, fmt.Sprintf("the request body is not a valid form. %v", err))
+		}
+
+		problems = append(problems, document.checkForm(content.Schema, r)...)
+
+	case IsJSON(mediaType):
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return append(problems, fmt.Sprintf("the request body could not be read. %v", err))
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var value any
+		if err := json.Unmarshal(body, &value); err != nil {
+			return append(problems, "the request body is not valid JSON")
+		}
+
+		problems = append(problems, document.Validate(content.Schema, value, "body")...)
+	}
+
+	return problems
+}
+
+// Returns the reasons the fields of a parsed form do not match the properties
+// of schema. Files are not checked beyond being present.
+func (document *Document) checkForm(schema *Schema, r *http.Request) []string {
+	var problems []string
+	var fields map[string]bool = map[string]bool{}
+
+	for name := range r.PostForm {
+		fields[name] = true
+	}
+
+	if r.MultipartForm != nil {
+		for name := range r.MultipartForm.File {
+			fields[name] = true
+		}
+	}
+
+	for _, name := range schema.Required {
+		if !fields[name] {
+			problems = append(problems, fmt.Sprintf("form field %s is missing", name))
+		}
+	}
+
+	for _, name := range sortedKeys(fields) {
+		property, described := schema.Properties[name]
+		if !described {
+			problems = append(problems, fmt.Sprintf("form field %s is not described", name))
+			continue
+		}
+
+		if values, isValue := r.PostForm[name]; isValue && property.Format != "binary" {
+			problems = append(problems, document.Validate(property, parameterValue(property, values[0]), "form field "+name)...)
+		}
+	}
+
+	return problems
+}
+
+// Returns the reasons a response does not match operation: a status code that
+// is not described, a content type that is not described for it, or a JSON body
+// that does not match its schema.
+func (document *Document) CheckResponse(operation *Operation, status int, header http.Header, body []byte) []string {
+	response := operation.Responses[strconv.Itoa(status)]
+	if response == nil {
+		return []string{fmt.Sprintf("the status %d is not described", status)}
+	}
+
+	if len(response.Content) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	content := response.Content[mediaType]
+	if content == nil {
+		return []string{fmt.Sprintf("the %d response is %q, which is not one of %v", status, mediaType, sortedKeys(response.Content))}
+	}
+
+	if content.Schema == nil || !IsJSON(mediaType) {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("the %d response body is not valid JSON", status)}
+	}
+
+	return document.Validate(content.Schema, value, "body")
+}
+
+// Reports whether a media type holds JSON, such as "application/json" or
+// "application/problem+json".
+func IsJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// Returns a query parameter or form field as the type its schema describes, so
+// that it can be validated like JSON. Values that can't be converted are left
+// as strings, which then fail validation.
+func parameterValue(schema *Schema, raw string) any {
+	for _, name := range schema.types() {
+		switch name {
+		case "integer", "number":
+			if number, err := strconv.ParseFloat(raw, 64); err == nil {
+				return number
+			}
+		case "boolean":
+			if boolean, err := strconv.ParseBool(raw); err == nil {
+				return boolean
+			}
+		}
+	}
+
+	return raw
+}
+
+// Returns the keys of a map in order.
+func sortedKeys[T any](values map[string]T) []string {
+	var keys []string = make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
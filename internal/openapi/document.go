@@ -0,0 +1,103 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+)
+
+// An OpenAPI 3.1 document, describing every route of the API.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+	Security   []Requirement        `json:"security"`
+
+	componentTypes map[string]reflect.Type // Go types of the component schemas
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// The operations of a path, keyed by their lower case method.
+type PathItem map[string]*Operation
+
+// Security schemes that must all be satisfied, keyed by their names.
+type Requirement map[string][]string
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// A single route. Permission is the permission of access the route requires,
+// if any.
+type Operation struct {
+	OperationId string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Security    *[]Requirement       `json:"security,omitempty"` // empty for routes that can be used without logging in
+	Permission  string               `json:"x-permission,omitempty"`
+}
+
+// A path or query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Returns the operation of a route registered on the mux as pattern, such as
+// "GET /api/v1/shows/{id}", or nil if it is not described.
+func (document *Document) Operation(pattern string) *Operation {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found || document.Paths[path] == nil {
+		return nil
+	}
+
+	return (*document.Paths[path])[strings.ToLower(method)]
+}
+
+// Returns the patterns of every described route, in the format they are
+// registered on the mux with.
+func (document *Document) Patterns() []string {
+	var patterns []string
+
+	for path, item := range document.Paths {
+		for method := range *item {
+			patterns = append(patterns, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	return patterns
+}
@@ -0,0 +1,404 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+	"github.com/andrewdotjs/watchify-server/internal/responses"
+)
+
+// Describes a route that requires logging in, along with the permission it
+// requires, if any. Responses every such route can send, such as those of the
+// authentication middleware, are added to the operation, as is the
+// Idempotency-Key of POST routes.
+func (document *Document) route(pattern string, permission access.Permission, operation Operation) {
+	operation.Permission = string(permission)
+	operation.Responses = document.withProblems(operation.Responses, 401, 403, 500)
+
+	// Requests creating something can be retried safely, see middleware.Idempotency.
+	if strings.HasPrefix(pattern, "POST ") {
+		operation.Parameters = append(operation.Parameters, &Parameter{
+			Name:        "Idempotency-Key",
+			In:          "header",
+			Description: "Unique key of the request, under which its response is replayed to retries for a day. Responses carrying credentials, or larger than a MiB, are not replayed, their retries are conflicts instead.",
+			Schema:      &Schema{Type: "string"},
+		})
+		operation.Responses = document.withProblems(operation.Responses, 409)
+	}
+	document.add(pattern, &operation)
+}
+
+// Describes a route that can be used without logging in.
+func (document *Document) public(pattern string, operation Operation) {
+	operation.Security = &[]Requirement{}
+	operation.Responses = document.withProblems(operation.Responses, 500)
+	document.add(pattern, &operation)
+}
+
+func (document *Document) add(pattern string, operation *Operation) {
+	method, path, _ := strings.Cut(pattern, " ")
+
+	if operation.OperationId == "" {
+		operation.OperationId = operationId(method, path)
+	}
+
+	if len(operation.Tags) == 0 {
+		segments := strings.Split(strings.TrimPrefix(path, "/api/v1/"), "/")
+		operation.Tags = []string{strings.TrimPrefix(segments[0], "/api/")}
+	}
+
+	// Path parameters that are not described otherwise come first, in order.
+	var parameters []*Parameter
+	var segments []string = strings.Split(path, "/")
+	for index, segment := range segments {
+		name, isParameter := strings.CutPrefix(segment, "{")
+		name = strings.TrimSuffix(name, "}")
+
+		if isParameter && !hasParameter(operation.Parameters, name, "path") {
+			thing := strings.TrimSuffix(name, "Id")
+			if name == "id" {
+				thing = singular(segments[index-1])
+			}
+
+			parameters = append(parameters, pathParameter(name, "UUID of the "+thing+"."))
+		}
+	}
+	operation.Parameters = append(parameters, operation.Parameters...)
+
+	if document.Paths[path] == nil {
+		document.Paths[path] = &PathItem{}
+	}
+
+	(*document.Paths[path])[strings.ToLower(method)] = operation
+}
+
+// Describes the conditional requests handled by middleware.Versioned on the
+// operations of each path: an If-None-Match for GET, answered with 304, and a
+// required If-Match for those changing the resource.
+func (document *Document) versioned(paths ...string) {
+	for _, path := range paths {
+		for method, operation := range *document.Paths[path] {
+			if method == "get" {
+				operation.Parameters = append(operation.Parameters, &Parameter{
+					Name:        "If-None-Match",
+					In:          "header",
+					Description: "ETag of a representation held already, which is then answered with 304 if it is current.",
+					Schema:      &Schema{Type: "string"},
+				})
+				operation.Responses["304"] = &Response{Description: "The representation held already is current."}
+				continue
+			}
+
+			document.requireIfMatch(operation)
+		}
+	}
+}
+
+// Describes the required If-Match of routes changing a part of a resource, such
+// as its cover, which middleware.Versioned checks against the version of the
+// resource itself.
+func (document *Document) conditional(patterns ...string) {
+	for _, pattern := range patterns {
+		document.requireIfMatch(document.Operation(pattern))
+	}
+}
+
+func (document *Document) requireIfMatch(operation *Operation) {
+	operation.Parameters = append(operation.Parameters, &Parameter{
+		Name:        "If-Match",
+		In:          "header",
+		Description: "ETag of the representation the change is based on, or * for any.",
+		Required:    true,
+		Schema:      &Schema{Type: "string"},
+	})
+	operation.Responses = document.withProblems(operation.Responses, 412, 428)
+}
+
+// Returns responses along with problem details for the given statuses, unless
+// a response for them is described already.
+func (document *Document) withProblems(described map[string]*Response, statuses ...int) map[string]*Response {
+	if described == nil {
+		described = map[string]*Response{}
+	}
+
+	for _, status := range statuses {
+		if described[strconv.Itoa(status)] == nil {
+			described[strconv.Itoa(status)] = document.problem()
+		}
+	}
+
+	return described
+}
+
+// Returns a response carrying problem details.
+func (document *Document) problem() *Response {
+	var schema *Schema = document.SchemaOf(responses.Problem{})
+
+	// Clients branch on the type, so every type of the catalog is listed.
+	if kind := document.Components.Schemas["Problem"].Properties["type"]; kind.Enum == nil {
+		kind.Description = "URI of the type of the problem, which stays the same across releases."
+		for _, problemType := range problems.Catalog {
+			kind.Enum = append(kind.Enum, problemType.URI)
+		}
+	}
+
+	return &Response{
+		Description: "The request failed, see the problem details.",
+		Content: map[string]*MediaType{
+			"application/problem+json": {Schema: schema},
+		},
+	}
+}
+
+// Returns a response carrying data in the status envelope. Data is left out
+// of the envelope when it is nil, or when data is nil or empty.
+func (document *Document) status(description string, data any) *Response {
+	var envelope Schema = Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"status": {Type: "integer"},
+		},
+		Required:             []string{"status"},
+		AdditionalProperties: false,
+	}
+
+	if data != nil {
+		envelope.Properties["data"] = document.SchemaOf(data)
+	}
+
+	return jsonResponse(description, &envelope)
+}
+
+// Returns a response carrying a page of a collection, which data is an example
+// of, in the page envelope.
+func (document *Document) paged(description string, data any) *Response {
+	return jsonResponse(description, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"status": {Type: "integer"},
+			"data":   document.SchemaOf(data),
+			"total":  {Type: "integer", Description: "Number of items in the whole collection."},
+			"next":   {Type: "string", Description: "Link to the next page, if any."},
+			"prev":   {Type: "string", Description: "Link to the previous page, if any."},
+		},
+		Required:             []string{"status", "data", "total"},
+		AdditionalProperties: false,
+	})
+}
+
+func jsonResponse(description string, schema *Schema) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]*MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// Returns a response carrying a file of the given media type.
+func fileResponse(description string, mediaType string) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]*MediaType{mediaType: {Schema: &Schema{Type: "string", Format: "binary"}}},
+	}
+}
+
+// Returns a response redirecting the client elsewhere.
+func redirect(description string) *Response {
+	return &Response{Description: description}
+}
+
+// A field of a form.
+type field struct {
+	name     string
+	schema   *Schema
+	required bool
+}
+
+// Returns a form field holding text, or one of values if any are given.
+func textField(name string, required bool, description string, values ...string) field {
+	return field{name: name, schema: described(enumSchema("string", values...), description), required: required}
+}
+
+// Returns a form field holding a whole number.
+func integerField(name string, required bool, description string) field {
+	return field{name: name, schema: described(&Schema{Type: "integer"}, description), required: required}
+}
+
+// Returns a form field holding a number.
+func numberField(name string, required bool, description string) field {
+	return field{name: name, schema: described(&Schema{Type: "number"}, description), required: required}
+}
+
+// Returns a form field holding "true" or "false".
+func booleanField(name string, required bool, description string) field {
+	return field{name: name, schema: described(&Schema{Type: "boolean"}, description), required: required}
+}
+
+// Returns a form field holding an uploaded file.
+func fileField(name string, required bool, description string) field {
+	return field{name: name, schema: described(&Schema{Type: "string", Format: "binary"}, description), required: required}
+}
+
+// Returns a form field holding any number of uploaded files.
+func filesField(name string, required bool, description string) field {
+	return field{
+		name:     name,
+		schema:   described(&Schema{Type: "array", Items: &Schema{Type: "string", Format: "binary"}}, description),
+		required: required,
+	}
+}
+
+// Returns a request body made of a form. Forms without files may also be sent
+// URL encoded.
+func form(fields ...field) *RequestBody {
+	var schema Schema = Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	var hasFiles, required bool
+
+	for _, field := range fields {
+		schema.Properties[field.name] = field.schema
+		hasFiles = hasFiles || field.schema.Format == "binary" || field.schema.Items != nil
+
+		if field.required {
+			schema.Required = append(schema.Required, field.name)
+			required = true
+		}
+	}
+
+	var body RequestBody = RequestBody{
+		Required: required,
+		Content:  map[string]*MediaType{"multipart/form-data": {Schema: &schema}},
+	}
+
+	if !hasFiles {
+		body.Content["application/x-www-form-urlencoded"] = &MediaType{Schema: &schema}
+	}
+
+	return &body
+}
+
+// Returns a request body patching the members fields describe, as a JSON Merge
+// Patch, in which null removes a member and which may also be sent as plain
+// JSON, as a JSON Patch, or as a form of the members to replace.
+func patchBody(fields ...field) *RequestBody {
+	var body *RequestBody = form(fields...)
+	var merge Schema = Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+
+	for _, field := range fields {
+		merge.Properties[field.name] = nullable(field.schema)
+	}
+
+	body.Required = true
+	body.Content["application/merge-patch+json"] = &MediaType{Schema: &merge}
+	body.Content["application/json"] = &MediaType{Schema: &merge}
+	body.Content["application/json-patch+json"] = &MediaType{Schema: &Schema{
+		Type: "array",
+		Items: &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"op":    enumSchema("string", "add", "remove", "replace", "move", "copy", "test"),
+				"path":  described(&Schema{Type: "string"}, "JSON Pointer to the member to change, such as /title."),
+				"from":  described(&Schema{Type: "string"}, "JSON Pointer to the member to move or copy."),
+				"value": {},
+			},
+			Required:             []string{"op", "path"},
+			AdditionalProperties: false,
+		},
+	}}
+
+	return body
+}
+
+// Returns a path parameter.
+func pathParameter(name string, description string) *Parameter {
+	return &Parameter{Name: name, In: "path", Description: description, Required: true, Schema: &Schema{Type: "string"}}
+}
+
+// Returns an optional query parameter holding text, or one of values if any
+// are given.
+func textQuery(name string, description string, values ...string) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Schema: enumSchema("string", values...)}
+}
+
+// Returns an optional query parameter holding a whole number.
+func integerQuery(name string, description string) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "integer"}}
+}
+
+// Returns the query parameters of routes returning pages of a collection of
+// things, defaultLimit to a page by default.
+func pageQueries(things string, defaultLimit int) []*Parameter {
+	return []*Parameter{
+		integerQuery("limit", fmt.Sprintf("Number of %s per page, %d by default and at most 100.", things, defaultLimit)),
+		textQuery("after", "Cursor of the page to return, from the next link of a page."),
+		textQuery("before", "Cursor of the page to return, from the prev link of a page."),
+	}
+}
+
+// Returns the query parameters that filter and sort the library.
+func libraryQueries(filterExample string) []*Parameter {
+	return []*Parameter{
+		textQuery("filter", fmt.Sprintf("Filter expression, such as `%s`.", filterExample)),
+		textQuery("sort", "Comma separated fields to sort by, descending if prefixed with a minus, such as `-rating,title`."),
+	}
+}
+
+// Returns a schema of type, limited to values if any are given.
+func enumSchema(typeName string, values ...string) *Schema {
+	var schema Schema = Schema{Type: typeName}
+
+	for _, value := range values {
+		schema.Enum = append(schema.Enum, value)
+	}
+
+	return &schema
+}
+
+func described(schema *Schema, description string) *Schema {
+	schema.Description = description
+	return schema
+}
+
+// Returns the name of one of a collection of things, such as "content rating"
+// for "content-ratings".
+func singular(collection string) string {
+	if collection == "people" {
+		return "person"
+	}
+
+	return strings.ReplaceAll(strings.TrimSuffix(collection, "s"), "-", " ")
+}
+
+func hasParameter(parameters []*Parameter, name string, in string) bool {
+	for _, parameter := range parameters {
+		if parameter.Name == name && parameter.In == in {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns an operation id made from the method and path of a route, such as
+// "getShowsByIdCover" for "GET /api/v1/shows/{id}/cover".
+func operationId(method string, path string) string {
+	var builder strings.Builder
+	builder.WriteString(strings.ToLower(method))
+
+	for _, segment := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(path, "/api/v1"), "/api"), "/") {
+		if strings.HasPrefix(segment, "{") {
+			builder.WriteString("By")
+			segment = strings.Trim(segment, "{}")
+		}
+
+		for _, word := range strings.FieldsFunc(segment, func(character rune) bool { return character == '-' }) {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			builder.WriteString(string(runes))
+		}
+	}
+
+	return builder.String()
+}
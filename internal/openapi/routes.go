@@ -0,0 +1,1354 @@
+package openapi
+
+import (
+	"reflect"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/graphql"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns the document describing every route registered in handlers.go. It
+// must be kept in step with them, which the validation middleware checks.
+func New() *Document {
+	var document Document = Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:   "Watchify",
+			Version: "1",
+			Description: "Self-hosted media server. Errors are sent as problem details, data in an envelope " +
+				"holding the status along with it, and pages of collections in an envelope holding the total " +
+				"and the links to the next and previous pages.",
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"session": {
+					Type:        "apiKey",
+					In:          "cookie",
+					Name:        access.SessionCookie,
+					Description: "Session cookie set by /api/v1/auth/login.",
+				},
+				"token": {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "API token created through /api/v1/tokens.",
+				},
+			},
+		},
+		Security:       []Requirement{{"session": {}}, {"token": {}}},
+		componentTypes: map[string]reflect.Type{},
+	}
+
+	document.library()
+	document.profiles()
+	document.accounts()
+	document.viewing()
+
+	// Single resources stored in versioned tables carry ETags.
+	document.versioned(
+		"/api/v1/videos/{id}",
+		"/api/v1/shows/{id}",
+		"/api/v1/movies/{id}",
+		"/api/v1/tags/{id}",
+		"/api/v1/people/{id}",
+		"/api/v1/content-ratings/{id}",
+		"/api/v1/profiles/{id}",
+		"/api/v1/users/{id}",
+		"/api/v1/credits/{id}",
+	)
+
+	// Changes to the parts of a resource are conditioned on the ETag of the
+	// resource, which they change as well.
+	document.conditional(
+		"PUT /api/v1/shows/{id}/cover",
+		"DELETE /api/v1/shows/{id}/cover",
+		"PUT /api/v1/movies/{id}/cover",
+		"DELETE /api/v1/movies/{id}/cover",
+		"PUT /api/v1/people/{id}/headshot",
+		"DELETE /api/v1/people/{id}/headshot",
+		"PUT /api/v1/shows/{id}/content-rating",
+		"PUT /api/v1/movies/{id}/content-rating",
+		"PUT /api/v1/videos/{id}/content-rating",
+		"PUT /api/v1/shows/{id}/tags/{tagId}",
+		"DELETE /api/v1/shows/{id}/tags/{tagId}",
+		"PUT /api/v1/movies/{id}/tags/{tagId}",
+		"DELETE /api/v1/movies/{id}/tags/{tagId}",
+		"PUT /api/v1/profiles/{id}/avatar",
+		"DELETE /api/v1/profiles/{id}/avatar",
+		"PUT /api/v1/users/{id}/role",
+		"DELETE /api/v1/users/{id}/2fa",
+	)
+
+	document.public("GET /api/openapi.json", Operation{
+		Summary: "Returns this document.",
+		Tags:    []string{"openapi"},
+		Responses: map[string]*Response{
+			"200": jsonResponse("The OpenAPI document of the API.", &Schema{Type: "object"}),
+		},
+	})
+
+	return &document
+}
+
+// Shows, movies, episodes and everything attached to them.
+func (document *Document) library() {
+	document.route("GET /api/v1/stream/{id}", access.Browse, Operation{
+		Summary: "Streams the video of an episode or movie, supporting range requests.",
+		Parameters: []*Parameter{
+			pathParameter("id", "UUID of the episode or movie."),
+			textQuery("type", `"show" to stream an episode, a movie otherwise.`),
+		},
+		Responses: map[string]*Response{
+			"200": fileResponse("The whole video.", "video/mp4"),
+			"206": fileResponse("The requested range of the video.", "video/mp4"),
+			"304": redirect("The video was not modified since the client retrieved it."),
+			"400": document.problem(),
+			"404": document.problem(),
+			"416": fileResponse("The requested range lies outside the video.", "text/plain"),
+		},
+	})
+
+	document.route("GET /api/v1/videos/{id}", access.Browse, Operation{
+		Summary: "Returns an episode along with links to the next and previous episodes.",
+		Parameters: []*Parameter{
+			pathParameter("id", "UUID of the episode."),
+		},
+		Responses: map[string]*Response{
+			"200": document.status("The episode.", types.Episode{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/videos", access.Browse, Operation{
+		Summary: "Always fails, episodes are listed through /api/v1/shows/{id}/episodes.",
+		Responses: map[string]*Response{
+			"400": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/videos", access.EditLibrary, Operation{
+		Summary: "Uploads an episode to a show.",
+		RequestBody: form(
+			fileField("video", true, "Uploaded video file."),
+			textField("show-id", true, "UUID of the show."),
+			integerField("season_number", false, "Season of the episode, 1 by default."),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The episode was uploaded.", nil),
+			"400": document.status("The form could not be read.", nil),
+		},
+	})
+
+	document.route("DELETE /api/v1/videos/{id}", access.DeleteLibrary, Operation{
+		Summary: "Deletes an episode along with its video.",
+		Parameters: []*Parameter{
+			pathParameter("id", "UUID of the episode."),
+		},
+		Responses: map[string]*Response{
+			"200": document.status("The episode was deleted.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	for _, collection := range []string{"shows", "movies", "videos"} {
+		document.route("PUT /api/v1/"+collection+"/{id}/content-rating", access.EditLibrary, Operation{
+			Summary: "Assigns a content rating, or clears it.",
+			RequestBody: form(
+				textField("content_rating_id", false, "UUID of the content rating, empty to clear it."),
+			),
+			Responses: map[string]*Response{
+				"200": document.status("The content rating was assigned.", nil),
+				"400": document.problem(),
+				"404": document.problem(),
+			},
+		})
+	}
+
+	document.route("GET /api/v1/shows", access.Browse, Operation{
+		Summary: "Returns a page of shows.",
+		Parameters: append(append([]*Parameter{
+			textQuery("tags", "Comma separated tag ids or names to filter by."),
+			textQuery("match", `"all" to require every tag, any of them otherwise.`, "all", "any"),
+			textQuery("orderedBy", `"upload_date" for newest first, "rating" for best rated first, by title otherwise.`),
+		}, libraryQueries("year>=2000 and tag:anime")...), pageQueries("shows", 15)...),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of shows.", []types.Show{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/shows", access.EditLibrary, Operation{
+		Summary: "Uploads a show along with its episodes and cover.",
+		RequestBody: form(
+			textField("title", false, "Title of the show."),
+			textField("description", false, "Description of the show."),
+			integerField("season_number", false, "Season of the uploaded episodes, 1 by default."),
+			filesField("videos", true, "Uploaded episodes, in order."),
+			fileField("cover", true, "Uploaded cover, should be a 400x600 jpg."),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The show was uploaded.", types.Show{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/shows/{id}", access.Browse, Operation{
+		Summary: "Returns a show.",
+		Responses: map[string]*Response{
+			"200": document.status("The show.", types.Show{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/shows/{id}", access.EditLibrary, Operation{
+		Summary: "Changes the title or description of a show.",
+		RequestBody: form(
+			textField("title", false, "Title of the show, at most 50 characters."),
+			textField("description", false, "Description of the show, at most 1000 characters."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The show was changed.", nil),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("PATCH /api/v1/shows/{id}", access.EditLibrary, Operation{
+		Summary: "Changes the title or description of a show, leaving out what isn't patched.",
+		RequestBody: patchBody(
+			textField("title", false, "Title of the show, at most 50 characters."),
+			textField("description", false, "Description of the show, at most 1000 characters."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The changed show.", types.Show{}),
+			"400": document.problem(),
+			"404": document.problem(),
+			"409": document.problem(),
+			"415": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/shows/{id}", access.DeleteLibrary, Operation{
+		Summary: "Deletes a show along with its episodes and cover.",
+		Responses: map[string]*Response{
+			"200": document.status("The show was deleted.", nil),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/shows/{id}/episodes", access.Browse, Operation{
+		Summary:    "Returns a page of the episodes of a show, by season and episode unless sorted otherwise.",
+		Parameters: append(libraryQueries(`season=2 and title~"finale"`), pageQueries("episodes", 50)...),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of episodes.", []types.Episode{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/movies", access.Browse, Operation{
+		Summary: "Returns a page of movies.",
+		Parameters: append(append([]*Parameter{
+			textQuery("hidden", `"true" to include hidden movies.`),
+			textQuery("tags", "Comma separated tag ids or names to filter by."),
+			textQuery("match", `"all" to require every tag, any of them otherwise.`, "all", "any"),
+			textQuery("orderedBy", `"upload_date" for newest first, "rating" for best rated first, by title otherwise.`),
+		}, libraryQueries("year>=2000 and tag:anime")...), pageQueries("movies", 30)...),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of movies.", []types.Movie{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/movies", access.EditLibrary, Operation{
+		Summary: "Uploads a movie along with its cover.",
+		RequestBody: form(
+			textField("title", false, "Title of the movie."),
+			textField("description", false, "Description of the movie."),
+			textField("hidden", false, `"true" to hide the movie from listings.`),
+			fileField("video", true, "Uploaded video file."),
+			fileField("cover", true, "Uploaded cover, should be a 400x600 jpg."),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The movie was uploaded.", types.Movie{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/movies/{id}", access.Browse, Operation{
+		Summary: "Returns a movie.",
+		Responses: map[string]*Response{
+			"200": document.status("The movie.", types.Movie{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/movies/{id}", access.EditLibrary, Operation{
+		Summary: "Changes the title, description or visibility of a movie.",
+		RequestBody: form(
+			textField("title", false, "Title of the movie, at most 50 characters."),
+			textField("description", false, "Description of the movie, at most 1000 characters."),
+			textField("hidden", false, `"true" to hide the movie from listings.`),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The movie was changed.", nil),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PATCH /api/v1/movies/{id}", access.EditLibrary, Operation{
+		Summary: "Changes the title, description or visibility of a movie, leaving out what isn't patched.",
+		RequestBody: patchBody(
+			textField("title", false, "Title of the movie, at most 50 characters."),
+			textField("description", false, "Description of the movie, at most 1000 characters."),
+			booleanField("hidden", false, "Whether to hide the movie from listings."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The changed movie.", types.Movie{}),
+			"400": document.problem(),
+			"404": document.problem(),
+			"409": document.problem(),
+			"415": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/movies/{id}", access.DeleteLibrary, Operation{
+		Summary: "Deletes a movie along with its cover.",
+		Responses: map[string]*Response{
+			"200": document.status("The movie was deleted.", nil),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/movies/{id}/similar", access.Browse, Operation{
+		Summary: "Returns movies similar to a movie.",
+		Parameters: []*Parameter{
+			integerQuery("limit", "Number of movies to return, 20 by default and at most 50."),
+		},
+		Responses: map[string]*Response{
+			"200": document.status("Similar movies, best match first.", []types.Recommendation{}),
+			"404": document.problem(),
+		},
+	})
+
+	for _, pattern := range []string{"/api/v1/shows/{id}/cover", "/api/v1/movies/{id}/cover", "/api/v1/people/{id}/headshot"} {
+		document.route("GET "+pattern, access.Browse, Operation{
+			Summary: "Returns the image, or a placeholder if there is none.",
+			Responses: map[string]*Response{
+				"200": fileResponse("The image.", "image/jpeg"),
+			},
+		})
+
+		document.route("PUT "+pattern, access.EditLibrary, Operation{
+			Summary: "Replaces the image with the uploaded one.",
+			RequestBody: form(
+				fileField("cover", true, "Uploaded image, should be a 400x600 jpg."),
+			),
+			Responses: map[string]*Response{
+				"200": document.status("The image was replaced.", nil),
+				"400": document.problem(),
+			},
+		})
+
+		document.route("DELETE "+pattern, access.EditLibrary, Operation{
+			Summary: "Deletes the image.",
+			Responses: map[string]*Response{
+				"200": document.status("The image was deleted.", nil),
+			},
+		})
+	}
+
+	for _, collection := range []string{"shows", "movies"} {
+		document.route("GET /api/v1/"+collection+"/{id}/tags", access.Browse, Operation{
+			Summary: "Returns the tags attached to the content.",
+			Responses: map[string]*Response{
+				"200": document.status("The tags.", []types.Tag{}),
+				"404": document.problem(),
+			},
+		})
+
+		document.route("PUT /api/v1/"+collection+"/{id}/tags/{tagId}", access.EditLibrary, Operation{
+			Summary: "Attaches a tag to the content.",
+			Responses: map[string]*Response{
+				"200": document.status("The tag was attached.", nil),
+				"404": document.problem(),
+			},
+		})
+
+		document.route("DELETE /api/v1/"+collection+"/{id}/tags/{tagId}", access.EditLibrary, Operation{
+			Summary: "Detaches a tag from the content.",
+			Responses: map[string]*Response{
+				"200": document.status("The tag was detached.", nil),
+				"404": document.problem(),
+			},
+		})
+	}
+
+	document.route("GET /api/v1/tags/facets", access.Browse, Operation{
+		Summary: "Returns the tags of the shows or movies matching a tag filter, along with how many of them carry each tag.",
+		Parameters: append([]*Parameter{
+			{Name: "type", In: "query", Required: true, Description: "Type of content to count.", Schema: enumSchema("string", "shows", "movies")},
+			textQuery("tags", "Comma separated tag ids or names to filter by."),
+			textQuery("match", `"all" to require every tag, any of them otherwise.`, "all", "any"),
+		}, pageQueries("tags", 50)...),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of the tags along with their counts.", []types.Tag{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/tags", access.Browse, Operation{
+		Summary: "Returns a page of tags.",
+		Parameters: append([]*Parameter{
+			textQuery("kind", "Only return tags of this kind.", "genre", "tag"),
+		}, pageQueries("tags", 50)...),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of tags.", []types.Tag{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/tags", access.EditLibrary, Operation{
+		Summary: "Creates a tag.",
+		RequestBody: form(
+			textField("name", true, "Name of the tag, unique regardless of case."),
+			textField("kind", false, `Kind of the tag, "tag" by default.`, "genre", "tag"),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The tag.", types.Tag{}),
+			"400": document.problem(),
+			"409": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/tags/{id}", access.Browse, Operation{
+		Summary: "Returns a tag.",
+		Responses: map[string]*Response{
+			"200": document.status("The tag.", types.Tag{}),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/tags/{id}", access.EditLibrary, Operation{
+		Summary: "Renames a tag or changes its kind.",
+		RequestBody: form(
+			textField("name", false, "New name of the tag."),
+			textField("kind", false, "New kind of the tag.", "genre", "tag"),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The tag.", types.Tag{}),
+			"400": document.problem(),
+			"404": document.problem(),
+			"409": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/tags/{id}", access.EditLibrary, Operation{
+		Summary: "Deletes a tag, detaching it from all content.",
+		Responses: map[string]*Response{
+			"200": document.status("The tag was deleted.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/people", access.Browse, Operation{
+		Summary: "Returns a page of people.",
+		Parameters: append([]*Parameter{
+			textQuery("name", "Only return people whose name contains this value."),
+		}, pageQueries("people", 50)...),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of people.", []types.Person{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/people", access.EditLibrary, Operation{
+		Summary: "Creates a person.",
+		RequestBody: form(
+			textField("name", true, "Full name of the person."),
+			textField("biography", false, "Short biography of the person."),
+			fileField("headshot", false, "Uploaded headshot, should be a 400x600 jpg."),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The person.", types.Person{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/people/{id}", access.Browse, Operation{
+		Summary: "Returns a person along with their filmography.",
+		Responses: map[string]*Response{
+			"200": document.status("The person.", types.Person{}),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/people/{id}", access.EditLibrary, Operation{
+		Summary: "Changes the name or biography of a person.",
+		RequestBody: form(
+			textField("name", false, "Full name of the person."),
+			textField("biography", false, "Short biography of the person."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The person.", types.Person{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/people/{id}", access.DeleteLibrary, Operation{
+		Summary: "Deletes a person along with their credits.",
+		Responses: map[string]*Response{
+			"200": document.status("The person was deleted.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/credits", access.EditLibrary, Operation{
+		Summary: "Credits a person on a movie, show or episode.",
+		RequestBody: form(
+			textField("person_id", true, "UUID of the credited person."),
+			textField("parent_id", true, "UUID of the movie, show or episode."),
+			textField("parent_type", true, "Type of the content.", "movie", "show", "episode"),
+			textField("role", true, "Role of the person.", "actor", "director", "writer"),
+			textField("character", false, "Name of the played character, actors only."),
+			integerField("position", false, "Billing order within the content, 0 by default."),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The credit.", types.Credit{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/credits/{id}", access.EditLibrary, Operation{
+		Summary: "Changes a credit.",
+		RequestBody: form(
+			textField("role", false, "Role of the person.", "actor", "director", "writer"),
+			textField("character", false, "Name of the played character, actors only."),
+			integerField("position", false, "Billing order within the content."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The credit.", types.Credit{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/credits/{id}", access.EditLibrary, Operation{
+		Summary: "Deletes a credit.",
+		Responses: map[string]*Response{
+			"200": document.status("The credit was deleted.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/content-ratings", access.Browse, Operation{
+		Summary: "Returns a page of the content ratings, by system and from least to most mature.",
+		Parameters: append([]*Parameter{
+			textQuery("system", "Only return ratings of this system."),
+		}, pageQueries("ratings", 50)...),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of content ratings.", []types.ContentRating{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/content-ratings", access.EditLibrary, Operation{
+		Summary: "Creates a content rating.",
+		RequestBody: form(
+			textField("system", true, `Rating system, such as "MPAA", "TV" or "BBFC".`),
+			textField("code", true, "Rating within the system, unique per system."),
+			integerField("rank", true, "Position on the shared scale, higher is more mature."),
+			textField("description", false, "Short explanation of the rating."),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The content rating.", types.ContentRating{}),
+			"400": document.problem(),
+			"409": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/content-ratings/{id}", access.Browse, Operation{
+		Summary: "Returns a content rating.",
+		Responses: map[string]*Response{
+			"200": document.status("The content rating.", types.ContentRating{}),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/content-ratings/{id}", access.EditLibrary, Operation{
+		Summary: "Changes a content rating.",
+		RequestBody: form(
+			textField("code", false, "Rating within the system."),
+			integerField("rank", false, "Position on the shared scale."),
+			textField("description", false, "Short explanation of the rating."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The content rating.", types.ContentRating{}),
+			"400": document.problem(),
+			"404": document.problem(),
+			"409": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/content-ratings/{id}", access.EditLibrary, Operation{
+		Summary: "Deletes a content rating that is not in use.",
+		Responses: map[string]*Response{
+			"200": document.status("The content rating was deleted.", nil),
+			"404": document.problem(),
+			"409": document.problem(),
+		},
+	})
+}
+
+// Profiles of users and what they watched.
+func (document *Document) profiles() {
+	document.route("GET /api/v1/profiles", access.Browse, Operation{
+		Summary:    "Returns a page of the profiles of the logged in user.",
+		Parameters: pageQueries("profiles", 50),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of profiles.", []types.Profile{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/profiles", access.ManageProfiles, Operation{
+		Summary: "Creates a profile for the logged in user.",
+		RequestBody: form(
+			textField("name", true, "Display name of the profile."),
+			textField("audio_language", false, `Preferred audio language as a BCP 47 tag, such as "en" or "pt-BR".`),
+			textField("subtitle_language", false, "Preferred subtitle language as a BCP 47 tag."),
+			textField("max_content_rating_id", false, "UUID of the most mature rating the profile may watch."),
+			textField("pin", false, "4 to 8 digit PIN, required with max_content_rating_id."),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The profile.", types.Profile{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/profiles/selected", access.Browse, Operation{
+		Summary: "Returns the profile requests are scoped to, if any.",
+		Responses: map[string]*Response{
+			"200": document.status("The selected profile, left out if none is.", types.Profile{}),
+		},
+	})
+
+	document.route("PUT /api/v1/profiles/selected", access.Browse, Operation{
+		Summary: "Scopes requests made with the current session or API token to a profile.",
+		RequestBody: form(
+			textField("profile_id", true, "UUID of the profile."),
+			textField("pin", false, "PIN of the profile, or of the selected profile when leaving it requires one."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The selected profile.", types.Profile{}),
+			"400": document.problem(),
+			"404": document.problem(),
+			"429": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/profiles/selected", access.Browse, Operation{
+		Summary: "Stops scoping requests to the selected profile.",
+		RequestBody: form(
+			textField("pin", false, "PIN of the selected profile, required if it is limited."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("No profile is selected anymore.", nil),
+			"429": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/profiles/{id}", access.Browse, Operation{
+		Summary: "Returns a profile of the logged in user.",
+		Responses: map[string]*Response{
+			"200": document.status("The profile.", types.Profile{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/profiles/{id}", access.ManageProfiles, Operation{
+		Summary: "Changes a profile of the logged in user.",
+		RequestBody: form(
+			textField("name", false, "Display name of the profile."),
+			textField("audio_language", false, "Preferred audio language as a BCP 47 tag, empty to clear it."),
+			textField("subtitle_language", false, "Preferred subtitle language as a BCP 47 tag, empty to clear it."),
+			textField("max_content_rating_id", false, "UUID of the most mature rating, empty to lift the limit."),
+			textField("pin", false, "Current PIN, required when changing the limit or PIN."),
+			textField("new_pin", false, "New 4 to 8 digit PIN."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The profile.", types.Profile{}),
+			"400": document.problem(),
+			"404": document.problem(),
+			"429": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/profiles/{id}", access.ManageProfiles, Operation{
+		Summary: "Deletes a profile of the logged in user along with what it watched.",
+		RequestBody: form(
+			textField("pin", false, "PIN of the profile, required if it has one."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The profile was deleted.", nil),
+			"404": document.problem(),
+			"429": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/profiles/{id}/avatar", access.Browse, Operation{
+		Summary: "Returns the avatar of a profile, or a placeholder if it has none.",
+		Responses: map[string]*Response{
+			"200": fileResponse("The avatar.", "image/jpeg"),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/profiles/{id}/avatar", access.ManageProfiles, Operation{
+		Summary: "Replaces the avatar of a profile with the uploaded image.",
+		RequestBody: form(
+			fileField("cover", true, "Uploaded image, should be a square jpg."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The avatar was replaced.", nil),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/profiles/{id}/avatar", access.ManageProfiles, Operation{
+		Summary: "Deletes the avatar of a profile.",
+		Responses: map[string]*Response{
+			"200": document.status("The avatar was deleted.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/profiles/{id}/history", access.Browse, Operation{
+		Summary:    "Returns a page of what a profile watched, most recent first.",
+		Parameters: pageQueries("entries", 50),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of the history.", []types.WatchHistoryEntry{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/profiles/{id}/history", access.ManageProfiles, Operation{
+		Summary: "Clears the history of a profile.",
+		RequestBody: form(
+			textField("pin", false, "PIN of the profile, required if it has one."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The history was cleared.", nil),
+			"404": document.problem(),
+			"429": document.problem(),
+		},
+	})
+}
+
+// Logging in, users, API tokens and server settings.
+func (document *Document) accounts() {
+	document.public("POST /api/v1/auth/login", Operation{
+		Summary: "Logs a user in, setting the session cookie unless a second factor is required.",
+		RequestBody: form(
+			textField("username", true, "Username of the user."),
+			textField("password", true, "Password of the user."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The user was logged in.", types.User{}),
+			"202": document.status("A TOTP code must be sent to /api/v1/auth/login/2fa along with the challenge.", types.LoginChallenge{}),
+			"401": document.problem(),
+		},
+	})
+
+	document.public("POST /api/v1/auth/login/2fa", Operation{
+		Summary: "Finishes logging in with a TOTP or recovery code.",
+		RequestBody: form(
+			textField("challenge", true, "Challenge returned by /api/v1/auth/login."),
+			textField("code", true, "TOTP code or recovery code."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The user was logged in.", types.User{}),
+			"401": document.problem(),
+			"429": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/auth/logout", "", Operation{
+		Summary: "Ends the current session.",
+		Responses: map[string]*Response{
+			"200": document.status("The session was ended.", nil),
+		},
+	})
+
+	document.route("GET /api/v1/auth/me", "", Operation{
+		Summary: "Returns the logged in user.",
+		Responses: map[string]*Response{
+			"200": document.status("The logged in user.", types.User{}),
+		},
+	})
+
+	document.route("POST /api/v1/auth/2fa", "", Operation{
+		Summary: "Starts enrolling the logged in user in two-factor authentication.",
+		Responses: map[string]*Response{
+			"200": document.status("The secret to confirm with /api/v1/auth/2fa/confirm.", types.TwoFactorEnrollment{}),
+			"409": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/auth/2fa/confirm", "", Operation{
+		Summary: "Enables two-factor authentication once a code of the new secret is confirmed.",
+		RequestBody: form(
+			textField("code", true, "TOTP code of the new secret."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The recovery codes, which are only shown once.", []string{}),
+			"400": document.problem(),
+			"409": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/auth/2fa/recovery-codes", "", Operation{
+		Summary: "Replaces the recovery codes of the logged in user.",
+		RequestBody: form(
+			textField("code", true, "TOTP code or recovery code."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The new recovery codes, which are only shown once.", []string{}),
+			"400": document.problem(),
+			"409": document.problem(),
+			"429": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/auth/2fa", "", Operation{
+		Summary: "Disables two-factor authentication for the logged in user.",
+		RequestBody: form(
+			textField("code", true, "TOTP code or recovery code."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("Two-factor authentication was disabled.", nil),
+			"400": document.problem(),
+			"409": document.problem(),
+			"429": document.problem(),
+		},
+	})
+
+	document.public("GET /api/v1/auth/oidc/login", Operation{
+		Summary: "Redirects to the single sign-on provider to log in.",
+		Tags:    []string{"auth"},
+		Responses: map[string]*Response{
+			"302": redirect("Redirect to the provider."),
+			"404": document.problem(),
+			"502": document.problem(),
+		},
+	})
+
+	document.public("GET /api/v1/auth/oidc/callback", Operation{
+		Summary: "Finishes logging in or linking an account once the provider redirects back.",
+		Tags:    []string{"auth"},
+		Parameters: []*Parameter{
+			textQuery("code", "Authorization code issued by the provider."),
+			textQuery("state", "State of the pending login."),
+			textQuery("error", "Error reported by the provider, if any."),
+			textQuery("error_description", "Explanation of the error reported by the provider."),
+			textQuery("iss", "Issuer of the response."),
+			textQuery("session_state", "Session of the provider."),
+		},
+		Responses: map[string]*Response{
+			"302": redirect("Redirect to the landing page of the client."),
+			"400": document.problem(),
+			"401": document.problem(),
+			"404": document.problem(),
+			"409": document.problem(),
+			"429": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/auth/oidc/link", "", Operation{
+		Summary: "Redirects to the single sign-on provider to link it to the logged in user.",
+		Tags:    []string{"auth"},
+		Responses: map[string]*Response{
+			"302": redirect("Redirect to the provider."),
+			"404": document.problem(),
+			"502": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/auth/oidc/link", "", Operation{
+		Summary: "Unlinks the single sign-on provider from the logged in user.",
+		Tags:    []string{"auth"},
+		Responses: map[string]*Response{
+			"200": document.status("The provider was unlinked.", nil),
+			"409": document.problem(),
+		},
+	})
+
+	document.public("GET /api/v1/setup", Operation{
+		Summary: "Reports whether the server still needs its first user.",
+		Tags:    []string{"users"},
+		Responses: map[string]*Response{
+			"200": document.status("Whether setup is required.", map[string]bool{}),
+		},
+	})
+
+	document.public("POST /api/v1/setup", Operation{
+		Summary: "Creates the first user, as an admin, and logs them in.",
+		Tags:    []string{"users"},
+		RequestBody: form(
+			textField("username", true, "3 to 32 letters, digits, dots, dashes or underscores."),
+			textField("password", true, "8 to 72 bytes."),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The user.", types.User{}),
+			"400": document.problem(),
+			"409": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/roles", "", Operation{
+		Summary: "Returns the roles along with the permissions they grant.",
+		Tags:    []string{"users"},
+		Responses: map[string]*Response{
+			"200": document.status("The roles.", []types.Role{}),
+		},
+	})
+
+	document.route("GET /api/v1/users", access.ManageUsers, Operation{
+		Summary:    "Returns a page of users.",
+		Parameters: pageQueries("users", 50),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of users.", []types.User{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/users", access.ManageUsers, Operation{
+		Summary: "Creates a user.",
+		RequestBody: form(
+			textField("username", true, "3 to 32 letters, digits, dots, dashes or underscores."),
+			textField("password", true, "8 to 72 bytes."),
+			textField("role", false, `Role of the user, "viewer" by default.`, "admin", "uploader", "viewer", "guest"),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The user.", types.User{}),
+			"400": document.problem(),
+			"409": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/users/{id}", "", Operation{
+		Summary: "Returns a user. Users without the manage_users permission can only retrieve themselves.",
+		Responses: map[string]*Response{
+			"200": document.status("The user.", types.User{}),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/users/{id}", "", Operation{
+		Summary: "Changes the username or password of a user. Users without the manage_users permission can only change themselves.",
+		RequestBody: form(
+			textField("username", false, "New username."),
+			textField("password", false, "New password, which ends every other session of the user and revokes their API tokens."),
+			textField("current_password", false, "Current password, required when users change themselves."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The user.", types.User{}),
+			"400": document.problem(),
+			"404": document.problem(),
+			"409": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/users/{id}", access.ManageUsers, Operation{
+		Summary: "Deletes a user along with their sessions, tokens and profiles.",
+		Responses: map[string]*Response{
+			"200": document.status("The user was deleted.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/users/{id}/role", access.ManageUsers, Operation{
+		Summary: "Changes the role of a user.",
+		RequestBody: form(
+			textField("role", true, "New role of the user.", "admin", "uploader", "viewer", "guest"),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The user.", types.User{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/users/{id}/2fa", access.ManageUsers, Operation{
+		Summary: "Disables two-factor authentication for a user who lost their codes.",
+		Responses: map[string]*Response{
+			"200": document.status("Two-factor authentication was disabled.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/tokens", "", Operation{
+		Summary:    "Returns a page of the API tokens of the logged in user, without the tokens themselves.",
+		Parameters: pageQueries("tokens", 50),
+		Responses: map[string]*Response{
+			"200": document.paged("A page of API tokens.", []types.ApiToken{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("POST /api/v1/tokens", "", Operation{
+		Summary: "Creates an API token for the logged in user.",
+		RequestBody: form(
+			textField("name", true, "What the token is used for."),
+			textField("scope", true, "What the token may do.", "read", "upload", "admin"),
+			integerField("expires_in_days", false, "Number of days the token stays valid, never expires if empty."),
+		),
+		Responses: map[string]*Response{
+			"201": document.status("The API token, along with the token itself, which is only shown once.", types.ApiToken{}),
+			"400": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/tokens/{id}", "", Operation{
+		Summary: "Renames an API token of the logged in user.",
+		RequestBody: form(
+			textField("name", true, "What the token is used for."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The token was renamed.", nil),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/tokens/{id}", "", Operation{
+		Summary: "Revokes an API token of the logged in user.",
+		Responses: map[string]*Response{
+			"200": document.status("The token was revoked.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/settings", access.ManageServer, Operation{
+		Summary: "Returns the server settings.",
+		Responses: map[string]*Response{
+			"200": document.status("The settings, keyed by their names.", map[string]string{}),
+		},
+	})
+
+	document.route("PUT /api/v1/settings", access.ManageServer, Operation{
+		Summary: "Changes the server settings present in the form.",
+		RequestBody: form(
+			textField("require_two_factor", false, "Whether admins and uploaders must enable two-factor authentication.", "true", "false"),
+			numberField("watched_threshold", false, "Percentage of a video that must be played for it to be marked as watched."),
+			integerField("recommendation_interval", false, "Minutes between recomputing recommendations."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The settings were changed.", nil),
+			"400": document.problem(),
+		},
+	})
+}
+
+// Progress, lists, ratings and everything built from what viewers watch.
+func (document *Document) viewing() {
+	document.route("PUT /api/v1/progress/{mediaId}", access.Browse, Operation{
+		Summary: "Reports the playback position of the viewer in a movie or episode.",
+		Parameters: []*Parameter{
+			pathParameter("mediaId", "UUID of the movie or episode."),
+		},
+		RequestBody: form(
+			numberField("position", true, "Playback position in seconds."),
+			numberField("duration", true, "Length of the video in seconds."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The progress.", types.Progress{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/progress/{mediaId}/watched", access.Browse, Operation{
+		Summary: "Marks a movie or episode as watched.",
+		Parameters: []*Parameter{
+			pathParameter("mediaId", "UUID of the movie or episode."),
+		},
+		Responses: map[string]*Response{
+			"200": document.status("It was marked as watched.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/progress/{mediaId}/watched", access.Browse, Operation{
+		Summary: "Marks a movie or episode as unwatched, clearing the progress.",
+		Parameters: []*Parameter{
+			pathParameter("mediaId", "UUID of the movie or episode."),
+		},
+		Responses: map[string]*Response{
+			"200": document.status("It was marked as unwatched.", nil),
+		},
+	})
+
+	document.route("PUT /api/v1/shows/{id}/watched", access.Browse, Operation{
+		Summary: "Marks every episode of a show, or of one of its seasons, as watched.",
+		Tags:    []string{"progress"},
+		RequestBody: form(
+			integerField("season_number", false, "Only mark the episodes of this season."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("Number of episodes marked, left out if none were.", 0),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/shows/{id}/watched", access.Browse, Operation{
+		Summary: "Marks every episode of a show, or of one of its seasons, as unwatched.",
+		Tags:    []string{"progress"},
+		RequestBody: form(
+			integerField("season_number", false, "Only mark the episodes of this season."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("Number of episodes marked, left out if none were.", 0),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/home/continue", access.Browse, Operation{
+		Summary: "Returns the movies and episodes the viewer started but did not finish, most recent first.",
+		Parameters: []*Parameter{
+			integerQuery("limit", "Number of items to return, 20 by default and at most 100. The feed is ranked anew on every request, so it is not paged."),
+		},
+		Responses: map[string]*Response{
+			"200": document.status("The items.", []types.FeedItem{}),
+		},
+	})
+
+	document.route("GET /api/v1/home/next-up", access.Browse, Operation{
+		Summary: "Returns the next episode of every show the viewer is watching.",
+		Parameters: []*Parameter{
+			integerQuery("limit", "Number of items to return, 20 by default and at most 100. The feed is ranked anew on every request, so it is not paged."),
+		},
+		Responses: map[string]*Response{
+			"200": document.status("The items.", []types.FeedItem{}),
+		},
+	})
+
+	for _, list := range []string{"watchlist", "favorites"} {
+		var mediaId *Parameter = pathParameter("mediaId", "UUID of the show or movie.")
+
+		document.route("GET /api/v1/"+list, access.Browse, Operation{
+			Summary:    "Returns a page of the items on the list of the viewer, in order.",
+			Parameters: pageQueries("items", 50),
+			Responses: map[string]*Response{
+				"200": document.paged("A page of the list.", []types.ListItem{}),
+				"400": document.problem(),
+			},
+		})
+
+		document.route("PUT /api/v1/"+list+"/{mediaId}", access.Browse, Operation{
+			Summary:    "Adds a show or movie to the end of the list of the viewer.",
+			Parameters: []*Parameter{mediaId},
+			Responses: map[string]*Response{
+				"200": document.status("The item.", types.ListItem{}),
+				"404": document.problem(),
+			},
+		})
+
+		document.route("DELETE /api/v1/"+list+"/{mediaId}", access.Browse, Operation{
+			Summary:    "Removes a show or movie from the list of the viewer.",
+			Parameters: []*Parameter{mediaId},
+			Responses: map[string]*Response{
+				"200": document.status("The item was removed.", nil),
+				"404": document.problem(),
+			},
+		})
+
+		document.route("PUT /api/v1/"+list+"/{mediaId}/position", access.Browse, Operation{
+			Summary:    "Moves a show or movie to another place on the list of the viewer.",
+			Parameters: []*Parameter{mediaId},
+			RequestBody: form(
+				integerField("position", true, "New place on the list, starting at 1."),
+			),
+			Responses: map[string]*Response{
+				"200": document.status("The item was moved.", nil),
+				"400": document.problem(),
+				"404": document.problem(),
+			},
+		})
+	}
+
+	var ratedMedia *Parameter = pathParameter("mediaId", "UUID of the movie, show or episode.")
+
+	document.route("GET /api/v1/ratings/{mediaId}", access.Browse, Operation{
+		Summary:    "Returns the average score of a movie, show or episode along with a page of its reviews.",
+		Tags:       []string{"reviews"},
+		Parameters: append([]*Parameter{ratedMedia}, pageQueries("reviews", 20)...),
+		Responses: map[string]*Response{
+			"200": document.paged("The score along with a page of reviews.", struct {
+				Rating  *types.RatingSummary `json:"rating"`
+				Reviews []types.Review       `json:"reviews"`
+			}{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("PUT /api/v1/ratings/{mediaId}", access.Browse, Operation{
+		Summary:    "Rates a movie, show or episode, replacing the rating of the viewer.",
+		Tags:       []string{"reviews"},
+		Parameters: []*Parameter{ratedMedia},
+		RequestBody: form(
+			integerField("score", false, "Score from 1 to 10. Required without thumb."),
+			textField("thumb", false, "Stored as a score of 10 or 1. Required without score.", "up", "down"),
+			textField("review", false, "Review of at most 2000 bytes."),
+		),
+		Responses: map[string]*Response{
+			"200": document.status("The review.", types.Review{}),
+			"400": document.problem(),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("DELETE /api/v1/ratings/{mediaId}", access.Browse, Operation{
+		Summary:    "Deletes the rating of the viewer.",
+		Tags:       []string{"reviews"},
+		Parameters: []*Parameter{ratedMedia},
+		Responses: map[string]*Response{
+			"200": document.status("The rating was deleted.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	for _, method := range []string{"PUT", "DELETE"} {
+		document.route(method+" /api/v1/reviews/{id}/hidden", access.ModerateReviews, Operation{
+			Summary: "Hides a review from other viewers, or shows it again.",
+			Responses: map[string]*Response{
+				"200": document.status("The review was hidden or shown.", nil),
+				"404": document.problem(),
+			},
+		})
+	}
+
+	document.route("DELETE /api/v1/reviews/{id}", access.ModerateReviews, Operation{
+		Summary: "Deletes a review.",
+		Responses: map[string]*Response{
+			"200": document.status("The review was deleted.", nil),
+			"404": document.problem(),
+		},
+	})
+
+	document.route("GET /api/v1/recommendations", access.Browse, Operation{
+		Summary: "Returns shows and movies the viewer may like, best match first.",
+		Parameters: []*Parameter{
+			integerQuery("limit", "Number of items to return, 20 by default and at most 100. The feed is ranked anew on every request, so it is not paged."),
+		},
+		Responses: map[string]*Response{
+			"200": document.status("The recommendations.", []types.Recommendation{}),
+		},
+	})
+
+	document.route("GET /api/v1/suggest", access.Browse, Operation{
+		Summary: "Returns shows, movies, people and tags whose words start with the words typed so far.",
+		Parameters: []*Parameter{
+			{Name: "q", In: "query", Required: true, Description: "What the viewer typed, nothing is suggested for less than two characters.", Schema: &Schema{Type: "string"}},
+			integerQuery("limit", "Number of suggestions to return, 10 by default and at most 25."),
+		},
+		Responses: map[string]*Response{
+			"200": document.status("The suggestions, best match first.", []types.Suggestion{}),
+		},
+	})
+
+	document.route("POST /api/v1/batch", access.EditLibrary, Operation{
+		Summary: "Executes operations on shows, movies and episodes in a single transaction, returning the outcome of each.",
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{"application/json": {Schema: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"atomic": described(&Schema{Type: "boolean"}, "Whether a failing operation rolls back every other one, true by default."),
+					"operations": {
+						Type: "array",
+						Items: &Schema{
+							Type: "object",
+							Properties: map[string]*Schema{
+								"op":       described(enumSchema("string", "hide", "unhide", "tag", "delete", "move"), "Operation to run, deleting requires the delete_library permission."),
+								"id":       described(&Schema{Type: "string"}, "Show or movie to run the operation on, or the episode to move."),
+								"tag_id":   described(&Schema{Type: "string"}, "Tag to attach, for tag operations."),
+								"show_id":  described(&Schema{Type: "string"}, "Series to move the episode to, for move operations."),
+								"if_match": described(&Schema{Type: "string"}, "ETag the show, movie or episode must still match, or * for any, as with If-Match."),
+							},
+							Required: []string{"op", "id", "if_match"},
+						},
+					},
+				},
+				Required: []string{"operations"},
+			}}},
+		},
+		Responses: map[string]*Response{
+			"200": document.status("The outcome of the batch, whether or not it was committed.", types.Batch{}),
+			"400": document.problem(),
+		},
+	})
+
+	var graphqlResponse *Response = jsonResponse("The result of the query.", document.SchemaOf(graphql.Response{}))
+
+	document.route("GET /api/graphql", access.Browse, Operation{
+		Summary: "Executes a GraphQL query over the library.",
+		Parameters: []*Parameter{
+			textQuery("query", "GraphQL query document, unless a persisted query is run."),
+			textQuery("operationName", "Operation to run if the document holds several."),
+			textQuery("variables", "JSON encoded values of the variables of the operation."),
+			textQuery("extensions", `JSON encoded extensions, such as {"persistedQuery": {"version": 1, "sha256Hash": "..."}}.`),
+		},
+		Responses: map[string]*Response{
+			"200": graphqlResponse,
+			"400": graphqlResponse,
+			"500": graphqlOrProblem(graphqlResponse, document.problem()),
+		},
+	})
+
+	document.route("POST /api/graphql", access.Browse, Operation{
+		Summary: "Executes a GraphQL query over the library.",
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{"application/json": {Schema: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"query":         {Type: "string"},
+					"operationName": {Type: []string{"string", "null"}},
+					"variables":     {Type: []string{"object", "null"}},
+					"extensions":    {Type: []string{"object", "null"}},
+				},
+			}}},
+		},
+		Responses: map[string]*Response{
+			"200": graphqlResponse,
+			"400": graphqlResponse,
+			"500": graphqlOrProblem(graphqlResponse, document.problem()),
+		},
+	})
+}
+
+// Returns a response that is either a GraphQL response or problem details, as
+// failures before the query runs are reported by the middleware.
+func graphqlOrProblem(graphqlResponse *Response, problem *Response) *Response {
+	return &Response{
+		Description: "The query could not be run.",
+		Content: map[string]*MediaType{
+			"application/json":         graphqlResponse.Content["application/json"],
+			"application/problem+json": problem.Content["application/problem+json"],
+		},
+	}
+}
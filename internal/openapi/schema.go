@@ -0,0 +1,259 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// A JSON Schema, as far as the API needs one. Type is either a single type name
+// or a list of them, such as ["string", "null"] for nullable values.
+// AdditionalProperties is either a bool or a *Schema.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// Returns a schema of the type of value, the way encoding/json marshals it.
+// Named structs are added to the components of the document and referenced.
+func (document *Document) SchemaOf(value any) *Schema {
+	return document.schemaOf(reflect.TypeOf(value))
+}
+
+func (document *Document) schemaOf(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(document.schemaOf(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: document.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: document.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return document.structSchema(t)
+		}
+
+		var name string = t.Name()
+
+		// Types of other packages sharing a name, such as graphql.Error, are told
+		// apart by their package.
+		if named, exists := document.componentTypes[name]; exists && named != t {
+			name = strings.ToUpper(path.Base(t.PkgPath())[:1]) + path.Base(t.PkgPath())[1:] + name
+		}
+
+		if _, exists := document.componentTypes[name]; !exists {
+			// Added before its fields so that types referring to themselves end.
+			document.componentTypes[name] = t
+			document.Components.Schemas[name] = document.structSchema(t)
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	return &Schema{}
+}
+
+// Returns a schema of the JSON object a struct is marshalled to. Fields with
+// omitempty are optional, and fields that marshal nil as null are nullable.
+func (document *Document) structSchema(t reflect.Type) *Schema {
+	var schema Schema = Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		var fieldType reflect.Type = field.Type
+		var omitted bool = strings.Contains(options, "omitempty")
+
+		if omitted && fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem() // nil is left out rather than null
+		}
+
+		property := document.schemaOf(fieldType)
+
+		if !omitted {
+			switch fieldType.Kind() {
+			case reflect.Slice, reflect.Map, reflect.Interface:
+				property = nullable(property)
+			}
+
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+	}
+
+	return &schema
+}
+
+// Returns a schema that also allows null.
+func nullable(schema *Schema) *Schema {
+	if schema.Type == nil {
+		if schema.Ref == "" {
+			return schema // anything is allowed already
+		}
+
+		return &Schema{OneOf: []*Schema{schema, {Type: "null"}}}
+	}
+
+	var nullableSchema Schema = *schema
+	nullableSchema.Type = append(schema.types(), "null")
+	return &nullableSchema
+}
+
+// Returns the names of the types the schema allows, or nil if it allows any.
+func (schema *Schema) types() []string {
+	switch value := schema.Type.(type) {
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	}
+
+	return nil
+}
+
+// Returns the reasons value, decoded from JSON, does not match schema. Where
+// describes the location of value, such as "body.data[0].title".
+func (document *Document) Validate(schema *Schema, value any, where string) []string {
+	var problems []string
+
+	if schema.Ref != "" {
+		referenced := document.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if referenced == nil {
+			return []string{fmt.Sprintf("%s refers to the unknown schema %s", where, schema.Ref)}
+		}
+
+		return document.Validate(referenced, value, where)
+	}
+
+	if len(schema.OneOf) > 0 {
+		matches := 0
+
+		for _, option := range schema.OneOf {
+			if len(document.Validate(option, value, where)) == 0 {
+				matches++
+			}
+		}
+
+		if matches != 1 {
+			return []string{fmt.Sprintf("%s matches %d of the %d schemas it must match exactly one of", where, matches, len(schema.OneOf))}
+		}
+	}
+
+	if types := schema.types(); types != nil {
+		var actual string = typeOf(value)
+		var allowed bool
+
+		for _, name := range types {
+			allowed = allowed || name == actual || (name == "number" && actual == "integer")
+		}
+
+		if !allowed {
+			return []string{fmt.Sprintf("%s is %s rather than %s", where, actual, strings.Join(types, " or "))}
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		var allowed bool
+
+		for _, option := range schema.Enum {
+			allowed = allowed || option == value
+		}
+
+		if !allowed {
+			problems = append(problems, fmt.Sprintf("%s is %v, which is not one of %v", where, value, schema.Enum))
+		}
+	}
+
+	switch value := value.(type) {
+	case []any:
+		if schema.Items != nil {
+			for index, item := range value {
+				problems = append(problems, document.Validate(schema.Items, item, fmt.Sprintf("%s[%d]", where, index))...)
+			}
+		}
+
+	case map[string]any:
+		for _, name := range schema.Required {
+			if _, present := value[name]; !present {
+				problems = append(problems, fmt.Sprintf("%s.%s is missing", where, name))
+			}
+		}
+
+		for _, name := range sortedKeys(value) {
+			if property, described := schema.Properties[name]; described {
+				problems = append(problems, document.Validate(property, value[name], where+"."+name)...)
+				continue
+			}
+
+			switch additional := schema.AdditionalProperties.(type) {
+			case bool:
+				if !additional {
+					problems = append(problems, fmt.Sprintf("%s.%s is not described", where, name))
+				}
+			case *Schema:
+				problems = append(problems, document.Validate(additional, value[name], where+"."+name)...)
+			}
+		}
+	}
+
+	return problems
+}
+
+// Returns the JSON Schema type name of a value decoded from JSON.
+func typeOf(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
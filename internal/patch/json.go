@@ -0,0 +1,269 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+)
+
+// An operation of a JSON Patch. Value is left nil when the operation has none,
+// which tells it apart from a null value.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Reported when a test operation does not hold, which leaves the document
+// unchanged.
+var errTestFailed = errors.New("the tested value differs")
+
+// Returns document with operations applied in order, as described by RFC 6902.
+// Either every operation applies, or the patch as a whole is rejected.
+func run(document any, operations []operation) (any, error) {
+	for index, operation := range operations {
+		var err error
+
+		document, err = operation.apply(document)
+		if errors.Is(err, errTestFailed) {
+			return nil, problems.New(problems.Conflict, fmt.Sprintf("Operation %d tests %s, but %v.", index, operation.Path, err))
+		}
+
+		if err != nil {
+			return nil, problems.New(problems.InvalidRequest, fmt.Sprintf("Operation %d (%s %s) can't be applied, %v.", index, operation.Op, operation.Path, err))
+		}
+	}
+
+	return document, nil
+}
+
+// Returns document with the operation applied.
+func (operation operation) apply(document any) (any, error) {
+	path, err := pointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	switch operation.Op {
+	case "add", "replace", "test":
+		if operation.Value == nil {
+			return nil, errors.New("the value is missing")
+		}
+
+		if err := json.Unmarshal(operation.Value, &value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch operation.Op {
+	case "add":
+		return add(document, path, value)
+
+	case "remove":
+		return remove(document, path)
+
+	case "replace":
+		if len(path) == 0 {
+			return value, nil
+		}
+
+		if document, err = remove(document, path); err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+
+	case "move", "copy":
+		from, err := pointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		moved, err := get(document, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Op == "copy" {
+			return add(document, path, clone(moved))
+		}
+
+		if strings.HasPrefix(operation.Path, operation.From+"/") {
+			return nil, errors.New("a value can't be moved into itself")
+		}
+
+		if document, err = remove(document, from); err != nil {
+			return nil, err
+		}
+		return add(document, path, moved)
+
+	case "test":
+		tested, err := get(document, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(tested, value) {
+			return nil, errTestFailed
+		}
+		return document, nil
+	}
+
+	return nil, fmt.Errorf("%q is not an operation", operation.Op)
+}
+
+// Returns the reference tokens of a JSON Pointer, as described by RFC 6901.
+// The empty pointer refers to the whole document.
+func pointer(text string) ([]string, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(text, "/") {
+		return nil, fmt.Errorf("the pointer %q does not start with a slash", text)
+	}
+
+	var tokens []string = strings.Split(text[1:], "/")
+	for index, token := range tokens {
+		tokens[index] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// Returns the value path refers to within document.
+func get(document any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := document.(type) {
+		case map[string]any:
+			member, exists := node[token]
+			if !exists {
+				return nil, fmt.Errorf("there is no member %q", token)
+			}
+			document = member
+
+		case []any:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			document = node[index]
+
+		default:
+			return nil, fmt.Errorf("%q is not within an object or array", token)
+		}
+	}
+
+	return document, nil
+}
+
+// Returns document with value added where path refers to. Members of objects
+// are set, while values are inserted into arrays, or appended for "-".
+func add(document any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return change(document, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+
+		case []any:
+			if token == "-" {
+				return append(node, value), nil
+			}
+
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		}
+
+		return nil, fmt.Errorf("%q is not within an object or array", token)
+	})
+}
+
+// Returns document without the value path refers to, which must exist.
+func remove(document any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.New("the whole document can't be removed")
+	}
+
+	return change(document, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			if _, exists := node[token]; !exists {
+				return nil, fmt.Errorf("there is no member %q", token)
+			}
+
+			delete(node, token)
+			return node, nil
+
+		case []any:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(node[:index], node[index+1:]...), nil
+		}
+
+		return nil, fmt.Errorf("%q is not within an object or array", token)
+	})
+}
+
+// Returns document with the parent of the value path refers to replaced by
+// what edit makes of it, given the last token of path. Arrays may grow or
+// shrink, so every parent on the way is set again.
+func change(document any, path []string, edit func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return edit(document, path[0])
+	}
+
+	child, err := get(document, path[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := change(child, path[1:], edit)
+	if err != nil {
+		return nil, err
+	}
+
+	switch node := document.(type) {
+	case map[string]any:
+		node[path[0]] = changed
+	case []any:
+		index, _ := arrayIndex(path[0], len(node)-1)
+		node[index] = changed
+	}
+
+	return document, nil
+}
+
+// Returns the array index token stands for, which may be at most maximum.
+func arrayIndex(token string, maximum int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+		return 0, fmt.Errorf("%q is not an array index", token)
+	}
+
+	if index > maximum {
+		return 0, fmt.Errorf("the index %d is out of range", index)
+	}
+
+	return index, nil
+}
@@ -0,0 +1,70 @@
+package patch
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+)
+
+// Returns the problem to send to the client if a patched document has members
+// other than names.
+func Only(document map[string]any, names ...string) error {
+	var allowed map[string]bool = map[string]bool{}
+	var unknown []string
+
+	for _, name := range names {
+		allowed[name] = true
+	}
+
+	for name := range document {
+		if !allowed[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	detail := fmt.Sprintf("The %s member can't be changed.", unknown[0])
+	return problems.New(problems.InvalidRequest, detail, problems.Param(unknown[0], detail))
+}
+
+// Returns the sanitized text of the member name of a patched document, which
+// may be at most maximum bytes. A removed member is empty text, which is
+// rejected if the member is required.
+func Text(document map[string]any, name string, maximum int, required bool) (string, error) {
+	var detail string
+
+	text, isText := document[name].(string)
+	text = functions.Sanitize(text)
+
+	switch {
+	case document[name] != nil && !isText:
+		detail = fmt.Sprintf("The %s value must be text.", name)
+	case len(text) > maximum:
+		detail = fmt.Sprintf("The %s value was larger than %d bytes. In UTF-8 encoding, English characters are 1 byte each.", name, maximum)
+	case required && text == "":
+		detail = fmt.Sprintf("The %s value is required.", name)
+	default:
+		return text, nil
+	}
+
+	return "", problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+}
+
+// Returns the member name of a patched document as a boolean. A removed member
+// is false.
+func Bool(document map[string]any, name string) (bool, error) {
+	value, isBoolean := document[name].(bool)
+
+	if document[name] != nil && !isBoolean {
+		detail := fmt.Sprintf("The %s value must be true or false.", name)
+		return false, problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+	}
+
+	return value, nil
+}
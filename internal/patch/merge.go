@@ -0,0 +1,27 @@
+package patch
+
+// Returns target with patch merged into it, as described by RFC 7396: members
+// of a patch object replace those of the target, recursively for objects, and
+// null members remove them. Any other patch replaces the target as a whole.
+func merge(target any, patch any) any {
+	patchObject, isObject := patch.(map[string]any)
+	if !isObject {
+		return patch
+	}
+
+	targetObject, isObject := target.(map[string]any)
+	if !isObject {
+		targetObject = map[string]any{}
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+
+		targetObject[name] = merge(targetObject[name], value)
+	}
+
+	return targetObject
+}
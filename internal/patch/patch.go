@@ -0,0 +1,152 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+)
+
+// Media types of the patches Apply understands.
+const (
+	MergePatch string = "application/merge-patch+json" // RFC 7396
+	JSONPatch  string = "application/json-patch+json"  // RFC 6902
+)
+
+// Largest patch read from a request body.
+const maximumSize int64 = 1 << 20
+
+// Largest part of multipart forms kept in memory, the way handlers parse forms
+// elsewhere.
+const maximumFormMemory int64 = 32 << 20
+
+// Returns document changed by the patch in the body of r, leaving document
+// untouched. The body is either a JSON Merge Patch, which plain JSON is read as
+// too, a JSON Patch, or, for older clients, a form whose fields replace the
+// members of the same name. Form values of members that are booleans are read
+// as booleans.
+//
+// This function returns the problem to send to the client if the patch is
+// malformed or can't be applied.
+func Apply(r *http.Request, document map[string]any) (map[string]any, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case MergePatch, "application/json":
+		var patch any
+		if err := decode(r, &patch); err != nil {
+			return nil, err
+		}
+
+		merged, isObject := merge(clone(document), patch).(map[string]any)
+		if !isObject {
+			return nil, problems.New(problems.InvalidRequest, "The merge patch must be a JSON object.")
+		}
+
+		return merged, nil
+
+	case JSONPatch:
+		var operations []operation
+		if err := decode(r, &operations); err != nil {
+			return nil, err
+		}
+
+		patched, err := run(clone(document), operations)
+		if err != nil {
+			return nil, err
+		}
+
+		object, isObject := patched.(map[string]any)
+		if !isObject {
+			return nil, problems.New(problems.InvalidRequest, "The patch must leave a JSON object.")
+		}
+
+		return object, nil
+
+	case "multipart/form-data", "application/x-www-form-urlencoded":
+		return form(r, mediaType, document)
+	}
+
+	return nil, problems.New(
+		problems.UnsupportedMediaType,
+		fmt.Sprintf("The request body is %q. Send %s, %s or a form instead.", mediaType, MergePatch, JSONPatch),
+	)
+}
+
+// Decodes the JSON body of r into value.
+func decode(r *http.Request, value any) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maximumSize+1))
+	if err != nil {
+		return problems.Wrap(problems.InvalidRequest, err, "The request body could not be read.")
+	}
+
+	if int64(len(body)) > maximumSize {
+		return problems.New(problems.InvalidRequest, fmt.Sprintf("The patch was larger than %d bytes.", maximumSize))
+	}
+
+	if err := json.Unmarshal(body, value); err != nil {
+		return problems.New(problems.InvalidRequest, fmt.Sprintf("The patch is not valid JSON. %v", err))
+	}
+
+	return nil
+}
+
+// Returns document with the members named by the fields of the form in r
+// replaced by their values. An empty field sets an empty value, while a missing
+// one leaves the member as it is.
+func form(r *http.Request, mediaType string, document map[string]any) (map[string]any, error) {
+	var err error
+	var patched map[string]any = clone(document).(map[string]any)
+
+	if mediaType == "application/x-www-form-urlencoded" {
+		err = r.ParseForm()
+	} else {
+		err = r.ParseMultipartForm(maximumFormMemory)
+	}
+
+	if err != nil {
+		return nil, problems.New(problems.InvalidRequest, fmt.Sprintf("The request body is not a valid form. %v", err))
+	}
+
+	for name, values := range r.PostForm {
+		if _, isBoolean := document[name].(bool); !isBoolean {
+			patched[name] = values[0]
+			continue
+		}
+
+		value, err := strconv.ParseBool(values[0])
+		if err != nil {
+			detail := fmt.Sprintf("The %s value must be true or false.", name)
+			return nil, problems.New(problems.InvalidRequest, detail, problems.Param(name, detail))
+		}
+
+		patched[name] = value
+	}
+
+	return patched, nil
+}
+
+// Returns a deep copy of a value decoded from JSON.
+func clone(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		var copied map[string]any = make(map[string]any, len(value))
+		for key, member := range value {
+			copied[key] = clone(member)
+		}
+		return copied
+
+	case []any:
+		var copied []any = make([]any, len(value))
+		for index, element := range value {
+			copied[index] = clone(element)
+		}
+		return copied
+	}
+
+	return value
+}
@@ -0,0 +1,60 @@
+package problems
+
+import (
+	"errors"
+	"fmt"
+)
+
+// An error that is sent to the client as a problem of a type of the catalog.
+// The cause, if any, is only logged.
+type Problem struct {
+	Type          *Type
+	Detail        string
+	InvalidParams []InvalidParam
+	Err           error
+}
+
+// A request parameter or field that was rejected, and why.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Returns a problem of kind explained by detail, listing the parameters that
+// were rejected, if any.
+func New(kind *Type, detail string, params ...InvalidParam) *Problem {
+	return &Problem{Type: kind, Detail: detail, InvalidParams: params}
+}
+
+// Returns a problem of kind explained by detail that was caused by err.
+func Wrap(kind *Type, err error, detail string) *Problem {
+	return &Problem{Type: kind, Detail: detail, Err: err}
+}
+
+// Returns a rejected parameter.
+func Param(name string, reason string) InvalidParam {
+	return InvalidParam{Name: name, Reason: reason}
+}
+
+// Returns the problem err is or wraps. Any other error becomes an internal
+// problem caused by it.
+func From(err error) *Problem {
+	var problem *Problem
+	if errors.As(err, &problem) {
+		return problem
+	}
+
+	return Wrap(Internal, err, "An unexpected error occurred. Mention the request id when reporting it.")
+}
+
+func (problem *Problem) Error() string {
+	if problem.Err != nil {
+		return fmt.Sprintf("%s: %s %v", problem.Type.Title, problem.Detail, problem.Err)
+	}
+
+	return fmt.Sprintf("%s: %s", problem.Type.Title, problem.Detail)
+}
+
+func (problem *Problem) Unwrap() error {
+	return problem.Err
+}
@@ -0,0 +1,94 @@
+package problems
+
+// A kind of problem the API reports. The URI is stable, so that clients can
+// branch on it rather than on titles or details, which are meant for people.
+type Type struct {
+	URI    string
+	Title  string
+	Status int
+}
+
+// Prefix of the URIs of every problem type.
+const base string = "/api/problems/"
+
+var (
+	// The request is malformed, or a value within it is invalid.
+	InvalidRequest = &Type{URI: base + "invalid-request", Title: "Invalid Request", Status: 400}
+
+	// The request carries no valid session or API token.
+	Unauthorized = &Type{URI: base + "unauthorized", Title: "Unauthorized", Status: 401}
+
+	// The authenticated user, profile or API token may not do this.
+	Forbidden = &Type{URI: base + "forbidden", Title: "Forbidden", Status: 403}
+
+	// The role of the user requires two-factor authentication, which they have
+	// not enabled yet.
+	TwoFactorRequired = &Type{URI: base + "two-factor-required", Title: "Two-Factor Authentication Required", Status: 403}
+
+	// The resource does not exist, or is hidden from the requesting profile.
+	NotFound = &Type{URI: base + "not-found", Title: "Not Found", Status: 404}
+
+	// The request conflicts with the current state of a resource, such as a
+	// name that is already taken.
+	Conflict = &Type{URI: base + "conflict", Title: "Conflict", Status: 409}
+
+	// The resource changed since the representation whose ETag the request
+	// was conditioned on with If-Match.
+	PreconditionFailed = &Type{URI: base + "precondition-failed", Title: "Precondition Failed", Status: 412}
+
+	// The request body is of a media type the route does not accept.
+	UnsupportedMediaType = &Type{URI: base + "unsupported-media-type", Title: "Unsupported Media Type", Status: 415}
+
+	// An operation of a batch was rolled back or left out, because another
+	// operation of the same batch failed.
+	FailedDependency = &Type{URI: base + "failed-dependency", Title: "Failed Dependency", Status: 424}
+
+	// The request changes a resource without an If-Match header, which could
+	// overwrite changes made by someone else.
+	PreconditionRequired = &Type{URI: base + "precondition-required", Title: "Precondition Required", Status: 428}
+
+	// Too many wrong secrets, such as the PIN of a profile, were entered. The
+	// Retry-After header tells when to try again.
+	TooManyAttempts = &Type{URI: base + "too-many-attempts", Title: "Too Many Attempts", Status: 429}
+
+	// The request does not match the OpenAPI document.
+	RequestDivergence = &Type{URI: base + "request-divergence", Title: "Request Diverges From the OpenAPI Document", Status: 400}
+
+	// An unexpected error occurred. Details are logged under the request id
+	// rather than sent to the client.
+	Internal = &Type{URI: base + "internal", Title: "Internal Error", Status: 500}
+
+	// An uploaded file could not be stored.
+	UploadFailed = &Type{URI: base + "upload-failed", Title: "Upload Failed", Status: 500}
+
+	// A file the database refers to is missing from storage, or the other way
+	// around.
+	StorageOutOfSync = &Type{URI: base + "storage-out-of-sync", Title: "Storage Out of Sync", Status: 500}
+
+	// The response of a handler does not match the OpenAPI document.
+	ResponseDivergence = &Type{URI: base + "response-divergence", Title: "Response Diverges From the OpenAPI Document", Status: 500}
+
+	// An upstream service, such as the identity provider, could not be reached.
+	BadGateway = &Type{URI: base + "bad-gateway", Title: "Bad Gateway", Status: 502}
+)
+
+// Every problem type, in the order of their statuses.
+var Catalog = []*Type{
+	InvalidRequest,
+	RequestDivergence,
+	Unauthorized,
+	Forbidden,
+	TwoFactorRequired,
+	NotFound,
+	Conflict,
+	PreconditionFailed,
+	UnsupportedMediaType,
+	FailedDependency,
+	PreconditionRequired,
+	TooManyAttempts,
+	Internal,
+	UploadFailed,
+	StorageOutOfSync,
+	ResponseDivergence,
+	BadGateway,
+}
@@ -0,0 +1,134 @@
+package queries
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns the credits of every given parent (movie, show or episode) id along
+// with the names of the credited people, keyed by the parent id and ordered by
+// their billing position.
+func CreditsFor(database *sql.DB, parentIds []string) (map[string][]types.Credit, error) {
+	var credits map[string][]types.Credit = map[string][]types.Credit{}
+	var arguments []any
+
+	if len(parentIds) == 0 {
+		return credits, nil
+	}
+
+	for _, id := range parentIds {
+		arguments = append(arguments, id)
+	}
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				credits.id, credits.person_id, people.name, credits.parent_id, credits.parent_type,
+				credits.role, credits.character, credits.position, credits.version
+			FROM
+				credits
+			JOIN
+				people ON people.id = credits.person_id
+			WHERE
+				credits.parent_id IN (%s)
+			ORDER BY
+				credits.position, people.name
+			`,
+			Placeholders(len(parentIds)),
+		),
+		arguments...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var credit types.Credit
+
+		if err := rows.Scan(
+			&credit.Id,
+			&credit.PersonId,
+			&credit.Name,
+			&credit.ParentId,
+			&credit.ParentType,
+			&credit.Role,
+			&credit.Character,
+			&credit.Position,
+			&credit.Version,
+		); err != nil {
+			return nil, err
+		}
+
+		credits[credit.ParentId] = append(credits[credit.ParentId], credit)
+	}
+
+	return credits, rows.Err()
+}
+
+// Returns every credit of a person within the library, along with the title
+// of the credited movie, show or episode. Credits on content that does not pass
+// the condition built by visible for the content's rating column are left out.
+func Filmography(
+  database *sql.DB,
+  personId string,
+  visible func(ratingColumn string) (string, []any),
+) ([]types.Credit, error) {
+	var credits []types.Credit = []types.Credit{}
+	var condition, arguments = visible(`
+		CASE credits.parent_type
+			WHEN 'movie' THEN movies.content_rating_id
+			WHEN 'show' THEN shows.content_rating_id
+			ELSE COALESCE(episodes.content_rating_id, (SELECT parent.content_rating_id FROM shows AS parent WHERE parent.id = episodes.parent_id))
+		END
+	`)
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				credits.id, credits.parent_id, credits.parent_type, credits.role, credits.character, credits.position,
+				COALESCE(movies.title, shows.title, episodes.title, '')
+			FROM
+				credits
+			LEFT JOIN
+				movies ON credits.parent_type = 'movie' AND movies.id = credits.parent_id
+			LEFT JOIN
+				shows ON credits.parent_type = 'show' AND shows.id = credits.parent_id
+			LEFT JOIN
+				episodes ON credits.parent_type = 'episode' AND episodes.id = credits.parent_id
+			WHERE
+				credits.person_id = ? AND %s
+			ORDER BY
+				credits.parent_type, credits.position
+			`,
+			condition,
+		),
+		append([]any{personId}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var credit types.Credit = types.Credit{PersonId: personId}
+
+		if err := rows.Scan(
+			&credit.Id,
+			&credit.ParentId,
+			&credit.ParentType,
+			&credit.Role,
+			&credit.Character,
+			&credit.Position,
+			&credit.Title,
+		); err != nil {
+			return nil, err
+		}
+
+		credits = append(credits, credit)
+	}
+
+	return credits, rows.Err()
+}
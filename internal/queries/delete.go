@@ -0,0 +1,124 @@
+package queries
+
+import (
+	"database/sql"
+	"fmt"
+	"path"
+)
+
+// Runs statements, either on the database itself or within a transaction, as
+// both *sql.DB and *sql.Tx do.
+type Executor interface {
+	Exec(query string, arguments ...any) (sql.Result, error)
+	Query(query string, arguments ...any) (*sql.Rows, error)
+	QueryRow(query string, arguments ...any) *sql.Row
+}
+
+// Deletes the show with the given id along with its episodes, or the movie
+// with it, depending on table, and everything attached to them. Returns the
+// files of their videos and covers, relative to the storage directory, for the
+// caller to remove once the deletion is committed, or sql.ErrNoRows if there
+// is no such show or movie.
+func DeleteMedia(executor Executor, table string, id string) ([]string, error) {
+	var exists bool
+	var files []string
+	var statements []statement
+	var videoQuery string = "SELECT file_name FROM movies WHERE id = ?"
+
+	if table != "shows" && table != "movies" {
+		return nil, fmt.Errorf("%s holds neither shows nor movies", table)
+	}
+
+	if err := executor.QueryRow(fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = ?)", table), id).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	if table == "shows" {
+		videoQuery = "SELECT file_name FROM episodes WHERE parent_id = ?"
+	}
+
+	videos, err := fileNames(executor, videoQuery, id)
+	if err != nil {
+		return nil, err
+	}
+
+	covers, err := fileNames(executor, "SELECT file_name FROM covers WHERE parent_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, video := range videos {
+		files = append(files, path.Join("videos", video))
+	}
+
+	for _, cover := range covers {
+		files = append(files, path.Join("covers", cover))
+	}
+
+	if table == "shows" {
+		statements = []statement{
+			{"DELETE FROM credits WHERE parent_id = ? OR parent_id IN (SELECT id FROM episodes WHERE parent_id = ?)", []any{id, id}},
+			{"DELETE FROM progress WHERE media_id IN (SELECT id FROM episodes WHERE parent_id = ?)", []any{id}},
+			{"DELETE FROM watch_history WHERE media_id IN (SELECT id FROM episodes WHERE parent_id = ?)", []any{id}},
+			{"DELETE FROM user_ratings WHERE media_id = ? OR media_id IN (SELECT id FROM episodes WHERE parent_id = ?)", []any{id, id}},
+			{"DELETE FROM list_items WHERE media_id = ?", []any{id}},
+			{"DELETE FROM episodes WHERE parent_id = ?", []any{id}},
+		}
+	} else {
+		statements = []statement{
+			{"DELETE FROM credits WHERE parent_id = ?", []any{id}},
+			{"DELETE FROM progress WHERE media_id = ?", []any{id}},
+			{"DELETE FROM watch_history WHERE media_id = ?", []any{id}},
+			{"DELETE FROM user_ratings WHERE media_id = ?", []any{id}},
+			{"DELETE FROM list_items WHERE media_id = ?", []any{id}},
+		}
+	}
+
+	statements = append(statements,
+		statement{"DELETE FROM covers WHERE parent_id = ?", []any{id}},
+		statement{"DELETE FROM taggings WHERE parent_id = ?", []any{id}},
+		statement{fmt.Sprintf("DELETE FROM %s WHERE id = ?", table), []any{id}},
+	)
+
+	for _, statement := range statements {
+		if _, err := executor.Exec(statement.query, statement.arguments...); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// A statement run along with others, in order.
+type statement struct {
+	query     string
+	arguments []any
+}
+
+// Returns the file names the query selects.
+func fileNames(executor Executor, query string, id string) ([]string, error) {
+	var names []string
+
+	rows, err := executor.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
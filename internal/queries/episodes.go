@@ -0,0 +1,59 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Returns the id of the episode that follows the given position within a show,
+// ordering episodes by season and then by episode number. Only episodes that
+// match condition, an SQL condition on the episodes table, are considered.
+// Returns an empty string if there is no such episode.
+func NextEpisode(database *sql.DB, showId string, season int, episode int, condition string, arguments []any) (string, error) {
+	return adjacentEpisode(database, showId, season, episode, ">", "ASC", condition, arguments)
+}
+
+// Returns the id of the episode that precedes the given position within a show,
+// the counterpart of NextEpisode. Returns an empty string if there is no such
+// episode.
+func PreviousEpisode(database *sql.DB, showId string, season int, episode int, condition string, arguments []any) (string, error) {
+	return adjacentEpisode(database, showId, season, episode, "<", "DESC", condition, arguments)
+}
+
+// Returns the id of the closest episode in the direction that comparison and
+// direction describe, for NextEpisode and PreviousEpisode.
+func adjacentEpisode(database *sql.DB, showId string, season int, episode int, comparison string, direction string, condition string, arguments []any) (string, error) {
+	var id string
+
+	if err := database.QueryRow(
+		fmt.Sprintf(`
+			SELECT
+				id
+			FROM
+				episodes
+			WHERE
+				parent_id = ?
+			AND
+				(season_number %[1]s ? OR (season_number = ? AND episode_number %[1]s ?))
+			AND
+				%[2]s
+			ORDER BY
+				season_number %[3]s, episode_number %[3]s
+			LIMIT 1
+			`,
+			comparison,
+			condition,
+			direction,
+		),
+		append([]any{showId, season, season, episode}, arguments...)...,
+	).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,529 @@
+package queries
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+)
+
+// Kinds of values a filterable field holds, which decide the operators and
+// values it accepts.
+const (
+	TextField = iota
+	NumberField
+	DateField
+	TagField
+	PersonField
+	ContentRatingField
+)
+
+// Bounds of a filter, which keep both parsing it and the condition it builds
+// cheap, and the condition well within SQLite's own limit on nesting.
+const (
+	maximumFilterLength int = 1024
+	maximumFilterDepth  int = 32
+)
+
+// A field clients may filter and sort a collection by. Expression is the SQL
+// column or expression the field reads, which must never be NULL if the field
+// is sortable.
+type Field struct {
+	Expression string
+	Kind       int
+	Sortable   bool
+}
+
+// The fields of a collection, keyed by the name clients use for them.
+type Fields map[string]Field
+
+// Fields of the movies collection.
+var MovieFields Fields = Fields{
+	"title":          {Expression: "title", Kind: TextField, Sortable: true},
+	"description":    {Expression: "description", Kind: TextField},
+	"year":           {Expression: year("upload_date"), Kind: NumberField, Sortable: true},
+	"uploaded":       {Expression: isoDate("upload_date"), Kind: DateField, Sortable: true},
+	"modified":       {Expression: isoDate("last_modified"), Kind: DateField, Sortable: true},
+	"rating":         {Expression: AverageRating("movies"), Kind: NumberField, Sortable: true},
+	"content_rating": {Expression: "content_rating_id", Kind: ContentRatingField},
+	"tag":            {Kind: TagField},
+	"person":         {Kind: PersonField},
+}
+
+// Fields of the shows collection.
+var ShowFields Fields = Fields{
+	"title":          {Expression: "title", Kind: TextField, Sortable: true},
+	"description":    {Expression: "description", Kind: TextField},
+	"episodes":       {Expression: "episode_count", Kind: NumberField, Sortable: true},
+	"year":           {Expression: year("upload_date"), Kind: NumberField, Sortable: true},
+	"uploaded":       {Expression: isoDate("upload_date"), Kind: DateField, Sortable: true},
+	"modified":       {Expression: isoDate("last_modified"), Kind: DateField, Sortable: true},
+	"rating":         {Expression: AverageRating("shows"), Kind: NumberField, Sortable: true},
+	"content_rating": {Expression: "content_rating_id", Kind: ContentRatingField},
+	"tag":            {Kind: TagField},
+	"person":         {Kind: PersonField},
+}
+
+// Fields of the episodes of a show.
+var EpisodeFields Fields = Fields{
+	"title":          {Expression: "COALESCE(title, '')", Kind: TextField, Sortable: true},
+	"description":    {Expression: "COALESCE(description, '')", Kind: TextField},
+	"season":         {Expression: "season_number", Kind: NumberField, Sortable: true},
+	"episode":        {Expression: "episode_number", Kind: NumberField, Sortable: true},
+	"uploaded":       {Expression: isoDate("upload_date"), Kind: DateField, Sortable: true},
+	"modified":       {Expression: isoDate("last_modified"), Kind: DateField, Sortable: true},
+	"rating":         {Expression: AverageRating("episodes"), Kind: NumberField, Sortable: true},
+	"content_rating": {Expression: access.EpisodeRating, Kind: ContentRatingField},
+	"person":         {Kind: PersonField},
+}
+
+// Returns an SQL expression reading a date column as "2006-01-02 15:04:05",
+// since movies and shows store their dates as "01-02-2006 15:04:05".
+func isoDate(column string) string {
+	return fmt.Sprintf(
+		"(CASE WHEN %[1]s LIKE '__-__-____%%' THEN substr(%[1]s, 7, 4) || '-' || substr(%[1]s, 1, 5) || substr(%[1]s, 11) ELSE %[1]s END)",
+		column,
+	)
+}
+
+// Returns an SQL expression reading the year of a date column as a number.
+// Release dates aren't stored, so the year a title was uploaded stands in for
+// it.
+func year(column string) string {
+	return fmt.Sprintf("CAST(substr(%s, 1, 4) AS INTEGER)", isoDate(column))
+}
+
+// Returned when a filter or sort query value can't be parsed. Position is the
+// byte offset of the offending token within the value, counting from 1.
+type SyntaxError struct {
+	Parameter string
+	Position  int
+	Token     string
+	Reason    string
+}
+
+func (err *SyntaxError) Error() string {
+	if err.Token == "" {
+		return fmt.Sprintf("The %s ended unexpectedly at position %d: %s.", err.Parameter, err.Position, err.Reason)
+	}
+
+	return fmt.Sprintf("Unexpected %q at position %d of the %s: %s.", err.Token, err.Position, err.Parameter, err.Reason)
+}
+
+// Returns the invalid request problem of a filter or sort value that couldn't
+// be parsed, naming the parameter along with the offending token and its
+// position. Other errors are returned as is.
+func Malformed(err error) error {
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	var reason string = fmt.Sprintf("Ended unexpectedly at position %d: %s.", syntaxErr.Position, syntaxErr.Reason)
+	if syntaxErr.Token != "" {
+		reason = fmt.Sprintf("Unexpected %q at position %d: %s.", syntaxErr.Token, syntaxErr.Position, syntaxErr.Reason)
+	}
+
+	return problems.New(
+		problems.InvalidRequest,
+		fmt.Sprintf("The %s can't be parsed.", syntaxErr.Parameter),
+		problems.Param(syntaxErr.Parameter, reason),
+	)
+}
+
+// Parses a filter query value such as `rating>=7 and (tag:anime or tag:drama)`
+// into an SQL condition over fields, along with its arguments. Terms compare a
+// field to a value, and can be combined with and, or, not and parentheses.
+//
+// Operators are = != > >= < <= for numbers and dates, = != and ~ (contains) for
+// text, and : (has) for tags, people and content ratings. Values containing
+// spaces or operators must be double quoted. An empty filter lets everything
+// through. Filters are at most 1024 bytes long, and nest parentheses and nots
+// at most 32 levels deep.
+func Filter(expression string, fields Fields) (string, []any, error) {
+	if strings.TrimSpace(expression) == "" {
+		return "1 = 1", nil, nil
+	}
+
+	if len(expression) > maximumFilterLength {
+		return "", nil, &SyntaxError{
+			Parameter: "filter",
+			Position:  maximumFilterLength + 1,
+			Token:     expression[maximumFilterLength : maximumFilterLength+1],
+			Reason:    fmt.Sprintf("the filter is longer than %d bytes", maximumFilterLength),
+		}
+	}
+
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return "", nil, err
+	}
+
+	parser := filterParser{tokens: tokens, fields: fields, end: len(expression) + 1}
+
+	condition, err := parser.or()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if parser.index < len(tokens) {
+		return "", nil, parser.fail(parser.peek(), "expected and, or or the end of the filter")
+	}
+
+	return condition, parser.arguments, nil
+}
+
+// Parses a sort query value such as `-rating,title` into the sort keys of a
+// collection. Fields are separated by commas and sorted in descending order if
+// prefixed with a minus. The id column is appended to make the order unique.
+func Sort(expression string, fields Fields) ([]SortKey, error) {
+	var keys []SortKey
+	var position int = 1
+
+	for _, part := range strings.Split(expression, ",") {
+		var key SortKey
+		var name string = strings.TrimSpace(part)
+		var start int = position + strings.Index(part, name)
+
+		position += len(part) + 1
+
+		if name == "" {
+			return nil, &SyntaxError{Parameter: "sort", Position: start, Reason: "expected a field name"}
+		}
+
+		if name[0] == '-' || name[0] == '+' {
+			key.Descending = (name[0] == '-')
+			name = name[1:]
+		}
+
+		field, exists := fields[name]
+		if !exists {
+			return nil, &SyntaxError{Parameter: "sort", Position: start, Token: name, Reason: "unknown field"}
+		}
+
+		if !field.Sortable {
+			return nil, &SyntaxError{Parameter: "sort", Position: start, Token: name, Reason: "the field can't be sorted by"}
+		}
+
+		key.Expression = field.Expression
+		keys = append(keys, key)
+	}
+
+	return append(keys, SortKey{Expression: "id"}), nil
+}
+
+// A word, quoted string, operator or parenthesis of a filter.
+type filterToken struct {
+	text     string
+	position int
+	quoted   bool
+}
+
+// Characters that make up operators, which end unquoted words.
+const operatorCharacters string = "=!<>~:"
+
+// Splits a filter into its tokens.
+func tokenize(expression string) ([]filterToken, error) {
+	var tokens []filterToken
+	var index int = 0
+
+	for index < len(expression) {
+		character := rune(expression[index])
+		start := index
+
+		switch {
+		case unicode.IsSpace(character):
+			index++
+			continue
+
+		case character == '(' || character == ')':
+			index++
+
+		case character == '"':
+			var text strings.Builder
+
+			for index++; index < len(expression) && expression[index] != '"'; index++ {
+				if expression[index] == '\\' && index+1 < len(expression) {
+					index++
+				}
+
+				text.WriteByte(expression[index])
+			}
+
+			if index >= len(expression) {
+				return nil, &SyntaxError{Parameter: "filter", Position: start + 1, Token: expression[start:], Reason: "the quoted value is never closed"}
+			}
+
+			index++
+			tokens = append(tokens, filterToken{text: text.String(), position: start + 1, quoted: true})
+			continue
+
+		case strings.ContainsRune(operatorCharacters, character):
+			index++
+			if index < len(expression) && expression[index] == '=' && character != '=' && character != ':' && character != '~' {
+				index++
+			}
+
+		default:
+			for index < len(expression) && !unicode.IsSpace(rune(expression[index])) &&
+				!strings.ContainsRune(operatorCharacters+"()\"", rune(expression[index])) {
+				index++
+			}
+		}
+
+		tokens = append(tokens, filterToken{text: expression[start:index], position: start + 1})
+	}
+
+	return tokens, nil
+}
+
+// A recursive descent parser over the tokens of a filter, collecting the
+// arguments of the condition it builds.
+type filterParser struct {
+	tokens    []filterToken
+	fields    Fields
+	index     int
+	end       int
+	depth     int
+	arguments []any
+}
+
+// Returns the current token, or a token with no text at the end of the filter.
+func (parser *filterParser) peek() filterToken {
+	if parser.index >= len(parser.tokens) {
+		return filterToken{position: parser.end}
+	}
+
+	return parser.tokens[parser.index]
+}
+
+// Reports whether the current token is the given unquoted keyword or symbol,
+// and moves past it if it is.
+func (parser *filterParser) accept(keyword string) bool {
+	token := parser.peek()
+
+	if token.quoted || !strings.EqualFold(token.text, keyword) {
+		return false
+	}
+
+	parser.index++
+	return true
+}
+
+func (parser *filterParser) fail(token filterToken, reason string) error {
+	return &SyntaxError{Parameter: "filter", Position: token.position, Token: token.text, Reason: reason}
+}
+
+// or = and { "or" and }
+func (parser *filterParser) or() (string, error) {
+	var condition strings.Builder
+
+	first, err := parser.and()
+	if err != nil {
+		return "", err
+	}
+
+	if parser.peek().quoted || !strings.EqualFold(parser.peek().text, "or") {
+		return first, nil
+	}
+
+	// Terms are joined into one flat list rather than nested pairs, which would
+	// nest as deep as there are terms.
+	condition.WriteString("(" + first)
+
+	for parser.accept("or") {
+		right, err := parser.and()
+		if err != nil {
+			return "", err
+		}
+
+		condition.WriteString(" OR " + right)
+	}
+
+	condition.WriteString(")")
+	return condition.String(), nil
+}
+
+// and = unary { "and" unary }
+func (parser *filterParser) and() (string, error) {
+	var condition strings.Builder
+
+	first, err := parser.unary()
+	if err != nil {
+		return "", err
+	}
+
+	if parser.peek().quoted || !strings.EqualFold(parser.peek().text, "and") {
+		return first, nil
+	}
+
+	// Terms are joined into one flat list rather than nested pairs, which would
+	// nest as deep as there are terms.
+	condition.WriteString("(" + first)
+
+	for parser.accept("and") {
+		right, err := parser.unary()
+		if err != nil {
+			return "", err
+		}
+
+		condition.WriteString(" AND " + right)
+	}
+
+	condition.WriteString(")")
+	return condition.String(), nil
+}
+
+// unary = "not" unary | "(" or ")" | term
+func (parser *filterParser) unary() (string, error) {
+	if token := parser.peek(); !token.quoted && (strings.EqualFold(token.text, "not") || token.text == "(") {
+		if parser.depth >= maximumFilterDepth {
+			return "", parser.fail(token, fmt.Sprintf("the filter nests deeper than %d levels", maximumFilterDepth))
+		}
+
+		parser.depth++
+		defer func() { parser.depth-- }()
+	}
+
+	if parser.accept("not") {
+		condition, err := parser.unary()
+		if err != nil {
+			return "", err
+		}
+
+		return "(NOT " + condition + ")", nil
+	}
+
+	if parser.accept("(") {
+		condition, err := parser.or()
+		if err != nil {
+			return "", err
+		}
+
+		if !parser.accept(")") {
+			return "", parser.fail(parser.peek(), "expected a closing parenthesis")
+		}
+
+		return condition, nil
+	}
+
+	return parser.term()
+}
+
+// term = field operator value
+func (parser *filterParser) term() (string, error) {
+	name := parser.peek()
+	if name.text == "" || name.quoted || strings.ContainsAny(name.text, operatorCharacters+"()") {
+		return "", parser.fail(name, "expected a field name")
+	}
+
+	field, exists := parser.fields[strings.ToLower(name.text)]
+	if !exists {
+		return "", parser.fail(name, "unknown field")
+	}
+
+	parser.index++
+
+	operator := parser.peek()
+	if operator.quoted || !allowedOperator(field.Kind, operator.text) {
+		return "", parser.fail(operator, fmt.Sprintf("expected one of %s after %s", strings.Join(operators[field.Kind], " "), name.text))
+	}
+
+	parser.index++
+
+	value := parser.peek()
+	if !value.quoted && (value.text == "" || strings.ContainsAny(value.text, operatorCharacters+"()")) {
+		return "", parser.fail(value, "expected a value")
+	}
+
+	parser.index++
+	return parser.compare(field, operator.text, value)
+}
+
+// Operators accepted by every kind of field.
+var operators map[int][]string = map[int][]string{
+	TextField:          {"=", "!=", "~"},
+	NumberField:        {"=", "!=", ">", ">=", "<", "<="},
+	DateField:          {"=", "!=", ">", ">=", "<", "<="},
+	TagField:           {":", "!="},
+	PersonField:        {":", "!="},
+	ContentRatingField: {":", "!="},
+}
+
+func allowedOperator(kind int, operator string) bool {
+	for _, allowed := range operators[kind] {
+		if allowed == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Builds the condition comparing field to value with operator.
+func (parser *filterParser) compare(field Field, operator string, value filterToken) (string, error) {
+	var negate string = ""
+
+	if operator == "!=" {
+		negate = "NOT "
+	}
+
+	switch field.Kind {
+	case TextField:
+		if operator == "~" {
+			escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value.text)
+			parser.arguments = append(parser.arguments, "%"+escaped+"%")
+			return field.Expression + ` LIKE ? ESCAPE '\'`, nil
+		}
+
+		parser.arguments = append(parser.arguments, value.text)
+		return field.Expression + " " + operator + " ? COLLATE NOCASE", nil
+
+	case NumberField:
+		number, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			return "", parser.fail(value, "expected a number")
+		}
+
+		parser.arguments = append(parser.arguments, number)
+		return field.Expression + " " + operator + " ?", nil
+
+	case DateField:
+		if _, err := time.Parse("2006-01-02 15:04:05", value.text); err == nil {
+			parser.arguments = append(parser.arguments, value.text)
+			return field.Expression + " " + operator + " ?", nil
+		}
+
+		if _, err := time.Parse("2006-01-02", value.text); err == nil {
+			parser.arguments = append(parser.arguments, value.text)
+			return "date(" + field.Expression + ") " + operator + " ?", nil
+		}
+
+		return "", parser.fail(value, `expected a date formatted as "2006-01-02" or "2006-01-02 15:04:05"`)
+
+	case TagField:
+		subquery, arguments := TagFilter([]string{value.text}, false)
+		parser.arguments = append(parser.arguments, arguments...)
+		return fmt.Sprintf("id %sIN (%s)", negate, subquery), nil
+
+	case PersonField:
+		parser.arguments = append(parser.arguments, value.text, value.text)
+		return fmt.Sprintf(
+			"id %sIN (SELECT credits.parent_id FROM credits JOIN people ON people.id = credits.person_id WHERE people.id = ? OR people.name = ? COLLATE NOCASE)",
+			negate,
+		), nil
+
+	default:
+		parser.arguments = append(parser.arguments, value.text, value.text)
+		return fmt.Sprintf(
+			"COALESCE(%s, '') %sIN (SELECT id FROM content_ratings WHERE id = ? OR code = ? COLLATE NOCASE)",
+			field.Expression,
+			negate,
+		), nil
+	}
+}
@@ -0,0 +1,39 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Returns the GraphQL query persisted under the SHA-256 hash of its text, or an
+// empty string if no query was persisted under it.
+func PersistedQuery(database *sql.DB, hash string) (string, error) {
+	var query string
+
+	if err := database.QueryRow(
+		"SELECT query FROM persisted_queries WHERE hash = ?",
+		hash,
+	).Scan(&query); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return query, nil
+}
+
+// Persists a GraphQL query under the SHA-256 hash of its text, so clients can
+// send the hash in place of the query from then on.
+func PersistQuery(database *sql.DB, hash string, query string) error {
+	_, err := database.Exec(
+		"INSERT OR IGNORE INTO persisted_queries (hash, query, upload_date) VALUES (?, ?, ?)",
+		hash,
+		query,
+		time.Now().Format("2006-01-02 15:04:05"),
+	)
+
+	return err
+}
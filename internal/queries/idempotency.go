@@ -0,0 +1,147 @@
+package queries
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// How long a response is kept for retries sending the same Idempotency-Key.
+const IdempotencyLifetime time.Duration = 24 * time.Hour
+
+// A request made with an Idempotency-Key. Status is 0 while the request is
+// still being processed, after which the response is kept to be replayed,
+// unless it was not replayable, in which case only its status is kept.
+type IdempotentRequest struct {
+	Fingerprint string
+	Status      int
+	Replayable  bool
+	ContentType string
+	Header      http.Header
+	Body        []byte
+}
+
+// Claims the key of the user for the request with the given fingerprint,
+// forgetting every expired key along the way. Returns nil if the key was
+// claimed, or the request that claimed it before otherwise.
+func ClaimIdempotencyKey(database *sql.DB, userId string, key string, fingerprint string) (*IdempotentRequest, error) {
+	var now time.Time = time.Now()
+
+	if _, err := database.Exec(`
+		DELETE FROM
+			idempotency_keys
+		WHERE
+			expiry_date <= ?
+		`,
+		now.Format("2006-01-02 15:04:05"),
+	); err != nil {
+		return nil, err
+	}
+
+	result, err := database.Exec(`
+		INSERT INTO
+			idempotency_keys (user_id, key, fingerprint, status, content_type, body, expiry_date)
+		VALUES
+			(?, ?, ?, 0, '', x'', ?)
+		ON CONFLICT DO NOTHING
+		`,
+		userId,
+		key,
+		fingerprint,
+		now.Add(IdempotencyLifetime).Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claimed, err := result.RowsAffected(); err != nil || claimed == 1 {
+		return nil, err
+	}
+
+	var request IdempotentRequest
+	var header string
+
+	if err := database.QueryRow(`
+		SELECT
+			fingerprint, status, replayable, content_type, header, body
+		FROM
+			idempotency_keys
+		WHERE
+			user_id = ? AND key = ?
+		`,
+		userId,
+		key,
+	).Scan(
+		&request.Fingerprint,
+		&request.Status,
+		&request.Replayable,
+		&request.ContentType,
+		&header,
+		&request.Body,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(header), &request.Header); err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// Keeps the response to the request that claimed the key of the user, so that
+// it can be replayed. Only the status of responses that are not replayable is
+// kept, their headers and body are left out.
+func CompleteIdempotencyKey(database *sql.DB, userId string, key string, response IdempotentRequest) error {
+	if !response.Replayable {
+		response.ContentType, response.Header, response.Body = "", nil, nil
+	}
+
+	header, err := json.Marshal(response.Header)
+	if err != nil {
+		return err
+	}
+
+	if response.Header == nil {
+		header = []byte("{}")
+	}
+
+	if response.Body == nil {
+		response.Body = []byte{}
+	}
+
+	_, err = database.Exec(`
+		UPDATE
+			idempotency_keys
+		SET
+			status = ?, replayable = ?, content_type = ?, header = ?, body = ?
+		WHERE
+			user_id = ? AND key = ?
+		`,
+		response.Status,
+		response.Replayable,
+		response.ContentType,
+		string(header),
+		response.Body,
+		userId,
+		key,
+	)
+
+	return err
+}
+
+// Forgets the key of the user, so that the request can be retried with it.
+func ReleaseIdempotencyKey(database *sql.DB, userId string, key string) error {
+	_, err := database.Exec(`
+		DELETE FROM
+			idempotency_keys
+		WHERE
+			user_id = ? AND key = ?
+		`,
+		userId,
+		key,
+	)
+
+	return err
+}
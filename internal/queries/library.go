@@ -0,0 +1,231 @@
+package queries
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns the shows that match condition, an SQL condition on the shows table,
+// in the order that order, an ORDER BY and LIMIT clause, describes. Covers and
+// episode links are filled in, embedded data such as tags is not.
+func Shows(database *sql.DB, condition string, arguments []any, order string) ([]types.Show, error) {
+	return readShows(database, condition, arguments, order, nil)
+}
+
+// Returns the shows of page among the shows that match condition, filled in
+// the same way as by Shows.
+func ShowPage(database *sql.DB, condition string, arguments []any, page *Page) ([]types.Show, error) {
+	seek, seekArguments := page.Condition()
+
+	shows, err := readShows(database, condition+" AND "+seek, slices.Concat(arguments, seekArguments), page.Order(), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return Trim(page, shows), nil
+}
+
+// Reads the shows of Shows, along with the sort keys of page unless it is nil.
+func readShows(database *sql.DB, condition string, arguments []any, order string, page *Page) ([]types.Show, error) {
+	var shows []types.Show = []types.Show{}
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				id, title, description, episode_count, hidden, COALESCE(content_rating_id, ''), upload_date, last_modified %s
+			FROM
+				shows
+			WHERE
+				%s
+			%s
+			`,
+			page.Columns(),
+			condition,
+			order,
+		),
+		arguments...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var show types.Show
+
+		if err := rows.Scan(append([]any{
+			&show.Id,
+			&show.Title,
+			&show.Description,
+			&show.EpisodeCount,
+			&show.Hidden,
+			&show.ContentRatingId,
+			&show.UploadDate,
+			&show.LastModified,
+		}, page.Scan()...)...); err != nil {
+			return nil, err
+		}
+
+		show.Episodes = map[string]any{
+			"count": show.EpisodeCount,
+			"url":   ("/api/v1/shows/" + show.Id + "/episodes"),
+		}
+
+		show.Cover = map[string]any{
+			"exists": true,
+			"url":    ("/api/v1/shows/" + show.Id + "/cover"),
+		}
+
+		shows = append(shows, show)
+	}
+
+	return shows, rows.Err()
+}
+
+// Returns the movies that match condition, an SQL condition on the movies
+// table, in the order that order describes. The counterpart of Shows.
+func Movies(database *sql.DB, condition string, arguments []any, order string) ([]types.Movie, error) {
+	return readMovies(database, condition, arguments, order, nil)
+}
+
+// Returns the movies of page among the movies that match condition. The
+// counterpart of ShowPage.
+func MoviePage(database *sql.DB, condition string, arguments []any, page *Page) ([]types.Movie, error) {
+	seek, seekArguments := page.Condition()
+
+	movies, err := readMovies(database, condition+" AND "+seek, slices.Concat(arguments, seekArguments), page.Order(), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return Trim(page, movies), nil
+}
+
+// Reads the movies of Movies, along with the sort keys of page unless it is nil.
+func readMovies(database *sql.DB, condition string, arguments []any, order string, page *Page) ([]types.Movie, error) {
+	var movies []types.Movie = []types.Movie{}
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				id, title, description, hidden, COALESCE(content_rating_id, ''), file_extension, file_name, upload_date, last_modified %s
+			FROM
+				movies
+			WHERE
+				%s
+			%s
+			`,
+			page.Columns(),
+			condition,
+			order,
+		),
+		arguments...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var movie types.Movie
+
+		if err := rows.Scan(append([]any{
+			&movie.Id,
+			&movie.Title,
+			&movie.Description,
+			&movie.Hidden,
+			&movie.ContentRatingId,
+			&movie.FileExtension,
+			&movie.FileName,
+			&movie.UploadDate,
+			&movie.LastModified,
+		}, page.Scan()...)...); err != nil {
+			return nil, err
+		}
+
+		movie.Cover = map[string]any{
+			"exists": true,
+			"url":    ("/api/v1/movies/" + movie.Id + "/cover"),
+		}
+
+		movies = append(movies, movie)
+	}
+
+	return movies, rows.Err()
+}
+
+// Returns the episodes that match condition, an SQL condition on the episodes
+// table, in the order that order describes. The content rating id falls back to
+// the rating of the show of episodes that are unrated themselves.
+func Episodes(database *sql.DB, condition string, arguments []any, order string) ([]types.Episode, error) {
+	return readEpisodes(database, condition, arguments, order, nil)
+}
+
+// Returns the episodes of page among the episodes that match condition. The
+// counterpart of ShowPage.
+func EpisodePage(database *sql.DB, condition string, arguments []any, page *Page) ([]types.Episode, error) {
+	seek, seekArguments := page.Condition()
+
+	episodes, err := readEpisodes(database, condition+" AND "+seek, slices.Concat(arguments, seekArguments), page.Order(), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return Trim(page, episodes), nil
+}
+
+// Reads the episodes of Episodes, along with the sort keys of page unless it
+// is nil.
+func readEpisodes(database *sql.DB, condition string, arguments []any, order string, page *Page) ([]types.Episode, error) {
+	var episodes []types.Episode = []types.Episode{}
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				id, COALESCE(parent_id, ''), season_number, COALESCE(episode_number, 0), COALESCE(title, ''),
+				COALESCE(description, ''), COALESCE(%s, ''), file_name, upload_date, last_modified %s
+			FROM
+				episodes
+			WHERE
+				%s
+			%s
+			`,
+			access.EpisodeRating,
+			page.Columns(),
+			condition,
+			order,
+		),
+		arguments...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var episode types.Episode
+
+		if err := rows.Scan(append([]any{
+			&episode.Id,
+			&episode.ParentId,
+			&episode.SeasonNumber,
+			&episode.EpisodeNumber,
+			&episode.Title,
+			&episode.Description,
+			&episode.ContentRatingId,
+			&episode.FileName,
+			&episode.UploadDate,
+			&episode.LastModified,
+		}, page.Scan()...)...); err != nil {
+			return nil, err
+		}
+
+		episodes = append(episodes, episode)
+	}
+
+	return episodes, rows.Err()
+}
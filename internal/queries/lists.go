@@ -0,0 +1,100 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Names of the lists a viewer keeps shows and movies in.
+const (
+	Watchlist string = "watchlist"
+	Favorites string = "favorites"
+)
+
+// Returns which of the given show or movie ids are on a list of the viewer.
+// Ids that are not on the list are left out.
+func ListMembership(database *sql.DB, viewerId string, list string, mediaIds []string) (map[string]bool, error) {
+	var members map[string]bool = map[string]bool{}
+	var arguments []any = []any{viewerId, list}
+
+	if len(mediaIds) == 0 {
+		return members, nil
+	}
+
+	for _, id := range mediaIds {
+		arguments = append(arguments, id)
+	}
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				media_id
+			FROM
+				list_items
+			WHERE
+				viewer_id = ? AND list = ? AND media_id IN (%s)
+			`,
+			Placeholders(len(mediaIds)),
+		),
+		arguments...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		members[id] = true
+	}
+
+	return members, rows.Err()
+}
+
+// Removes a show or movie from a list of the viewer and closes the gap it
+// leaves in the order. Returns whether it was on the list.
+func RemoveFromList(database *sql.DB, viewerId string, list string, mediaId string) (bool, error) {
+	var position int
+
+	if err := database.QueryRow(`
+		DELETE FROM
+			list_items
+		WHERE
+			viewer_id = ? AND list = ? AND media_id = ?
+		RETURNING
+			position
+		`,
+		viewerId,
+		list,
+		mediaId,
+	).Scan(&position); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if _, err := database.Exec(`
+		UPDATE
+			list_items
+		SET
+			position = position - 1
+		WHERE
+			viewer_id = ? AND list = ? AND position > ?
+		`,
+		viewerId,
+		list,
+		position,
+	); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
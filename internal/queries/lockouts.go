@@ -0,0 +1,93 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Counts wrong secrets entered for something, such as the PIN of a profile,
+// in a table keyed by column that holds failures and locked_until. Once it ran
+// out of attempts it is locked for first, which every further wrong secret
+// doubles, up to limit.
+type lockout struct {
+	table    string
+	column   string
+	attempts int
+	first    time.Duration
+	limit    time.Duration
+}
+
+// Returns until when no secret can be entered for id, which is the zero time
+// if it is not locked.
+func (lockout lockout) lockedUntil(database *sql.DB, id string) (time.Time, error) {
+	var lockedUntil string
+
+	if err := database.QueryRow(
+		fmt.Sprintf("SELECT locked_until FROM %s WHERE %s = ?", lockout.table, lockout.column),
+		id,
+	).Scan(&lockedUntil); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, err
+	}
+
+	if lockedUntil == "" {
+		return time.Time{}, nil
+	}
+
+	return time.ParseInLocation("2006-01-02 15:04:05", lockedUntil, time.Local)
+}
+
+// Counts a wrong secret against id, locking it once it ran out of attempts.
+// Returns until when it is locked, which is the zero time if it is not.
+func (lockout lockout) fail(database *sql.DB, id string) (time.Time, error) {
+	var failures int
+
+	if err := database.QueryRow(
+		fmt.Sprintf(`
+			INSERT INTO
+				%[1]s (%[2]s, failures, locked_until)
+			VALUES
+				(?, 1, '')
+			ON CONFLICT (%[2]s) DO UPDATE SET
+				failures = failures + 1
+			RETURNING
+				failures
+			`,
+			lockout.table,
+			lockout.column,
+		),
+		id,
+	).Scan(&failures); err != nil {
+		return time.Time{}, err
+	}
+
+	if failures < lockout.attempts {
+		return time.Time{}, nil
+	}
+
+	var duration time.Duration = lockout.limit
+	if doublings := failures - lockout.attempts; doublings < 16 && lockout.first<<doublings < lockout.limit {
+		duration = lockout.first << doublings
+	}
+
+	var lockedUntil time.Time = time.Now().Add(duration).Truncate(time.Second)
+
+	_, err := database.Exec(
+		fmt.Sprintf("UPDATE %s SET locked_until = ? WHERE %s = ?", lockout.table, lockout.column),
+		lockedUntil.Format("2006-01-02 15:04:05"),
+		id,
+	)
+
+	return lockedUntil, err
+}
+
+// Forgets the wrong secrets entered for id, once the right one was.
+func (lockout lockout) reset(database *sql.DB, id string) error {
+	_, err := database.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", lockout.table, lockout.column), id)
+	return err
+}
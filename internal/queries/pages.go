@@ -0,0 +1,216 @@
+package queries
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/andrewdotjs/watchify-server/internal/functions"
+)
+
+// Most items a page may hold, whatever limit the client asks for.
+const maximumPageLimit int = 100
+
+// A column or expression that a collection is ordered by. Expressions must
+// never be NULL, so wrap nullable columns in COALESCE. The last key of every
+// order must be unique, such as the id column, so that every item has a single
+// place in the order.
+type SortKey struct {
+	Expression string
+	Descending bool
+}
+
+// One page of a collection, read with keyset pagination. Pages start after or
+// before the item a cursor points at, which keeps them stable while items are
+// added or removed.
+type Page struct {
+	Limit int
+
+	keys     []SortKey
+	cursor   []any
+	backward bool
+	rows     [][]any
+	more     bool
+}
+
+// Reads the limit, after and before query values of the request into a page of
+// a collection ordered by keys. Returns an error if a cursor is malformed.
+func NewPage(r *http.Request, defaultLimit int, keys []SortKey) (*Page, error) {
+	var page Page = Page{
+		Limit: functions.Limit(r.URL.Query().Get("limit"), defaultLimit, maximumPageLimit),
+		keys:  keys,
+	}
+
+	cursor := r.URL.Query().Get("after")
+	if before := r.URL.Query().Get("before"); before != "" {
+		cursor = before
+		page.backward = true
+	}
+
+	if cursor == "" {
+		return &page, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(decoded, &page.cursor)
+	}
+
+	if err != nil || len(page.cursor) != len(page.keys) {
+		return nil, errors.New("the cursor is malformed")
+	}
+
+	return &page, nil
+}
+
+// Returns the sort keys as a list of columns, starting with a comma, to append
+// to the columns of the SELECT statement. Their values are scanned into the
+// destinations returned by Scan. Returns an empty string for a nil page.
+func (page *Page) Columns() string {
+	var columns []string
+
+	if page == nil {
+		return ""
+	}
+
+	for _, key := range page.keys {
+		columns = append(columns, key.Expression)
+	}
+
+	return ", " + strings.Join(columns, ", ")
+}
+
+// Returns an SQL condition that only lets through items past the cursor, along
+// with its arguments. Without a cursor every item is let through.
+func (page *Page) Condition() (string, []any) {
+	var alternatives []string
+	var arguments []any
+
+	if page.cursor == nil {
+		return "1 = 1", nil
+	}
+
+	for index, key := range page.keys {
+		var parts []string
+
+		for _, previous := range page.keys[:index] {
+			parts = append(parts, previous.Expression+" = ?")
+		}
+
+		comparison := ">"
+		if key.Descending != page.backward {
+			comparison = "<"
+		}
+
+		parts = append(parts, key.Expression+" "+comparison+" ?")
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+		arguments = append(arguments, page.cursor[:index+1]...)
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", arguments
+}
+
+// Returns the ORDER BY and LIMIT clauses of the page. One item more than the
+// limit is read to find out whether another page follows.
+func (page *Page) Order() string {
+	var parts []string
+
+	for _, key := range page.keys {
+		direction := "ASC"
+		if key.Descending != page.backward {
+			direction = "DESC"
+		}
+
+		parts = append(parts, key.Expression+" "+direction)
+	}
+
+	return fmt.Sprintf("ORDER BY %s LIMIT %d", strings.Join(parts, ", "), page.Limit+1)
+}
+
+// Returns destinations for the sort key columns of a row, to append to the
+// destinations passed to Scan. Call once for every row. Returns no
+// destinations for a nil page.
+func (page *Page) Scan() []any {
+	if page == nil {
+		return nil
+	}
+
+	var values []any = make([]any, len(page.keys))
+	var destinations []any
+
+	for index := range values {
+		destinations = append(destinations, &values[index])
+	}
+
+	page.rows = append(page.rows, values)
+	return destinations
+}
+
+// Returns the cursor pointing at the next page, or an empty string if this is
+// the last page.
+func (page *Page) Next() string {
+	if len(page.rows) == 0 || (!page.more && !page.backward) {
+		return ""
+	}
+
+	return encodeCursor(page.rows[len(page.rows)-1])
+}
+
+// Returns the cursor pointing at the previous page, or an empty string if this
+// is the first page.
+func (page *Page) Prev() string {
+	if len(page.rows) == 0 || (page.cursor == nil) || (!page.more && page.backward) {
+		return ""
+	}
+
+	return encodeCursor(page.rows[0])
+}
+
+// Drops the extra item read to look ahead and puts the items of a page read
+// backwards back in order. Call with the scanned items before using the
+// cursors of the page.
+func Trim[T any](page *Page, items []T) []T {
+	if len(items) > page.Limit {
+		page.more = true
+		items = items[:page.Limit]
+		page.rows = page.rows[:page.Limit]
+	}
+
+	if page.backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+			page.rows[i], page.rows[j] = page.rows[j], page.rows[i]
+		}
+	}
+
+	return items
+}
+
+// Returns the number of rows of from, a table along with any joins, that match
+// condition.
+func Count(database *sql.DB, from string, condition string, arguments []any) (int, error) {
+	var count int
+
+	err := database.QueryRow(
+		fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", from, condition),
+		arguments...,
+	).Scan(&count)
+
+	return count, err
+}
+
+// Encodes the sort key values of a row as an opaque cursor.
+func encodeCursor(values []any) string {
+	for index, value := range values {
+		if bytes, isBytes := value.([]byte); isBytes {
+			values[index] = string(bytes)
+		}
+	}
+
+	encoded, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
@@ -0,0 +1,169 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns the profile with the given id along with its maximum content rating.
+// Returns sql.ErrNoRows if the profile does not exist.
+func Profile(database *sql.DB, id string) (*types.Profile, error) {
+	var profile types.Profile
+	var maxRatingId, userId sql.NullString
+
+	if err := database.QueryRow(`
+		SELECT
+			id, user_id, name, max_content_rating_id, audio_language, subtitle_language, pin_hash, upload_date, last_modified
+		FROM
+			profiles
+		WHERE
+			id = ?
+		`,
+		id,
+	).Scan(
+		&profile.Id,
+		&userId,
+		&profile.Name,
+		&maxRatingId,
+		&profile.AudioLanguage,
+		&profile.SubtitleLanguage,
+		&profile.PinHash,
+		&profile.UploadDate,
+		&profile.LastModified,
+	); err != nil {
+		return nil, err
+	}
+
+	profile.UserId = userId.String
+	profile.HasPin = (profile.PinHash != "")
+
+	if maxRatingId.Valid {
+		ratings, err := ContentRatings(database)
+		if err != nil {
+			return nil, err
+		}
+
+		profile.MaxContentRating = ratings[maxRatingId.String]
+	}
+
+	return &profile, nil
+}
+
+// Returns the profile selected on the session or API token stored in the row of
+// table whose column matches digest, or nil if none is selected.
+func SelectedProfile(database *sql.DB, table string, column string, digest string) (*types.Profile, error) {
+	var profileId sql.NullString
+
+	if err := database.QueryRow(
+		fmt.Sprintf("SELECT profile_id FROM %s WHERE %s = ?", table, column),
+		digest,
+	).Scan(&profileId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if !profileId.Valid {
+		return nil, nil
+	}
+
+	return Profile(database, profileId.String)
+}
+
+// Selects the profile on the session or API token stored in the row of table
+// whose column matches digest. An empty profileId clears the selection.
+func SelectProfile(database *sql.DB, table string, column string, digest string, profileId string) error {
+	var value any = nil
+
+	if profileId != "" {
+		value = profileId
+	}
+
+	_, err := database.Exec(
+		fmt.Sprintf("UPDATE %s SET profile_id = ? WHERE %s = ?", table, column),
+		value,
+		digest,
+	)
+
+	return err
+}
+
+// Records in the watch history of the profile that playback of a movie or
+// episode was started.
+func RecordWatch(database *sql.DB, profileId string, mediaId string, mediaType string) error {
+	var now string = time.Now().Format("2006-01-02 15:04:05")
+
+	_, err := database.Exec(`
+		INSERT INTO
+			watch_history (profile_id, media_id, media_type, play_count, last_watched_date)
+		VALUES
+			(?, ?, ?, 1, ?)
+		ON CONFLICT (profile_id, media_id) DO UPDATE SET
+			play_count = play_count + 1, last_watched_date = excluded.last_watched_date
+		`,
+		profileId,
+		mediaId,
+		mediaType,
+		now,
+	)
+
+	return err
+}
+
+// Removes a profile along with its watch history, progress, lists and ratings, and clears it from every
+// session and API token it is selected on.
+func DeleteProfile(database *sql.DB, id string) error {
+	for _, statement := range []string{
+		"UPDATE sessions SET profile_id = NULL WHERE profile_id = ?",
+		"UPDATE api_tokens SET profile_id = NULL WHERE profile_id = ?",
+		"DELETE FROM watch_history WHERE profile_id = ?",
+		"DELETE FROM pin_failures WHERE profile_id = ?",
+		"DELETE FROM progress WHERE viewer_id = ?",
+		"DELETE FROM list_items WHERE viewer_id = ?",
+		"DELETE FROM user_ratings WHERE viewer_id = ?",
+		"DELETE FROM profiles WHERE id = ?",
+	} {
+		if _, err := database.Exec(statement, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// How many wrong PINs can be entered for a profile before it is locked.
+const PinAttempts int = 5
+
+// How long a profile is locked once it ran out of PIN attempts. Every further
+// wrong PIN doubles it, up to PinLockoutLimit.
+const PinLockout time.Duration = time.Minute
+
+// Longest a profile is locked for.
+const PinLockoutLimit time.Duration = time.Hour
+
+// Wrong PINs entered for profiles.
+var pinLockout = lockout{"pin_failures", "profile_id", PinAttempts, PinLockout, PinLockoutLimit}
+
+// Returns until when no PIN can be entered for a profile, which is the zero
+// time if it is not locked.
+func PinLockedUntil(database *sql.DB, profileId string) (time.Time, error) {
+	return pinLockout.lockedUntil(database, profileId)
+}
+
+// Counts a wrong PIN against a profile, locking it once it ran out of
+// attempts. Returns until when it is locked, which is the zero time if it
+// is not.
+func FailPin(database *sql.DB, profileId string) (time.Time, error) {
+	return pinLockout.fail(database, profileId)
+}
+
+// Forgets the wrong PINs entered for a profile, once the right one was.
+func ResetPinFailures(database *sql.DB, profileId string) error {
+	return pinLockout.reset(database, profileId)
+}
@@ -0,0 +1,86 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Percentage of a video that must be watched for it to count as watched when the
+// watched_threshold setting is missing or invalid.
+const defaultWatchedThreshold float64 = 90
+
+// Returns the playback progress of the viewer on every given movie or episode
+// id, keyed by that id. Videos the viewer never played are left out.
+func ProgressFor(database *sql.DB, viewerId string, mediaIds []string) (map[string]*types.Progress, error) {
+	var progress map[string]*types.Progress = map[string]*types.Progress{}
+	var arguments []any = []any{viewerId}
+
+	if len(mediaIds) == 0 {
+		return progress, nil
+	}
+
+	for _, id := range mediaIds {
+		arguments = append(arguments, id)
+	}
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				media_id, media_type, position, duration, watched, last_watched_date
+			FROM
+				progress
+			WHERE
+				viewer_id = ? AND media_id IN (%s)
+			`,
+			Placeholders(len(mediaIds)),
+		),
+		arguments...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var entry types.Progress
+
+		if err := rows.Scan(
+			&entry.MediaId,
+			&entry.MediaType,
+			&entry.Position,
+			&entry.Duration,
+			&entry.Watched,
+			&entry.LastWatchedDate,
+		); err != nil {
+			return nil, err
+		}
+
+		progress[entry.MediaId] = &entry
+	}
+
+	return progress, rows.Err()
+}
+
+// Returns the percentage of a video that must be watched for it to be marked as
+// watched, following the watched_threshold setting.
+func WatchedThreshold(database *sql.DB) (float64, error) {
+	value, err := Setting(database, "watched_threshold")
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return defaultWatchedThreshold, nil
+		}
+
+		return 0, err
+	}
+
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold <= 0 || threshold > 100 {
+		return defaultWatchedThreshold, nil
+	}
+
+	return threshold, nil
+}
@@ -0,0 +1,43 @@
+package queries
+
+import (
+	"database/sql"
+
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns every content rating, keyed by its id. The table is small enough to
+// read in full whenever ratings need to be attached to content.
+func ContentRatings(database *sql.DB) (map[string]*types.ContentRating, error) {
+	var ratings map[string]*types.ContentRating = map[string]*types.ContentRating{}
+
+	rows, err := database.Query(`
+		SELECT
+			id, system, code, rank, description
+		FROM
+			content_ratings
+		`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var rating types.ContentRating
+
+		if err := rows.Scan(
+			&rating.Id,
+			&rating.System,
+			&rating.Code,
+			&rating.Rank,
+			&rating.Description,
+		); err != nil {
+			return nil, err
+		}
+
+		ratings[rating.Id] = &rating
+	}
+
+	return ratings, rows.Err()
+}
@@ -0,0 +1,27 @@
+package queries
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+// Time between recomputing recommendations when the recommendation_interval
+// setting is missing or invalid.
+const defaultRecommendationInterval time.Duration = time.Hour
+
+// Returns how long to wait between recomputing recommendations, following the
+// recommendation_interval setting.
+func RecommendationInterval(database *sql.DB) time.Duration {
+	value, err := Setting(database, "recommendation_interval")
+	if err != nil {
+		return defaultRecommendationInterval
+	}
+
+	minutes, err := strconv.Atoi(value)
+	if err != nil || minutes < 1 {
+		return defaultRecommendationInterval
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
@@ -0,0 +1,68 @@
+package queries
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns an SQL expression for the average user score of rows of table, 0 for
+// unrated rows, for ordering collections by rating.
+func AverageRating(table string) string {
+	return fmt.Sprintf("COALESCE((SELECT AVG(score) FROM user_ratings WHERE user_ratings.media_id = %s.id), 0)", table)
+}
+
+// Returns the average user score and number of scores of every given movie,
+// show or episode id, keyed by that id, along with the score the viewer gave.
+// Ids nobody rated are left out.
+func RatingsFor(database *sql.DB, viewerId string, mediaIds []string) (map[string]*types.RatingSummary, error) {
+	var ratings map[string]*types.RatingSummary = map[string]*types.RatingSummary{}
+	var arguments []any = []any{viewerId}
+
+	if len(mediaIds) == 0 {
+		return ratings, nil
+	}
+
+	for _, id := range mediaIds {
+		arguments = append(arguments, id)
+	}
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				media_id, AVG(score), COUNT(*), COALESCE(MAX(CASE WHEN viewer_id = ? THEN score END), 0)
+			FROM
+				user_ratings
+			WHERE
+				media_id IN (%s)
+			GROUP BY
+				media_id
+			`,
+			Placeholders(len(mediaIds)),
+		),
+		arguments...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var summary types.RatingSummary
+
+		if err := rows.Scan(
+			&id,
+			&summary.Average,
+			&summary.Count,
+			&summary.Score,
+		); err != nil {
+			return nil, err
+		}
+
+		ratings[id] = &summary
+	}
+
+	return ratings, rows.Err()
+}
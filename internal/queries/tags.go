@@ -0,0 +1,126 @@
+package queries
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// Returns a comma separated list of count SQL placeholders, ready to be used
+// within an IN (...) clause.
+func Placeholders(count int) string {
+	if count <= 0 {
+		return ""
+	}
+
+	return strings.Repeat("?, ", count-1) + "?"
+}
+
+// Builds a subquery selecting the ids of all content tagged with the given tags,
+// which can be either tag ids or tag names. If matchAll is true, content must
+// carry every tag (AND), otherwise any of them (OR). Repeated tags count once,
+// and a tag given by both its id and its name is carried as soon as it is.
+//
+// Meant to be used as "WHERE id IN (<subquery>)". Returns the subquery along
+// with its arguments.
+func TagFilter(tags []string, matchAll bool) (string, []any) {
+	var arguments []any
+	var having string = ""
+	var distinct []string
+
+	for _, tag := range tags {
+		if !slices.Contains(distinct, tag) {
+			distinct = append(distinct, tag)
+		}
+	}
+
+	for _, tag := range distinct {
+		arguments = append(arguments, tag)
+	}
+
+	for _, tag := range distinct {
+		arguments = append(arguments, tag)
+	}
+
+	// Every tag has to be carried by at least one of the tags of the content.
+	if matchAll {
+		var carried []string
+
+		for _, tag := range distinct {
+			carried = append(carried, "MAX(tags.id = ? OR tags.name = ?)")
+			arguments = append(arguments, tag, tag)
+		}
+
+		having = "HAVING " + strings.Join(carried, " AND ")
+	}
+
+	return fmt.Sprintf(`
+		SELECT
+			taggings.parent_id
+		FROM
+			taggings
+		JOIN
+			tags ON tags.id = taggings.tag_id
+		WHERE
+			tags.id IN (%[1]s) OR tags.name IN (%[1]s)
+		GROUP BY
+			taggings.parent_id
+		%[2]s
+		`,
+		Placeholders(len(distinct)),
+		having,
+	), arguments
+}
+
+// Returns the tags of every given parent (show or movie) id, keyed by the parent
+// id. Done in a single query so list endpoints don't query once per item.
+func TagsFor(database *sql.DB, parentIds []string) (map[string][]types.Tag, error) {
+	var tags map[string][]types.Tag = map[string][]types.Tag{}
+	var arguments []any
+
+	if len(parentIds) == 0 {
+		return tags, nil
+	}
+
+	for _, id := range parentIds {
+		arguments = append(arguments, id)
+	}
+
+	rows, err := database.Query(
+		fmt.Sprintf(`
+			SELECT
+				taggings.parent_id, tags.id, tags.name, tags.kind
+			FROM
+				taggings
+			JOIN
+				tags ON tags.id = taggings.tag_id
+			WHERE
+				taggings.parent_id IN (%s)
+			ORDER BY
+				tags.kind, tags.name
+			`,
+			Placeholders(len(parentIds)),
+		),
+		arguments...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var parentId string
+		var tag types.Tag
+
+		if err := rows.Scan(&parentId, &tag.Id, &tag.Name, &tag.Kind); err != nil {
+			return nil, err
+		}
+
+		tags[parentId] = append(tags[parentId], tag)
+	}
+
+	return tags, rows.Err()
+}
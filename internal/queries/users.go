@@ -0,0 +1,323 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/secrets"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+)
+
+// How long a session stays valid after logging in.
+const SessionLifetime time.Duration = 30 * 24 * time.Hour
+
+// Returns the user with the given id. Returns sql.ErrNoRows if the user does
+// not exist.
+func User(database *sql.DB, id string) (*types.User, error) {
+	return scanUser(database.QueryRow(`
+		SELECT
+			id, username, password_hash, role, totp_enabled, totp_secret, totp_counter, upload_date, last_modified
+		FROM
+			users
+		WHERE
+			id = ?
+		`,
+		id,
+	))
+}
+
+// Returns the user with the given username, ignoring case. Returns
+// sql.ErrNoRows if the user does not exist.
+func UserByUsername(database *sql.DB, username string) (*types.User, error) {
+	return scanUser(database.QueryRow(`
+		SELECT
+			id, username, password_hash, role, totp_enabled, totp_secret, totp_counter, upload_date, last_modified
+		FROM
+			users
+		WHERE
+			username = ?
+		`,
+		username,
+	))
+}
+
+// Scans a row of users selected by User or UserByUsername.
+func scanUser(row *sql.Row) (*types.User, error) {
+	var user types.User
+
+	if err := row.Scan(
+		&user.Id,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Role,
+		&user.TwoFactorEnabled,
+		&user.TotpSecret,
+		&user.TotpCounter,
+		&user.UploadDate,
+		&user.LastModified,
+	); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Starts a new session for the user. Only the digest of the session token is
+// stored, the token itself is returned so it can be handed to the client along
+// with the expiry of the session.
+func CreateSession(database *sql.DB, userId string) (string, time.Time, error) {
+	var now time.Time = time.Now()
+	var expiry time.Time = now.Add(SessionLifetime)
+
+	token, err := secrets.Token()
+	if err != nil {
+		return "", expiry, err
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			sessions (id, user_id, expiry_date, upload_date)
+		VALUES
+			(?, ?, ?, ?)
+		`,
+		secrets.Digest(token),
+		userId,
+		expiry.Format("2006-01-02 15:04:05"),
+		now.Format("2006-01-02 15:04:05"),
+	); err != nil {
+		return "", expiry, err
+	}
+
+	return token, expiry, nil
+}
+
+// Returns the user that owns the session with the given token, as long as the
+// session has not expired. Returns sql.ErrNoRows if there is no such session.
+func SessionUser(database *sql.DB, token string) (*types.User, error) {
+	var userId string
+
+	if err := database.QueryRow(`
+		SELECT
+			user_id
+		FROM
+			sessions
+		WHERE
+			id = ? AND expiry_date > ?
+		`,
+		secrets.Digest(token),
+		time.Now().Format("2006-01-02 15:04:05"),
+	).Scan(&userId); err != nil {
+		return nil, err
+	}
+
+	return User(database, userId)
+}
+
+// Ends the session with the given token, along with every expired session.
+func DeleteSession(database *sql.DB, token string) error {
+	_, err := database.Exec(`
+		DELETE FROM
+			sessions
+		WHERE
+			id = ? OR expiry_date <= ?
+		`,
+		secrets.Digest(token),
+		time.Now().Format("2006-01-02 15:04:05"),
+	)
+
+	return err
+}
+
+// Returns the user that owns the API token, along with the scope of the token,
+// as long as the token has not expired. Records the token as used. Returns
+// sql.ErrNoRows if there is no such token.
+func TokenUser(database *sql.DB, token string) (*types.User, string, error) {
+	var userId, scope string
+	var now string = time.Now().Format("2006-01-02 15:04:05")
+
+	if err := database.QueryRow(`
+		UPDATE
+			api_tokens
+		SET
+			last_used_date = ?
+		WHERE
+			digest = ? AND (expiry_date IS NULL OR expiry_date > ?)
+		RETURNING
+			user_id, scope
+		`,
+		now,
+		secrets.Digest(token),
+		now,
+	).Scan(&userId, &scope); err != nil {
+		return nil, "", err
+	}
+
+	user, err := User(database, userId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, scope, nil
+}
+
+// Returns the value of a server setting. Returns sql.ErrNoRows if the setting
+// does not exist.
+func Setting(database *sql.DB, key string) (string, error) {
+	var value string
+
+	err := database.QueryRow(`
+		SELECT
+			value
+		FROM
+			settings
+		WHERE
+			key = ?
+		`,
+		key,
+	).Scan(&value)
+
+	return value, err
+}
+
+// How long users have to enter their second factor after their password.
+const ChallengeLifetime time.Duration = 5 * time.Minute
+
+// How many codes can be tried against a single login challenge.
+const ChallengeAttempts int = 5
+
+// Starts a login challenge for a user that passed the password check but still
+// has to enter a second factor. Returns the challenge token and its expiry.
+func CreateChallenge(database *sql.DB, userId string) (string, time.Time, error) {
+	var expiry time.Time = time.Now().Add(ChallengeLifetime)
+
+	token, err := secrets.Token()
+	if err != nil {
+		return "", expiry, err
+	}
+
+	if _, err := database.Exec(`
+		INSERT INTO
+			login_challenges (id, user_id, attempts, expiry_date)
+		VALUES
+			(?, ?, 0, ?)
+		`,
+		secrets.Digest(token),
+		userId,
+		expiry.Format("2006-01-02 15:04:05"),
+	); err != nil {
+		return "", expiry, err
+	}
+
+	return token, expiry, nil
+}
+
+// Returns the user of a login challenge and counts an attempt against it, as
+// long as the challenge has not expired or run out of attempts. Returns
+// sql.ErrNoRows if there is no such challenge.
+func ChallengeUser(database *sql.DB, token string) (*types.User, error) {
+	var userId string
+
+	if err := database.QueryRow(`
+		UPDATE
+			login_challenges
+		SET
+			attempts = attempts + 1
+		WHERE
+			id = ? AND attempts < ? AND expiry_date > ?
+		RETURNING
+			user_id
+		`,
+		secrets.Digest(token),
+		ChallengeAttempts,
+		time.Now().Format("2006-01-02 15:04:05"),
+	).Scan(&userId); err != nil {
+		return nil, err
+	}
+
+	return User(database, userId)
+}
+
+// How many wrong codes a user can enter before their second factor is locked,
+// across every login challenge and every other place a code is asked for.
+const TwoFactorAttempts int = 5
+
+// How long the second factor of a user is locked once they ran out of
+// attempts. Every further wrong code doubles it, up to TwoFactorLockoutLimit.
+const TwoFactorLockout time.Duration = time.Minute
+
+// Longest the second factor of a user is locked for.
+const TwoFactorLockoutLimit time.Duration = time.Hour
+
+// Wrong codes entered for the second factor of users.
+var twoFactorLockout = lockout{"two_factor_failures", "user_id", TwoFactorAttempts, TwoFactorLockout, TwoFactorLockoutLimit}
+
+// Returns until when no code can be entered for the second factor of a user,
+// which is the zero time if it is not locked.
+func TwoFactorLockedUntil(database *sql.DB, userId string) (time.Time, error) {
+	return twoFactorLockout.lockedUntil(database, userId)
+}
+
+// Counts a wrong code against the second factor of a user, locking it once
+// they ran out of attempts. Returns until when it is locked, which is the zero
+// time if it is not.
+func FailTwoFactor(database *sql.DB, userId string) (time.Time, error) {
+	return twoFactorLockout.fail(database, userId)
+}
+
+// Forgets the wrong codes entered by a user, once the right one was.
+func ResetTwoFactorFailures(database *sql.DB, userId string) error {
+	return twoFactorLockout.reset(database, userId)
+}
+
+// Ends a login challenge, along with every expired challenge.
+func DeleteChallenge(database *sql.DB, token string) error {
+	_, err := database.Exec(`
+		DELETE FROM
+			login_challenges
+		WHERE
+			id = ? OR expiry_date <= ?
+		`,
+		secrets.Digest(token),
+		time.Now().Format("2006-01-02 15:04:05"),
+	)
+
+	return err
+}
+
+// Roles that the require_two_factor setting applies to.
+var twoFactorRoles = map[string]bool{
+	"admin":    true,
+	"uploader": true,
+}
+
+// Reports whether users with the role must enable two-factor authentication,
+// following the require_two_factor setting.
+func TwoFactorRequired(database *sql.DB, role string) (bool, error) {
+	if !twoFactorRoles[role] {
+		return false, nil
+	}
+
+	value, err := Setting(database, "require_two_factor")
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	return value == "true", nil
+}
+
+// Turns two-factor authentication off for the user and forgets their secret,
+// recovery codes and wrong codes.
+func ResetTwoFactor(database *sql.DB, userId string) error {
+	for _, statement := range []string{
+		"UPDATE users SET totp_enabled = 0, totp_secret = '', totp_counter = 0 WHERE id = ?",
+		"DELETE FROM recovery_codes WHERE user_id = ?",
+		"DELETE FROM two_factor_failures WHERE user_id = ?",
+	} {
+		if _, err := database.Exec(statement, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
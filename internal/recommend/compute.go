@@ -0,0 +1,243 @@
+package recommend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/google/uuid"
+)
+
+// A pair of items, ordered so that each pair is only counted once.
+type pair struct {
+	first  string
+	second string
+}
+
+// Rebuilds the cache from the current library, viewing activity and ratings.
+// Requests keep being answered from the previous cache while this runs.
+func (engine *Engine) Compute() error {
+	var types map[string]string = map[string]string{}
+	var shows map[string]string = map[string]string{}
+
+	if err := engine.collect(`
+		SELECT id, 'movie' FROM movies
+		UNION ALL
+		SELECT id, 'show' FROM shows
+		`,
+		func(id string, mediaType string) { types[id] = mediaType },
+	); err != nil {
+		return err
+	}
+
+	if err := engine.collect(
+		"SELECT id, parent_id FROM episodes",
+		func(id string, show string) { shows[id] = show },
+	); err != nil {
+		return err
+	}
+
+	// Maps episodes onto their show and drops ids that are no longer in the
+	// library.
+	item := func(id string) string {
+		if show, found := shows[id]; found {
+			id = show
+		}
+
+		if _, found := types[id]; !found {
+			return ""
+		}
+
+		return id
+	}
+
+	viewers, err := engine.features(`
+		SELECT viewer_id, media_id FROM progress
+		UNION
+		SELECT profile_id, media_id FROM watch_history
+		UNION
+		SELECT viewer_id, media_id FROM user_ratings WHERE score >= 6
+		`,
+		item,
+	)
+	if err != nil {
+		return err
+	}
+
+	tags, err := engine.features("SELECT tag_id, parent_id FROM taggings", item)
+	if err != nil {
+		return err
+	}
+
+	people, err := engine.features("SELECT person_id, parent_id FROM credits", item)
+	if err != nil {
+		return err
+	}
+
+	similarity := map[pair]float64{}
+	for _, signal := range []struct {
+		features map[string]map[string]bool
+		weight   float64
+		measure  func(shared int, first int, second int) float64
+	}{
+		{viewers, watchWeight, cosine},
+		{tags, tagWeight, jaccard},
+		{people, peopleWeight, jaccard},
+	} {
+		for key, score := range overlap(signal.features, signal.measure) {
+			similarity[key] += signal.weight * score
+		}
+	}
+
+	neighbors := map[string][]Neighbor{}
+	for key, score := range similarity {
+		neighbors[key.first] = append(neighbors[key.first], Neighbor{Id: key.second, MediaType: types[key.second], Score: score})
+		neighbors[key.second] = append(neighbors[key.second], Neighbor{Id: key.first, MediaType: types[key.first], Score: score})
+	}
+
+	for id := range neighbors {
+		rank(neighbors[id])
+
+		if len(neighbors[id]) > neighborCount {
+			neighbors[id] = neighbors[id][:neighborCount]
+		}
+	}
+
+	popular := []Neighbor{}
+	for id, mediaType := range types {
+		popular = append(popular, Neighbor{Id: id, MediaType: mediaType, Score: float64(len(viewers[id]))})
+	}
+
+	rank(popular)
+
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+
+	engine.types = types
+	engine.shows = shows
+	engine.neighbors = neighbors
+	engine.popular = popular
+	engine.computed = time.Now()
+
+	return nil
+}
+
+// Computes the cache right away and then every interval returned by interval,
+// which is asked again after each run so that changes to it take effect. Runs
+// until the process exits.
+func (engine *Engine) Start(interval func() time.Duration, log *logger.Logger) {
+	var functionId string = uuid.NewString()
+
+	go func() {
+		for {
+			started := time.Now()
+
+			if err := engine.Compute(); err != nil {
+				log.Error(functionId, fmt.Sprintf("Failed to compute recommendations. %v", err))
+			} else {
+				log.Info(functionId, fmt.Sprintf("Computed recommendations in %v", time.Since(started)))
+			}
+
+			time.Sleep(interval())
+		}
+	}()
+}
+
+// Runs a query that returns two text columns and hands every row to add.
+func (engine *Engine) collect(query string, add func(first string, second string)) error {
+	rows, err := engine.database.Query(query)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var first, second string
+
+		if err := rows.Scan(&first, &second); err != nil {
+			return err
+		}
+
+		add(first, second)
+	}
+
+	return rows.Err()
+}
+
+// Runs a query that returns feature and id pairs, such as a viewer and what
+// they watched, and returns the set of features of every item that item maps
+// the id to.
+func (engine *Engine) features(query string, item func(id string) string) (map[string]map[string]bool, error) {
+	var features map[string]map[string]bool = map[string]map[string]bool{}
+
+	err := engine.collect(query, func(feature string, id string) {
+		if id = item(id); id == "" {
+			return
+		}
+
+		if features[id] == nil {
+			features[id] = map[string]bool{}
+		}
+
+		features[id][feature] = true
+	})
+
+	return features, err
+}
+
+// Returns the similarity, following measure, of every pair of items that share
+// at least one feature. Pairs are found through the items of each feature so
+// that items with nothing in common are never compared.
+func overlap(features map[string]map[string]bool, measure func(shared int, first int, second int) float64) map[pair]float64 {
+	var items map[string][]string = map[string][]string{}
+	var shared map[pair]int = map[pair]int{}
+	var scores map[pair]float64 = map[pair]float64{}
+
+	for id, set := range features {
+		for feature := range set {
+			items[feature] = append(items[feature], id)
+		}
+	}
+
+	for _, ids := range items {
+		sort.Strings(ids)
+
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				shared[pair{ids[i], ids[j]}]++
+			}
+		}
+	}
+
+	for key, count := range shared {
+		scores[key] = measure(count, len(features[key.first]), len(features[key.second]))
+	}
+
+	return scores
+}
+
+// Cosine similarity of two sets, given the size of their intersection and of
+// each set.
+func cosine(shared int, first int, second int) float64 {
+	return float64(shared) / math.Sqrt(float64(first)*float64(second))
+}
+
+// Jaccard similarity of two sets, given the size of their intersection and of
+// each set.
+func jaccard(shared int, first int, second int) float64 {
+	return float64(shared) / float64(first+second-shared)
+}
+
+// Sorts items by score, highest first, breaking ties by id so that results are
+// stable between runs.
+func rank(items []Neighbor) {
+	sort.Slice(items, func(i int, j int) bool {
+		if items[i].Score != items[j].Score {
+			return items[i].Score > items[j].Score
+		}
+
+		return items[i].Id < items[j].Id
+	})
+}
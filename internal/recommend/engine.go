@@ -0,0 +1,191 @@
+package recommend
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// Number of similar items kept for every item.
+const neighborCount int = 50
+
+// How much each signal contributes to the similarity of two items. Co-watching
+// is the strongest hint, shared tags and shared people fill in for items that
+// few viewers watched.
+const (
+	watchWeight  float64 = 0.5
+	tagWeight    float64 = 0.3
+	peopleWeight float64 = 0.2
+)
+
+// An item along with how strongly it relates to another item or a viewer.
+type Neighbor struct {
+	Id        string
+	MediaType string
+	Score     float64
+}
+
+// Computes and caches recommendations from the library, viewing activity and
+// ratings. Everything happens in-process, so recommendations work without any
+// outside service.
+type Engine struct {
+	database *sql.DB
+
+	mutex     sync.RWMutex
+	types     map[string]string     // media type of every show and movie
+	shows     map[string]string     // show of every episode
+	neighbors map[string][]Neighbor // most similar items of every item, most similar first
+	popular   []Neighbor            // items by number of viewers, most watched first
+	computed  time.Time
+}
+
+// Returns an engine for the database. Nothing is recommended until Compute has
+// run at least once.
+func New(database *sql.DB) *Engine {
+	return &Engine{
+		database:  database,
+		types:     map[string]string{},
+		shows:     map[string]string{},
+		neighbors: map[string][]Neighbor{},
+	}
+}
+
+// Returns when the cache was last computed, or the zero time if it never was.
+func (engine *Engine) Computed() time.Time {
+	engine.mutex.RLock()
+	defer engine.mutex.RUnlock()
+
+	return engine.computed
+}
+
+// Returns up to limit items that are most similar to the show or movie, most
+// similar first. Returns nil if the id is unknown.
+func (engine *Engine) Similar(id string, limit int) []Neighbor {
+	engine.mutex.RLock()
+	defer engine.mutex.RUnlock()
+
+	neighbors := engine.neighbors[id]
+	if len(neighbors) > limit {
+		neighbors = neighbors[:limit]
+	}
+
+	return append([]Neighbor(nil), neighbors...)
+}
+
+// Returns the items that best match what the viewer watched and how they rated
+// it, best match first. Items the viewer already watched or rated are left out.
+// When there is too little to go on, the list is filled up with the items most
+// viewers watched.
+func (engine *Engine) Recommend(viewerId string) ([]Neighbor, error) {
+	var scores map[string]float64 = map[string]float64{}
+	var ranked []Neighbor
+
+	seeds, err := engine.seeds(viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	engine.mutex.RLock()
+	defer engine.mutex.RUnlock()
+
+	for seed, weight := range seeds {
+		for _, neighbor := range engine.neighbors[seed] {
+			scores[neighbor.Id] += weight * neighbor.Score
+		}
+	}
+
+	for id, score := range scores {
+		if _, seen := seeds[id]; seen || score <= 0 {
+			continue
+		}
+
+		ranked = append(ranked, Neighbor{Id: id, MediaType: engine.types[id], Score: score})
+	}
+
+	rank(ranked)
+
+	for _, item := range engine.popular {
+		if _, seen := seeds[item.Id]; seen || scores[item.Id] > 0 {
+			continue
+		}
+
+		ranked = append(ranked, Neighbor{Id: item.Id, MediaType: item.MediaType})
+	}
+
+	return ranked, nil
+}
+
+// Returns the shows and movies the viewer interacted with, weighted by how much
+// they liked them. Watched items weigh 1, rated items weigh from -1 for the
+// lowest score to 1 for the highest.
+func (engine *Engine) seeds(viewerId string) (map[string]float64, error) {
+	var seeds map[string]float64 = map[string]float64{}
+
+	rows, err := engine.database.Query(`
+		SELECT media_id FROM progress WHERE viewer_id = ?
+		UNION
+		SELECT media_id FROM watch_history WHERE profile_id = ?
+		`,
+		viewerId,
+		viewerId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		seeds[engine.item(id)] = 1
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ratings, err := engine.database.Query(`
+		SELECT
+			media_id, score
+		FROM
+			user_ratings
+		WHERE
+			viewer_id = ?
+		`,
+		viewerId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer ratings.Close()
+	for ratings.Next() {
+		var id string
+		var score float64
+
+		if err := ratings.Scan(&id, &score); err != nil {
+			return nil, err
+		}
+
+		seeds[engine.item(id)] = (score - 5.5) / 4.5
+	}
+
+	return seeds, ratings.Err()
+}
+
+// Returns the show an episode id belongs to, or the id itself for shows and
+// movies.
+func (engine *Engine) item(id string) string {
+	engine.mutex.RLock()
+	defer engine.mutex.RUnlock()
+
+	if show, found := engine.shows[id]; found {
+		return show
+	}
+
+	return id
+}
@@ -0,0 +1,58 @@
+package responses
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type Page struct {
+	Status int    `json:"status"`
+	Data   any    `json:"data"`
+	Total  int    `json:"total"`          // number of items in the whole collection
+	Next   string `json:"next,omitempty"` // URL of the next page, if any
+	Prev   string `json:"prev,omitempty"` // URL of the previous page, if any
+}
+
+// Builds a page of data out of a collection of total items. The next and prev
+// cursors are turned into links to the same request starting after or before
+// them. Empty cursors leave the link out.
+func Paged(r *http.Request, data any, total int, next string, prev string) Page {
+	return Page{
+		Status: 200,
+		Data:   data,
+		Total:  total,
+		Next:   pageLink(r, "after", next),
+		Prev:   pageLink(r, "before", prev),
+	}
+}
+
+// Takes a built Page struct and converts it into JSON-compatible bytes
+// using the "encoding/json" library then sends to client through provided
+// ResponseWriter. Data that cannot be marshalled is a bug, so it panics, which
+// middleware.Recover turns into an internal problem.
+func (page Page) ToClient(w http.ResponseWriter) {
+	json, err := json.Marshal(page)
+	if err != nil {
+		panic(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", "en")
+	w.WriteHeader(page.Status)
+	w.Write(json)
+}
+
+// Returns the path and query of the request with the page cursors replaced by
+// key set to cursor, or an empty string if there is no cursor.
+func pageLink(r *http.Request, key string, cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+
+	query := r.URL.Query()
+	query.Del("after")
+	query.Del("before")
+	query.Set(key, cursor)
+
+	return r.URL.Path + "?" + query.Encode()
+}
@@ -0,0 +1,46 @@
+package responses
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/access"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
+)
+
+// The body of a problem response, as described by RFC 7807. Type is the URI of
+// a type of the problems catalog, which clients can branch on.
+type Problem struct {
+	Type          string                  `json:"type"`
+	Title         string                  `json:"title"`
+	Status        int                     `json:"status"`
+	Detail        string                  `json:"detail"`
+	Instance      string                  `json:"instance"`
+	RequestId     string                  `json:"request_id,omitempty"`
+	InvalidParams []problems.InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// Sends err to the client as a problem. Errors that are not problems are sent
+// as internal problems, without their message.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	var problem *problems.Problem = problems.From(err)
+
+	json, err := json.Marshal(Problem{
+		Type:          problem.Type.URI,
+		Title:         problem.Type.Title,
+		Status:        problem.Type.Status,
+		Detail:        problem.Detail,
+		Instance:      r.URL.Path,
+		RequestId:     access.RequestId(r),
+		InvalidParams: problem.InvalidParams,
+	})
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("Content-Language", "en")
+	w.WriteHeader(problem.Type.Status)
+	w.Write(json)
+}
@@ -2,27 +2,22 @@ package responses
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 )
 
 type Status struct {
-  Status   int    `json:"status"`
-	Message string `json:"message,omitempty"`
-	Data    any    `json:"data,omitempty"`
-	Type     string `json:"type,omitempty"`
-	Title    string `json:"title,omitempty"`
-	Detail   string `json:"detail,omitempty"`
-	Instance string `json:"instance,omitempty"`
+	Status int `json:"status"`
+	Data   any `json:"data,omitempty"`
 }
 
 // Takes a built Status struct and converts it into JSON-compatible bytes
 // using the "encoding/json" library then sends to client through provided
-// ResponseWriter.
+// ResponseWriter. Data that cannot be marshalled is a bug, so it panics, which
+// middleware.Recover turns into an internal problem.
 func (status Status) ToClient(w http.ResponseWriter) {
 	json, err := json.Marshal(status)
 	if err != nil {
-		log.Fatalf("ERR : %v", err)
+		panic(err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
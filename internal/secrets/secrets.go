@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hashes a secret, such as a password or profile PIN, so that it can be stored
+// in the database without being recoverable.
+func Hash(secret string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Reports whether the secret matches the stored hash. An empty hash never
+// matches anything.
+func Verify(hash string, secret string) bool {
+	if hash == "" {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(secret)) == nil
+}
+
+// Returns a new random token with 256 bits of entropy, encoded so that it can
+// be used in cookies and headers as is.
+func Token() (string, error) {
+	var buffer []byte = make([]byte, 32)
+
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// Returns the SHA-256 digest of a token as hex. Tokens are random enough that a
+// fast digest is sufficient, and it allows looking tokens up by their digest.
+func Digest(token string) string {
+	var sum [32]byte = sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
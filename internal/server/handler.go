@@ -0,0 +1,75 @@
+package server
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/andrewdotjs/watchify-server/internal/handlers"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/middleware"
+	"github.com/andrewdotjs/watchify-server/internal/oidc"
+	"github.com/andrewdotjs/watchify-server/internal/openapi"
+	"github.com/andrewdotjs/watchify-server/internal/recommend"
+	"github.com/andrewdotjs/watchify-server/internal/suggest"
+)
+
+// Returns the handler serving every route of handlers.go over db, behind the
+// middleware. Routes are checked against the OpenAPI document when validation
+// is "log", and divergences are also rejected when it is "strict".
+func Handler(
+  db *sql.DB,
+  appDirectory *string,
+  index *suggest.Index,
+  engine *recommend.Engine,
+  provider *oidc.Provider,
+  validation string,
+  log *logger.Logger,
+  functionId *string,
+) http.Handler {
+	mux := http.NewServeMux()
+
+	handlers.Shows(mux, db, appDirectory, index, log)
+	handlers.Movies(mux, db, appDirectory, index, log)
+	handlers.Stream(mux, db, appDirectory, log)
+	handlers.Videos(mux, db, appDirectory, log)
+	handlers.Tags(mux, db, index, log)
+	handlers.People(mux, db, appDirectory, index, log)
+	handlers.ContentRatings(mux, db, index, log)
+	handlers.Profiles(mux, db, appDirectory, log)
+	handlers.Auth(mux, db, log)
+	handlers.Users(mux, db, appDirectory, log)
+	handlers.Tokens(mux, db, log)
+	handlers.SSO(mux, db, provider, log)
+	handlers.Settings(mux, db, log)
+	handlers.Progress(mux, db, log)
+	handlers.Home(mux, db, log)
+	handlers.Lists(mux, db, log)
+	handlers.Reviews(mux, db, log)
+	handlers.Suggestions(mux, index, log)
+	handlers.Batch(mux, db, appDirectory, index, log)
+	handlers.GraphQL(mux, db, log)
+	handlers.Recommendations(mux, db, engine, log)
+
+	// Every route above is described by the OpenAPI document.
+	document := openapi.New()
+	handlers.OpenAPI(mux, document, log)
+
+	// Middleware
+	var handler http.Handler = mux
+
+	if validation == "log" || validation == "strict" {
+		handler = middleware.Validate(mux, document, validation == "strict", log, functionId)
+		log.Info(*functionId, fmt.Sprintf("OpenAPI validation enabled in %s mode", validation))
+	}
+
+	handler = middleware.Idempotency(handler, db, log)
+	handler = middleware.LogEndpoint(handler, log)
+	handler = middleware.Profile(handler, db, log)
+	handler = middleware.Authenticate(handler, db, log)
+	handler = middleware.CORS(handler)
+	handler = middleware.Recover(handler, log)
+	handler = middleware.RequestId(handler)
+
+	return handler
+}
@@ -0,0 +1,327 @@
+// Package servertest runs the whole API over an in-memory database, so that
+// tests can send it requests the way clients do. Every server validates routes
+// strictly against the OpenAPI document, so any test also fails when a route
+// diverges from it.
+package servertest
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/database"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/oidc"
+	"github.com/andrewdotjs/watchify-server/internal/recommend"
+	"github.com/andrewdotjs/watchify-server/internal/server"
+	"github.com/andrewdotjs/watchify-server/internal/suggest"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Username and password of the admin that Admin sets the server up with.
+const (
+	AdminUsername string = "admin"
+	AdminPassword string = "password123"
+)
+
+// A running server along with its database and storage.
+type Server struct {
+	*httptest.Server
+	DB           *sql.DB
+	AppDirectory string
+	Log          *logger.Logger
+}
+
+// Starts a server without single sign-on. It is closed at the end of the test.
+func New(t testing.TB) *Server {
+	return NewWithProvider(t, nil)
+}
+
+// Starts a server that logs users in through provider. It is closed at the end
+// of the test.
+func NewWithProvider(t testing.TB, provider *oidc.Provider) *Server {
+	t.Helper()
+
+	var log *logger.Logger = &logger.Logger{}
+	var functionId string = "servertest"
+	var appDirectory string = t.TempDir()
+
+	for _, directory := range []string{"db", "storage/covers", "storage/videos"} {
+		if err := os.MkdirAll(path.Join(appDirectory, directory), 0770); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db, err := database.Open(log, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := suggest.New(db)
+	index.Refresh(log)
+
+	handler := server.Handler(db, &appDirectory, index, recommend.New(db), provider, "strict", log, &functionId)
+
+	testServer := &Server{
+		Server:       httptest.NewServer(handler),
+		DB:           db,
+		AppDirectory: appDirectory,
+		Log:          log,
+	}
+
+	t.Cleanup(func() {
+		testServer.Close()
+		db.Close()
+	})
+
+	return testServer
+}
+
+// Returns a client that keeps the cookies it is sent, like a browser, but does
+// not follow redirects.
+func (server *Server) Client(t testing.TB) *Client {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Client{
+		t:      t,
+		server: server,
+		Header: http.Header{},
+		client: &http.Client{
+			Jar: jar,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Sets the server up and returns a client logged in as its first admin.
+func (server *Server) Admin(t testing.TB) *Client {
+	t.Helper()
+
+	client := server.Client(t)
+	client.Form("POST", "/api/v1/setup", url.Values{
+		"username": {AdminUsername},
+		"password": {AdminPassword},
+	}).Expect(201)
+
+	return client
+}
+
+// Sends requests to a server on behalf of a test.
+type Client struct {
+	t      testing.TB
+	server *Server
+	client *http.Client
+
+	// Headers sent along with every request, such as Authorization.
+	Header http.Header
+}
+
+// A file of a multipart form.
+type File struct {
+	Field   string
+	Name    string
+	Content []byte
+}
+
+// Sends a request without a body. Headers are given as name, value pairs.
+func (client *Client) Send(method string, target string, header ...string) *Response {
+	request := client.request(method, target, nil, header)
+	return client.Do(request)
+}
+
+// Sends values as a multipart form.
+func (client *Client) Form(method string, target string, values url.Values, header ...string) *Response {
+	return client.Upload(method, target, values, nil, header...)
+}
+
+// Sends values and files as a multipart form.
+func (client *Client) Upload(method string, target string, values url.Values, files []File, header ...string) *Response {
+	client.t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for name, fieldValues := range values {
+		for _, value := range fieldValues {
+			writer.WriteField(name, value)
+		}
+	}
+
+	for _, file := range files {
+		part, err := writer.CreateFormFile(file.Field, file.Name)
+		if err != nil {
+			client.t.Fatal(err)
+		}
+		part.Write(file.Content)
+	}
+
+	writer.Close()
+
+	request := client.request(method, target, &body, header)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return client.Do(request)
+}
+
+// Sends value encoded as JSON.
+func (client *Client) JSON(method string, target string, value any, header ...string) *Response {
+	client.t.Helper()
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		client.t.Fatal(err)
+	}
+
+	request := client.request(method, target, bytes.NewReader(body), header)
+	if request.Header.Get("Content-Type") == "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	return client.Do(request)
+}
+
+// Sends a request and reads the whole response.
+func (client *Client) Do(request *http.Request) *Response {
+	client.t.Helper()
+
+	for name, values := range client.Header {
+		if request.Header.Get(name) == "" {
+			request.Header[name] = values
+		}
+	}
+
+	response, err := client.client.Do(request)
+	if err != nil {
+		client.t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		client.t.Fatal(err)
+	}
+
+	return &Response{
+		t:       client.t,
+		request: request.Method + " " + request.URL.Path,
+		Status:  response.StatusCode,
+		Header:  response.Header,
+		Body:    body,
+	}
+}
+
+// Uploads a movie with a placeholder video and cover. Returns its id.
+func (client *Client) CreateMovie(title string) string {
+	client.t.Helper()
+
+	return client.Upload("POST", "/api/v1/movies", url.Values{
+		"title":       {title},
+		"description": {"Description of " + title + "."},
+	}, []File{
+		{Field: "video", Name: "movie.mp4", Content: []byte("video")},
+		{Field: "cover", Name: "cover.jpg", Content: []byte("cover")},
+	}).Expect(201).String("id")
+}
+
+// Uploads a show with a placeholder episode and cover. Returns its id.
+func (client *Client) CreateShow(title string) string {
+	client.t.Helper()
+
+	return client.Upload("POST", "/api/v1/shows", url.Values{
+		"title":       {title},
+		"description": {"Description of " + title + "."},
+	}, []File{
+		{Field: "videos", Name: "episode.mp4", Content: []byte("episode")},
+		{Field: "cover", Name: "cover.jpg", Content: []byte("cover")},
+	}).Expect(201).String("id")
+}
+
+func (client *Client) request(method string, target string, body io.Reader, header []string) *http.Request {
+	client.t.Helper()
+
+	if !strings.HasPrefix(target, "http") {
+		target = client.server.URL + target
+	}
+
+	request, err := http.NewRequest(method, target, body)
+	if err != nil {
+		client.t.Fatal(err)
+	}
+
+	for index := 0; index+1 < len(header); index += 2 {
+		request.Header.Set(header[index], header[index+1])
+	}
+
+	return request
+}
+
+// A response read in full.
+type Response struct {
+	t       testing.TB
+	request string
+
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Fails the test unless the response has the given status.
+func (response *Response) Expect(status int) *Response {
+	response.t.Helper()
+
+	if response.Status != status {
+		response.t.Fatalf("%s answered %d rather than %d: %s", response.request, response.Status, status, response.Body)
+	}
+
+	return response
+}
+
+// Returns the response decoded as JSON.
+func (response *Response) JSON() map[string]any {
+	response.t.Helper()
+
+	var value map[string]any
+	if err := json.Unmarshal(response.Body, &value); err != nil {
+		response.t.Fatalf("%s answered with something other than a JSON object: %s", response.request, response.Body)
+	}
+
+	return value
+}
+
+// Returns the data of the status envelope.
+func (response *Response) Data() map[string]any {
+	response.t.Helper()
+
+	data, _ := response.JSON()["data"].(map[string]any)
+	return data
+}
+
+// Returns a string member of the data of the status envelope.
+func (response *Response) String(name string) string {
+	response.t.Helper()
+
+	value, _ := response.Data()[name].(string)
+	return value
+}
+
+// Returns the type of the problem the response carries.
+func (response *Response) Problem() string {
+	response.t.Helper()
+
+	kind, _ := response.JSON()["type"].(string)
+	return kind
+}
@@ -0,0 +1,215 @@
+package suggest
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/types"
+	"github.com/google/uuid"
+)
+
+// Order suggestions of the same quality are returned in.
+var typeOrder map[string]int = map[string]int{"show": 0, "movie": 1, "person": 2, "tag": 3}
+
+// A show, movie, person or tag that can be suggested, along with the words of
+// its text.
+type item struct {
+	suggestion types.Suggestion
+	words      []string
+	rank       int // rank of the content rating of shows and movies, -1 if unrated
+}
+
+// A word of an item, which the index is sorted by.
+type entry struct {
+	word string
+	item int
+}
+
+// Keeps the titles, names of people and names of tags of the library in memory,
+// sorted by every word they contain, so that they can be matched by prefix
+// without querying the database.
+type Index struct {
+	database *sql.DB
+
+	// Held by Refresh from the first query until the swap, so that refreshes
+	// run one at a time and a slow one can't install a snapshot older than
+	// the one a later refresh already installed.
+	refreshing sync.Mutex
+
+	mutex   sync.RWMutex
+	items   []item
+	entries []entry
+}
+
+// Returns an empty index for the database. Nothing is suggested until Refresh
+// has run at least once.
+func New(database *sql.DB) *Index {
+	return &Index{database: database}
+}
+
+// Rebuilds the index from the database. Meant to be called after every change
+// to the shows, movies, people or tags of the library. Failures are logged and
+// leave the previous index in place. Concurrent calls wait for each other, so
+// the index reflects every change made before the last call started.
+func (index *Index) Refresh(log *logger.Logger) {
+	var items []item
+	var entries []entry
+
+	index.refreshing.Lock()
+	defer index.refreshing.Unlock()
+
+	for _, source := range []struct {
+		kind  string
+		url   string
+		query string
+	}{
+		{"show", "/api/v1/shows/", "SELECT id, title, COALESCE((SELECT rank FROM content_ratings WHERE id = shows.content_rating_id), -1) FROM shows WHERE hidden = 0"},
+		{"movie", "/api/v1/movies/", "SELECT id, title, COALESCE((SELECT rank FROM content_ratings WHERE id = movies.content_rating_id), -1) FROM movies WHERE hidden = 0"},
+		{"person", "/api/v1/people/", "SELECT id, name, -1 FROM people"},
+		{"tag", "/api/v1/tags/", "SELECT id, name, -1 FROM tags"},
+	} {
+		rows, err := index.database.Query(source.query)
+		if err != nil {
+			log.Error(uuid.NewString(), fmt.Sprintf("Failed to refresh suggestions. %v", err))
+			return
+		}
+
+		for rows.Next() {
+			var suggestion types.Suggestion = types.Suggestion{Type: source.kind}
+			var rank int
+
+			if err := rows.Scan(&suggestion.Id, &suggestion.Text, &rank); err != nil {
+				rows.Close()
+				log.Error(uuid.NewString(), fmt.Sprintf("Failed to refresh suggestions. %v", err))
+				return
+			}
+
+			suggestion.Url = source.url + suggestion.Id
+			words := normalize(suggestion.Text)
+
+			for _, word := range words {
+				entries = append(entries, entry{word: word, item: len(items)})
+			}
+
+			items = append(items, item{suggestion: suggestion, words: words, rank: rank})
+		}
+
+		rows.Close()
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].word < entries[j].word
+	})
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	index.items = items
+	index.entries = entries
+}
+
+// Returns up to limit items whose words start with every word of the query,
+// best match first. Items whose text starts with the query come first. Shows
+// and movies rated above maxRank are left out, along with unrated ones, unless
+// maxRank is negative.
+func (index *Index) Lookup(query string, maxRank int, limit int) []types.Suggestion {
+	var words []string = normalize(query)
+	var matches []item
+	var seen map[int]bool = map[int]bool{}
+	var suggestions []types.Suggestion = []types.Suggestion{}
+
+	if len(words) == 0 {
+		return suggestions
+	}
+
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+
+	// Only items with a word starting with the first word of the query can match,
+	// and those words sit next to each other in the index.
+	start := sort.Search(len(index.entries), func(i int) bool {
+		return index.entries[i].word >= words[0]
+	})
+
+	for _, entry := range index.entries[start:] {
+		if !strings.HasPrefix(entry.word, words[0]) {
+			break
+		}
+
+		if seen[entry.item] {
+			continue
+		}
+
+		seen[entry.item] = true
+		candidate := index.items[entry.item]
+
+		if maxRank >= 0 && (candidate.suggestion.Type == "show" || candidate.suggestion.Type == "movie") &&
+			(candidate.rank < 0 || candidate.rank > maxRank) {
+			continue
+		}
+
+		if matchesAll(candidate.words, words[1:]) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	prefix := strings.Join(words, " ")
+	sort.SliceStable(matches, func(i, j int) bool {
+		first, second := matches[i], matches[j]
+		firstLeads := strings.HasPrefix(strings.Join(first.words, " "), prefix)
+		secondLeads := strings.HasPrefix(strings.Join(second.words, " "), prefix)
+
+		switch {
+		case firstLeads != secondLeads:
+			return firstLeads
+		case typeOrder[first.suggestion.Type] != typeOrder[second.suggestion.Type]:
+			return typeOrder[first.suggestion.Type] < typeOrder[second.suggestion.Type]
+		case len(first.suggestion.Text) != len(second.suggestion.Text):
+			return len(first.suggestion.Text) < len(second.suggestion.Text)
+		default:
+			return strings.ToLower(first.suggestion.Text) < strings.ToLower(second.suggestion.Text)
+		}
+	})
+
+	for _, match := range matches {
+		if len(suggestions) == limit {
+			break
+		}
+
+		suggestions = append(suggestions, match.suggestion)
+	}
+
+	return suggestions
+}
+
+// Reports whether every prefix starts one of words.
+func matchesAll(words []string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		var found bool = false
+
+		for _, word := range words {
+			if strings.HasPrefix(word, prefix) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Splits text into lower case words of letters and digits.
+func normalize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(character rune) bool {
+		return !unicode.IsLetter(character) && !unicode.IsDigit(character)
+	})
+}
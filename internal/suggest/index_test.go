@@ -0,0 +1,62 @@
+package suggest_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/andrewdotjs/watchify-server/internal/database"
+	"github.com/andrewdotjs/watchify-server/internal/logger"
+	"github.com/andrewdotjs/watchify-server/internal/suggest"
+	"github.com/google/uuid"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestConcurrentRefresh(t *testing.T) {
+	var log logger.Logger
+	var waitGroup sync.WaitGroup
+
+	db, err := database.Open(&log, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	index := suggest.New(db)
+
+	// A larger library makes every refresh slow enough to overlap.
+	if _, err := db.Exec(`
+		WITH RECURSIVE numbers (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM numbers WHERE n < 5000)
+		INSERT INTO people (id, name, biography, upload_date, last_modified)
+		SELECT 'person-' || n, 'Person ' || n, '', '', '' FROM numbers
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every change is followed by a refresh, like the handlers do, while the
+	// refreshes of earlier changes may still be running.
+	for i := range 20 {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			if _, err := db.Exec(
+				"INSERT INTO tags (id, name, kind, upload_date, last_modified) VALUES (?, ?, 'genre', '', '')",
+				uuid.NewString(),
+				fmt.Sprintf("Genre %d", i),
+			); err != nil {
+				t.Error(err)
+			}
+
+			index.Refresh(&log)
+		}()
+	}
+
+	waitGroup.Wait()
+
+	if suggestions := index.Lookup("genre", -1, 50); len(suggestions) != 20 {
+		t.Errorf("the index holds %d of the 20 tags", len(suggestions))
+	}
+}
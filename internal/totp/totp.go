@@ -0,0 +1,111 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Length of a time step in seconds, and number of digits of a code. These are
+// the defaults of RFC 6238, which every authenticator app supports.
+const (
+	period int64 = 30
+	digits int   = 6
+)
+
+// How many time steps before and after the current one are accepted, to allow
+// for clock drift and slow typing.
+const skew int64 = 1
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Returns a new random secret, encoded in base32 as authenticator apps expect.
+func Secret() (string, error) {
+	var buffer []byte = make([]byte, 20)
+
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(buffer), nil
+}
+
+// Returns the otpauth URI that authenticator apps read from a QR code to enroll
+// the secret.
+func ProvisioningUri(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(period))
+
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + query.Encode()
+}
+
+// Returns the code of the secret for the given time step, as described in
+// RFC 4226 section 5.3.
+func Code(secret string, counter int64) (string, error) {
+	var message [8]byte
+
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	binary.BigEndian.PutUint64(message[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range digits {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulo), nil
+}
+
+// Returns the time step that the moment falls in.
+func Counter(moment time.Time) int64 {
+	return moment.Unix() / period
+}
+
+// Checks a code against the secret at the given moment. Codes of time steps up
+// to and including lastCounter are rejected, so that every code can only be used
+// once. Returns the time step the code belongs to when it is valid.
+func Verify(secret string, code string, moment time.Time, lastCounter int64) (int64, bool) {
+	var current int64 = Counter(moment)
+
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != digits {
+		return 0, false
+	}
+
+	for counter := current - skew; counter <= current+skew; counter++ {
+		if counter <= lastCounter {
+			continue
+		}
+
+		expected, err := Code(secret, counter)
+		if err != nil {
+			return 0, false
+		}
+
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
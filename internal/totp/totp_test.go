@@ -0,0 +1,97 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// The ASCII secret "12345678901234567890" of RFC 6238 Appendix B, in base32.
+const rfcSecret string = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// The SHA1 test vectors of RFC 6238 Appendix B, which lists 8 digit codes,
+	// of which the last 6 are the 6 digit code.
+	for _, vector := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	} {
+		code, err := Code(rfcSecret, Counter(time.Unix(vector.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := vector.code[8-digits:]; code != want {
+			t.Errorf("the code at %d is %s, want %s", vector.unix, code, want)
+		}
+	}
+}
+
+func TestVerifySkew(t *testing.T) {
+	var moment time.Time = time.Unix(1111111111, 0)
+	var current int64 = Counter(moment)
+
+	for offset := int64(-3); offset <= 3; offset++ {
+		code, err := Code(rfcSecret, current+offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counter, valid := Verify(rfcSecret, code, moment, 0)
+		if want := offset >= -skew && offset <= skew; valid != want {
+			t.Errorf("the code %d steps away was accepted: %t, want %t", offset, valid, want)
+		}
+
+		if valid && counter != current+offset {
+			t.Errorf("the code %d steps away belongs to step %d, want %d", offset, counter, current+offset)
+		}
+	}
+}
+
+func TestVerifyReuse(t *testing.T) {
+	var moment time.Time = time.Unix(1111111111, 0)
+	var current int64 = Counter(moment)
+
+	code, err := Code(rfcSecret, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counter, valid := Verify(rfcSecret, code, moment, 0)
+	if !valid {
+		t.Fatal("the current code was rejected")
+	}
+
+	// Once a step was used, its code and those of earlier steps are rejected.
+	if _, valid := Verify(rfcSecret, code, moment, counter); valid {
+		t.Error("the code was accepted twice")
+	}
+
+	previous, err := Code(rfcSecret, current-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, valid := Verify(rfcSecret, previous, moment, counter); valid {
+		t.Error("the code of an earlier step was accepted after a later one was used")
+	}
+
+	next, err := Code(rfcSecret, current+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, valid := Verify(rfcSecret, next, moment, counter); !valid {
+		t.Error("the code of the next step was rejected")
+	}
+
+	if _, valid := Verify(rfcSecret, "12345", moment, 0); valid {
+		t.Error("a code that is too short was accepted")
+	}
+}
@@ -0,0 +1,14 @@
+package types
+
+type ApiToken struct {
+	Id string `json:"id"` // uuid of the token
+
+	Name         string `json:"name,omitempty"`           // what the token is used for
+	Scope        string `json:"scope,omitempty"`          // "read", "upload" or "admin"
+	Token        string `json:"token,omitempty"`          // the token itself, only returned once on creation
+	LastUsedDate string `json:"last_used_date,omitempty"` // when the token was last used, if ever
+	ExpiryDate   string `json:"expiry_date,omitempty"`    // when the token stops working, if ever
+
+	// General data that is useful for debugging.
+	UploadDate string `json:"upload_date,omitempty"`
+}
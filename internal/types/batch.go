@@ -0,0 +1,29 @@
+package types
+
+// An operation of a batch.
+type BatchOperation struct {
+	Op     string `json:"op"`                // "hide", "unhide", "tag", "delete" or "move"
+	Id     string `json:"id"`                // uuid of the show or movie, or of the episode to move
+	TagId  string `json:"tag_id,omitempty"`  // uuid of the tag to attach, for "tag"
+	ShowId string `json:"show_id,omitempty"` // uuid of the series to move the episode to, for "move"
+
+	IfMatch string `json:"if_match,omitempty"` // ETag the show, movie or episode must still match, or *
+}
+
+// The outcome of a batch of operations, which were committed together or not
+// at all.
+type Batch struct {
+	Atomic    bool          `json:"atomic"`    // whether a single failure rolls back every operation
+	Committed bool          `json:"committed"` // whether the successful operations were kept
+	Results   []BatchResult `json:"results"`   // outcome of every operation, in order
+}
+
+// The outcome of an operation of a batch. Failures carry the type and detail
+// of the problem, as the operation would have on its own.
+type BatchResult struct {
+	Op     string `json:"op"`
+	Id     string `json:"id"`
+	Status int    `json:"status"`
+	Type   string `json:"type,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
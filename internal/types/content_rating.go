@@ -0,0 +1,10 @@
+package types
+
+type ContentRating struct {
+	Id string `json:"id"` // uuid of the content rating
+
+	System      string `json:"system,omitempty"`      // rating system, such as "MPAA", "TV" or "BBFC"
+	Code        string `json:"code,omitempty"`        // rating within the system, such as "PG-13"
+	Rank        int    `json:"rank"`                  // position on the shared scale, higher is more mature
+	Description string `json:"description,omitempty"` // short explanation of the rating
+}
@@ -0,0 +1,19 @@
+package types
+
+type Credit struct {
+	Id string `json:"id"` // uuid of the credit
+
+	PersonId   string `json:"person_id,omitempty"`
+	Name       string `json:"name,omitempty"`        // name of the person, set when embedded in content
+	ParentId   string `json:"parent_id,omitempty"`   // uuid of the movie, show or episode
+	ParentType string `json:"parent_type,omitempty"` // either "movie", "show" or "episode"
+	Title      string `json:"title,omitempty"`       // title of the content, set within filmographies
+
+	Role      string `json:"role,omitempty"`      // either "actor", "director" or "writer"
+	Character string `json:"character,omitempty"` // name of the played character, actors only
+	Position  int    `json:"position"`            // billing order within the content
+
+	// Version of the credit, which If-Match accepts as the ETag "<version>" to
+	// change it, since credits have no representation of their own.
+	Version int64 `json:"version,omitempty"`
+}
@@ -5,10 +5,16 @@ type Episode struct {
 	Id       string `json:"id"`
 	ParentId string `json:"series_id,omitempty"`
 
+	SeasonNumber  int    `json:"season_number,omitempty"`
 	EpisodeNumber int    `json:"episode_number,omitempty"`
 	Title         string `json:"title,omitempty"`
 	Description   string `json:"description,omitempty"`
 
+	Credits         []Credit       `json:"credits,omitempty"`
+	ContentRating   *ContentRating `json:"content_rating,omitempty"`
+	ContentRatingId string         `json:"-"`
+	Progress        *Progress      `json:"progress,omitempty"`
+
 	// Urls for easier app navigation
 	NextEpisode     map[string]string `json:"next_episode,omitempty"`
 	PreviousEpisode map[string]string `json:"previous_episode,omitempty"`
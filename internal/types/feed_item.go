@@ -0,0 +1,9 @@
+package types
+
+type FeedItem struct {
+	MediaType string    `json:"media_type"`         // "movie" or "episode"
+	Movie     *Movie    `json:"movie,omitempty"`    // the movie, for movie items
+	Episode   *Episode  `json:"episode,omitempty"`  // the episode, for episode items
+	Show      *Show     `json:"show,omitempty"`     // show of the episode, for episode items
+	Progress  *Progress `json:"progress,omitempty"` // progress of the viewer on the item
+}
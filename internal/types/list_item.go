@@ -0,0 +1,9 @@
+package types
+
+type ListItem struct {
+	MediaType string `json:"media_type"` // "show" or "movie"
+	Position  int    `json:"position"`   // place on the list, starting at 1
+	AddedDate string `json:"added_date"` // date the item was added to the list
+	Show      *Show  `json:"show,omitempty"`
+	Movie     *Movie `json:"movie,omitempty"`
+}
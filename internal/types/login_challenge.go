@@ -0,0 +1,7 @@
+package types
+
+type LoginChallenge struct {
+	TwoFactorRequired bool   `json:"two_factor_required"` // always true, tells clients to ask for a code
+	Challenge         string `json:"challenge"`           // token to send along with the code
+	ExpiryDate        string `json:"expiry_date"`         // when the challenge stops working
+}
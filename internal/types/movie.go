@@ -3,9 +3,17 @@ package types
 type Movie struct {
 	Id string `json:"id"` // uuid of the movie
 
-	Title       string `json:"title,omitempty"`       // title of the movie
-	Description string `json:"description,omitempty"` // description of the movie
-	Hidden      bool   `json:"hidden,omitempty"`
+	Title           string         `json:"title,omitempty"`       // title of the movie
+	Description     string         `json:"description,omitempty"` // description of the movie
+	Hidden          bool           `json:"hidden,omitempty"`
+	Tags            []Tag          `json:"tags,omitempty"`
+	Credits         []Credit       `json:"credits,omitempty"`
+	ContentRating   *ContentRating `json:"content_rating,omitempty"`
+	ContentRatingId string         `json:"-"`
+	Progress        *Progress      `json:"progress,omitempty"`
+	Rating          *RatingSummary `json:"rating,omitempty"`
+	InWatchlist     bool           `json:"in_watchlist"`
+	Favorite        bool           `json:"favorite"`
 
 	Cover map[string]any `json:"cover,omitempty"`
 	// 	EXAMPLE:
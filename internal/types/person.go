@@ -0,0 +1,21 @@
+package types
+
+type Person struct {
+	Id string `json:"id"` // uuid of the person
+
+	Name      string `json:"name,omitempty"`      // full name of the person
+	Biography string `json:"biography,omitempty"` // short biography of the person
+
+	Headshot map[string]any `json:"headshot,omitempty"`
+	// 	EXAMPLE:
+	//  "headshot": {
+	//		"exists": true,
+	//		"url": "example.com/api/v1/people/{person_id}/headshot"
+	//  }
+
+	Filmography []Credit `json:"filmography,omitempty"` // credits within the library
+
+	// General data that is useful for debugging.
+	UploadDate   string `json:"upload_date,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
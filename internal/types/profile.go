@@ -0,0 +1,18 @@
+package types
+
+type Profile struct {
+	Id     string `json:"id"` // uuid of the profile
+	UserId string `json:"-"`  // uuid of the user the profile belongs to
+
+	Name             string         `json:"name,omitempty"`               // display name of the profile
+	MaxContentRating *ContentRating `json:"max_content_rating,omitempty"` // most mature rating the profile may watch
+	AudioLanguage    string         `json:"audio_language"`               // preferred audio language, as a BCP 47 tag
+	SubtitleLanguage string         `json:"subtitle_language"`            // preferred subtitle language, as a BCP 47 tag
+	HasPin           bool           `json:"has_pin"`                      // whether selecting and changing it requires a PIN
+	Selected         bool           `json:"selected"`                     // whether the request is scoped to the profile
+	PinHash          string         `json:"-"`
+
+	// General data that is useful for debugging.
+	UploadDate   string `json:"upload_date,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
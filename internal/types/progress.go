@@ -0,0 +1,10 @@
+package types
+
+type Progress struct {
+	MediaId         string  `json:"media_id"`          // uuid of the movie or episode
+	MediaType       string  `json:"media_type"`        // "movie" or "episode"
+	Position        float64 `json:"position"`          // where playback should resume, in seconds
+	Duration        float64 `json:"duration"`          // length of the video as reported by the client, in seconds
+	Watched         bool    `json:"watched"`           // whether the video was watched to the end
+	LastWatchedDate string  `json:"last_watched_date"` // when progress was last reported
+}
@@ -0,0 +1,8 @@
+package types
+
+type Recommendation struct {
+	MediaType string  `json:"media_type"` // "show" or "movie"
+	Score     float64 `json:"score"`      // how well it matches, 0 for popular fill-ins
+	Show      *Show   `json:"show,omitempty"`
+	Movie     *Movie  `json:"movie,omitempty"`
+}
@@ -0,0 +1,19 @@
+package types
+
+type RatingSummary struct {
+	Average float64 `json:"average"`         // average score out of 10
+	Count   int     `json:"count"`           // number of scores the average is of
+	Score   int     `json:"score,omitempty"` // score the viewer gave, if any
+}
+
+type Review struct {
+	Id           string `json:"id"`
+	MediaId      string `json:"media_id"`
+	MediaType    string `json:"media_type"` // "movie", "show" or "episode"
+	Author       string `json:"author"`     // name of the profile or user that wrote it
+	Score        int    `json:"score"`      // score out of 10
+	Review       string `json:"review,omitempty"`
+	Hidden       bool   `json:"hidden,omitempty"` // hidden by a moderator
+	CreatedDate  string `json:"created_date"`
+	LastModified string `json:"last_modified"`
+}
@@ -0,0 +1,6 @@
+package types
+
+type Role struct {
+	Name        string   `json:"name"`        // name of the role, as stored on users
+	Permissions []string `json:"permissions"` // permissions granted by the role
+}
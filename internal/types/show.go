@@ -4,10 +4,17 @@ type Show struct {
 	Id      string `json:"id"` // uuid of the series
 	CoverId string `json:"cover_id"`
 
-	Title        string `json:"title,omitempty"`       // title of the series
-	Description  string `json:"description,omitempty"` // description of the series
-	EpisodeCount int    `json:"-"`                     // episode count of the series
-	Hidden       bool   `json:"hidden"`
+	Title           string         `json:"title,omitempty"`       // title of the series
+	Description     string         `json:"description,omitempty"` // description of the series
+	EpisodeCount    int            `json:"-"`                     // episode count of the series
+	Hidden          bool           `json:"hidden"`
+	Tags            []Tag          `json:"tags,omitempty"`
+	Credits         []Credit       `json:"credits,omitempty"`
+	ContentRating   *ContentRating `json:"content_rating,omitempty"`
+	ContentRatingId string         `json:"-"`
+	Rating          *RatingSummary `json:"rating,omitempty"`
+	InWatchlist     bool           `json:"in_watchlist"`
+	Favorite        bool           `json:"favorite"`
 
 	Episodes map[string]any `json:"episodes,omitempty"`
 	// 	EXAMPLE:
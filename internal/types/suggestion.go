@@ -0,0 +1,8 @@
+package types
+
+type Suggestion struct {
+	Id   string `json:"id"`
+	Type string `json:"type"` // "show", "movie", "person" or "tag"
+	Text string `json:"text"` // title of a show or movie, name of a person or tag
+	Url  string `json:"url"`
+}
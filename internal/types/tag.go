@@ -0,0 +1,13 @@
+package types
+
+type Tag struct {
+	Id   string `json:"id"`             // uuid of the tag
+	Name string `json:"name,omitempty"` // display name of the tag, unique regardless of case
+	Kind string `json:"kind,omitempty"` // either "genre" or "tag"
+
+	Count *int `json:"count,omitempty"` // amount of tagged content, only set on facets
+
+	// General data that is useful for debugging.
+	UploadDate   string `json:"upload_date,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
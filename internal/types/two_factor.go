@@ -0,0 +1,6 @@
+package types
+
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`           // base32 secret, for entering it by hand
+	ProvisioningUri string `json:"provisioning_uri"` // otpauth URI, for showing it as a QR code
+}
@@ -0,0 +1,17 @@
+package types
+
+type User struct {
+	Id string `json:"id"` // uuid of the user
+
+	Username     string `json:"username,omitempty"` // unique name used to log in
+	Role         string `json:"role,omitempty"`     // "admin", "uploader", "viewer" or "guest"
+	PasswordHash string `json:"-"`
+
+	TwoFactorEnabled bool   `json:"two_factor_enabled"` // whether logging in requires a TOTP code
+	TotpSecret       string `json:"-"`
+	TotpCounter      int64  `json:"-"` // time step of the last accepted code
+
+	// General data that is useful for debugging.
+	UploadDate   string `json:"upload_date,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
@@ -0,0 +1,9 @@
+package types
+
+type WatchHistoryEntry struct {
+	MediaId         string `json:"media_id"`          // uuid of the movie or episode
+	MediaType       string `json:"media_type"`        // "movie" or "episode"
+	Title           string `json:"title"`             // title of the movie or episode
+	PlayCount       int    `json:"play_count"`        // number of times playback was started
+	LastWatchedDate string `json:"last_watched_date"` // when playback was last started
+}
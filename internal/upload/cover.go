@@ -11,14 +11,14 @@ import (
 	"time"
 
 	"github.com/andrewdotjs/watchify-server/internal/logger"
-	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
 	"github.com/andrewdotjs/watchify-server/internal/types"
 	"github.com/google/uuid"
 )
 
 // This takes a movie cover file and stores it in the file system and database.
 //
-// This function returns an error response that needs to be sent to the client.
+// This function returns the problem to send to the client if the upload fails.
 func Cover(
   uploadedFile *multipart.FileHeader,
   cover *types.Cover,
@@ -26,7 +26,7 @@ func Cover(
   uploadDirectory *string,
   log *logger.Logger,
   functionId *string,
-) *responses.Error {
+) error {
 	var id string = uuid.NewString()
 	var uploadPath string
 
@@ -41,31 +41,15 @@ func Cover(
 	// Create the upload directory if it doesn't exist
 	if _, err := os.Stat(*uploadDirectory); err != nil {
 		if !os.IsNotExist(err) {
-			var errorResponse responses.Error = responses.Error{
-				Type:   "null",
-				Title:  "Failure to upload movie cover",
-				Status: 500,
-				Detail: "An interesting error occurred while checking if upload directory exists.",
-				// Add URL endpoint as instance.
-			}
-
 			log.Error(*functionId, fmt.Sprintf("An unknown error occurred during verification. %v", err))
-			return &errorResponse
+			return problems.Wrap(problems.UploadFailed, err, "An interesting error occurred while checking if upload directory exists.")
 		}
 
 		log.Info(*functionId, fmt.Sprintf("Could not find upload directory at %s. Creating one", *uploadDirectory))
 
 		if err := os.Mkdir(*uploadDirectory, os.ModePerm); err != nil {
-			var errorResponse responses.Error = responses.Error{
-				Type:   "null",
-				Title:  "Failure to upload movie cover",
-				Status: 500,
-				Detail: "Failed to create upload directory when needed.",
-				// Add URL endpoint as instance.
-			}
-
 			log.Error(*functionId, fmt.Sprintf("Failed to create upload directory when needed. %v", err))
-			return &errorResponse
+			return problems.Wrap(problems.UploadFailed, err, "Failed to create upload directory when needed.")
 		}
 	}
 
@@ -74,7 +58,7 @@ func Cover(
 	// Insert the new cover's data into the series_covers table/
 	if _, err := database.Exec(`
 	  INSERT INTO
-			covers
+			covers (id, parent_id, file_extension, file_name, upload_date)
 		VALUES
 		  (?, ?, ?, ?, ?)
 		`,
@@ -84,16 +68,8 @@ func Cover(
 		cover.FileName,
 		cover.UploadDate,
 	); err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload movie cover",
-			Status: 500,
-			Detail: "Failed to execute SQL insert statement.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Error(*functionId, fmt.Sprintf("Failed to upload cover information to the database. %v", err))
-		return &errorResponse
+		return problems.Wrap(problems.UploadFailed, err, "Failed to execute SQL insert statement.")
 	}
 
 	log.Info(*functionId, "Creating file in file system")
@@ -103,16 +79,8 @@ func Cover(
 	uploadPath = path.Join(*uploadDirectory, cover.FileName)
 	out, err := os.Create(uploadPath)
 	if err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload series cover",
-			Status: 500,
-			Detail: "Failed to create file in storage system.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Error(*functionId, fmt.Sprintf("Failed to create file in file system. %v", err))
-		return &errorResponse
+		return problems.Wrap(problems.UploadFailed, err, "Failed to create file in storage system.")
 	}
 
 	defer out.Close()
@@ -122,32 +90,16 @@ func Cover(
 	// Opens the file header to return the actual file.
 	file, err := uploadedFile.Open()
 	if err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload movie cover",
-			Status: 500,
-			Detail: "Failed to open uploaded file.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Error(*functionId, fmt.Sprintf("Failed to open uploaded file. %v", err))
-		return &errorResponse
+		return problems.Wrap(problems.UploadFailed, err, "Failed to open uploaded file.")
 	}
 
 	log.Info(*functionId, "Attempting to store file in file system.")
 
 	// Copy the file contents to newly created file.
 	if _, err := io.Copy(out, file); err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload cover",
-			Status: 500,
-			Detail: "Failed to copy file contents to newly created file.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Error(*functionId, fmt.Sprintf("Failed to store file in file system. %v", err))
-		return &errorResponse
+		return problems.Wrap(problems.UploadFailed, err, "Failed to copy file contents to newly created file.")
 	}
 
 	return nil
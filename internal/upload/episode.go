@@ -12,14 +12,14 @@ import (
 	"time"
 
 	"github.com/andrewdotjs/watchify-server/internal/logger"
-	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
 	"github.com/andrewdotjs/watchify-server/internal/types"
 	"github.com/google/uuid"
 )
 
 // This takes a episode file and stores it in the file system and database.
 //
-// This function does not return anything.
+// This function returns the problem to send to the client if the upload fails.
 func Episode(
   uploadedFile *multipart.FileHeader,
   episode *types.Episode,
@@ -27,7 +27,7 @@ func Episode(
   uploadDirectory *string,
   log *logger.Logger,
   functionId *string,
-) *responses.Error {
+) error {
 	var currentDateTime string = time.Now().Format("2006-01-02 15:04:05")
 	var splitFileName []string = strings.Split(uploadedFile.Filename, ".")
 	var uploadPath string
@@ -48,17 +48,8 @@ func Episode(
 		log.Info(*functionId, fmt.Sprintf("Could not find upload directory at '%s'. Creating one.", *uploadDirectory))
 
 		if err = os.Mkdir(*uploadDirectory, os.FileMode(0777)); err != nil {
-			var errorResponse responses.Error = responses.Error{
-				Type:   "null",
-				Title:  "Failure to upload episode",
-				Status: 500,
-				Detail: "Failed to create a new upload directory when needed.",
-				// Add URL endpoint as instance.
-			}
-
 			log.Info(*functionId, fmt.Sprintf("Failed to create a new upload directory when needed. %v", err))
-
-			return &errorResponse
+			return problems.Wrap(problems.UploadFailed, err, "Failed to create a new upload directory when needed.")
 		}
 	}
 
@@ -70,17 +61,23 @@ func Episode(
 		episode.EpisodeNumber = number
 	}
 
+	// Episodes uploaded without a season belong to the first one.
+	if episode.SeasonNumber < 1 {
+		episode.SeasonNumber = 1
+	}
+
 	log.Info(*functionId, "Executing insert statement into the database")
 
 	// Insert the new episode's data into the series_episodes table.
 	if _, err = database.Exec(`
 	  INSERT INTO
-			episodes
+			episodes (id, parent_id, season_number, episode_number, title, description, file_name, file_extension, upload_date, last_modified)
 		VALUES
-		  (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`,
 		episode.Id,
 		episode.ParentId,
+		episode.SeasonNumber,
 		episode.EpisodeNumber,
 		nil,
 		nil,
@@ -89,17 +86,8 @@ func Episode(
 		episode.UploadDate,
 		episode.LastModified,
 	); err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload series episode",
-			Status: 500,
-			Detail: "Failed to execute SQL insert statement.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Info(*functionId, fmt.Sprintf("Failed to execute SQL insert statement. %v", err))
-
-		return &errorResponse
+		return problems.Wrap(problems.UploadFailed, err, "Failed to execute SQL insert statement.")
 	}
 
 	log.Info(*functionId, "Creating file in storage system.")
@@ -108,16 +96,8 @@ func Episode(
 	uploadPath = path.Join(*uploadDirectory, episode.FileName)
 	out, err := os.Create(uploadPath)
 	if err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload series episode",
-			Status: 500,
-			Detail: "Failed to create file in storage system.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Info(*functionId, fmt.Sprintf("Failed to create file in storage system. %v", err))
-		return &errorResponse
+		return problems.Wrap(problems.UploadFailed, err, "Failed to create file in storage system.")
 	}
 
 	defer out.Close()
@@ -127,34 +107,16 @@ func Episode(
 	// Open uploaded file so that it's ready to be copied.
 	file, err := uploadedFile.Open()
 	if err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload series episode",
-			Status: 500,
-			Detail: "Failed to open uploaded file.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Info(*functionId, fmt.Sprintf("Failed to open uploaded file. %v", err))
-
-		return &errorResponse
+		return problems.Wrap(problems.UploadFailed, err, "Failed to open uploaded file.")
 	}
 
 	log.Info(*functionId, "Copying file contents to newly created file in storage system.")
 
 	// Copy the file to the destination and error handle.
 	if _, err := io.Copy(out, file); err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload series episode",
-			Status: 500,
-			Detail: "Failed to copy file contents to newly created file.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Info(*functionId, fmt.Sprintf("Failed to copy file contents to newly created file. %v", err))
-
-		return &errorResponse
+		return problems.Wrap(problems.UploadFailed, err, "Failed to copy file contents to newly created file.")
 	}
 
 	return nil
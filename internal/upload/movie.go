@@ -11,7 +11,7 @@ import (
 	"time"
 
 	"github.com/andrewdotjs/watchify-server/internal/logger"
-	"github.com/andrewdotjs/watchify-server/internal/responses"
+	"github.com/andrewdotjs/watchify-server/internal/problems"
 	"github.com/andrewdotjs/watchify-server/internal/types"
 	"github.com/google/uuid"
 )
@@ -23,7 +23,7 @@ func Movie(
   uploadDirectory *string,
   log *logger.Logger,
   functionId *string,
-) (*string, *responses.Error) {
+) (*string, error) {
 	var currentTime string = time.Now().Format("01-02-2006 15:04:05")
 	var uploadPath string
 	var err error
@@ -43,16 +43,8 @@ func Movie(
 	  log.Error(*functionId, fmt.Sprintf("Could not find upload directory at %s. Creating one", *uploadDirectory))
 
 		if err = os.Mkdir(*uploadDirectory, os.FileMode(0777)); err != nil {
-			var errorResponse responses.Error = responses.Error{
-				Type:   "null",
-				Title:  "Failure to upload movie",
-				Status: 500,
-				Detail: "Failed to create upload directory when needed.",
-				// Add URL endpoint as instance.
-			}
-
 			log.Error(*functionId, fmt.Sprintf("Failed to create upload directory when needed. %v", err))
-			return nil, &errorResponse
+			return nil, problems.Wrap(problems.UploadFailed, err, "Failed to create upload directory when needed.")
 		}
 	}
 
@@ -62,7 +54,7 @@ func Movie(
 	if _, err = database.Exec(
 	  `
 			INSERT INTO
-			  movies
+			  movies (id, title, description, hidden, file_extension, file_name, upload_date, last_modified)
 			VALUES
 			  (?, ?, ?, ?, ?, ?, ?, ?)
 		`,
@@ -75,16 +67,8 @@ func Movie(
 		movie.UploadDate,
 		movie.LastModified,
 	); err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload movie",
-			Status: 500,
-			Detail: "Failed to execute SQL insert statement.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Error(*functionId, fmt.Sprintf("Insert failed. %v", err))
-		return nil, &errorResponse
+		return nil, problems.Wrap(problems.UploadFailed, err, "Failed to execute SQL insert statement.")
 	}
 
 	log.Info(*functionId, "Creating file in file system")
@@ -93,16 +77,8 @@ func Movie(
 	uploadPath = path.Join(*uploadDirectory, movie.FileName)
 	out, err := os.Create(uploadPath)
 	if err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload movie",
-			Status: 500,
-			Detail: "Failed to create file in storage system.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Error(*functionId, fmt.Sprintf("Failed to create file in file system. %v", err))
-		return nil, &errorResponse
+		return nil, problems.Wrap(problems.UploadFailed, err, "Failed to create file in storage system.")
 	}
 
 	defer out.Close()
@@ -112,32 +88,16 @@ func Movie(
 	// Open uploaded file so that it's ready to be copied.
 	file, err := uploadedFile.Open()
 	if err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload movie",
-			Status: 500,
-			Detail: "Failed to open uploaded file.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Error(*functionId, fmt.Sprintf("Failed to open uploaded file. %v", err))
-		return nil, &errorResponse
+		return nil, problems.Wrap(problems.UploadFailed, err, "Failed to open uploaded file.")
 	}
 
 	log.Info(*functionId, "Attempting to store uploaded file into file system")
 
 	// Copy the file to the destination and error handle.
 	if _, err := io.Copy(out, file); err != nil {
-		var errorResponse responses.Error = responses.Error{
-			Type:   "null",
-			Title:  "Failure to upload movie",
-			Status: 500,
-			Detail: "Failed to copy file contents to newly created file.",
-			// Add URL endpoint as instance.
-		}
-
 		log.Error(*functionId, fmt.Sprintf("Failed to store uploaded file into file system. %v", err))
-		return nil, &errorResponse
+		return nil, problems.Wrap(problems.UploadFailed, err, "Failed to copy file contents to newly created file.")
 	}
 
 	return &movie.Id, nil
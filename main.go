@@ -10,10 +10,12 @@ import (
 	"time"
 
 	"github.com/andrewdotjs/watchify-server/internal/database"
-	"github.com/andrewdotjs/watchify-server/internal/handlers"
 	"github.com/andrewdotjs/watchify-server/internal/logger"
-	"github.com/andrewdotjs/watchify-server/internal/middleware"
+	"github.com/andrewdotjs/watchify-server/internal/oidc"
+	"github.com/andrewdotjs/watchify-server/internal/queries"
+	"github.com/andrewdotjs/watchify-server/internal/recommend"
 	"github.com/andrewdotjs/watchify-server/internal/server"
+	"github.com/andrewdotjs/watchify-server/internal/suggest"
 	"github.com/google/uuid"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -36,18 +38,21 @@ func main() {
 	db := database.Initialize(&log, &appDirectory)
 	log.Info(functionId, "Database initialized")
 
-	mux := http.NewServeMux()
+	// Suggestions are served from memory and refreshed on every library change.
+	index := suggest.New(db)
+	index.Refresh(&log)
 
-	handlers.Shows(mux, db, &appDirectory, &log)
-	handlers.Movies(mux, db, &appDirectory, &log)
-	handlers.Stream(mux, db, &appDirectory, &log)
-	handlers.Videos(mux, db, &appDirectory, &log)
+	// Recommendations are recomputed in the background.
+	engine := recommend.New(db)
+	engine.Start(func() time.Duration { return queries.RecommendationInterval(db) }, &log)
 
-	// Middleware
-	muxHandler := middleware.LogEndpoint(mux, &log ,&functionId)
-	muxHandler = middleware.CORS(muxHandler)
+	// Checks routes against the OpenAPI document when set to "log", and also
+	// rejects divergences when set to "strict".
+	validation := os.Getenv("WATCHIFY_OPENAPI_VALIDATION")
 
-	server := &http.Server{
+	muxHandler := server.Handler(db, &appDirectory, index, engine, oidc.New(oidc.FromEnvironment()), validation, &log, &functionId)
+
+	httpServer := &http.Server{
 		Addr:         "0.0.0.0:" + strconv.Itoa(PORT),
 		WriteTimeout: 15 * time.Minute,
 		ReadTimeout:  15 * time.Minute,
@@ -57,7 +62,7 @@ func main() {
 
 	// Run in goroutine to not interrupt graceful shutdown procedure.
 	go func() {
-		if err := server.ListenAndServe(); err == http.ErrServerClosed {
+		if err := httpServer.ListenAndServe(); err == http.ErrServerClosed {
 			fmt.Println("")
 			log.Info(functionId, "Starting shutdown procedure")
 		} else if err != nil {
@@ -72,7 +77,7 @@ func main() {
 	<-c
 
 	defer db.Close()
-	server.Shutdown(context.Background())
+	httpServer.Shutdown(context.Background())
 
 	log.Info(functionId, "Shutting down...")
 	os.Exit(0)